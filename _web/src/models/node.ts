@@ -1,6 +1,14 @@
 import { endpoint } from "./nodes/endpoint";
+import { emailTrigger } from "./nodes/emailTrigger";
+import { consume } from "./nodes/consume";
 import { template } from "./nodes/template";
 import { request } from "./nodes/request";
+import { graphql } from "./nodes/graphql";
+import { grpc } from "./nodes/grpc";
+import { soap } from "./nodes/soap";
+import { publish } from "./nodes/publish";
+import { sql } from "./nodes/sql";
+import { kvGet, kvSet, kvDelete, kvIncrement, kvCompareAndSwap } from "./nodes/kv";
 import { script } from "./nodes/script";
 import { forLoop } from "./nodes/forLoop";
 import { ifCase } from "./nodes/ifCase";
@@ -11,6 +19,9 @@ import { email } from "./nodes/email";
 import { note } from "./nodes/note";
 import { wait } from "./nodes/wait";
 import { hub } from "./nodes/hub";
+import { delay } from "./nodes/delay";
+import { approval } from "./nodes/approval";
+import { extract } from "./nodes/extract";
 
 export type node = {
   name: string
@@ -25,8 +36,20 @@ export type node = {
 
 export const nodes = {
   endpoint,
+  emailTrigger,
+  consume,
   template,
   request,
+  graphql,
+  grpc,
+  soap,
+  publish,
+  sql,
+  kvGet,
+  kvSet,
+  kvDelete,
+  kvIncrement,
+  kvCompareAndSwap,
   script,
   forLoop,
   ifCase,
@@ -37,4 +60,7 @@ export const nodes = {
   note,
   wait,
   hub,
+  delay,
+  approval,
+  extract,
 } as Record<string, node>;
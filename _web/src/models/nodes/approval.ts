@@ -0,0 +1,29 @@
+import type { node } from "@/models/node";
+
+export type approvalData = {
+  info: string
+  groups: string
+  timeout: string
+  tags: string
+};
+
+export const approval: node = {
+  name: "approval",
+  html: `
+  <div>
+    <div class="title-box">Approval</div>
+    <div class="box">
+      <input type="text" placeholder="info" name="info" readonly disabled df-info>
+    </div>
+  </div>
+  `,
+  data: {
+    info: "",
+    groups: "",
+    timeout: "",
+    tags: "",
+  },
+  input: 1,
+  output: 4,
+  class: "node-approval",
+};
@@ -0,0 +1,41 @@
+import type { node } from "@/models/node";
+
+export type consumeData = {
+  endpoint: string
+  profile: string
+  topic: string
+  group: string
+  concurrency: string
+  attempts: string
+  retry_delay: string
+  dead_letter: string
+  max_deliveries: string
+  tags: string
+};
+
+export const consume: node = {
+  name: "consume",
+  html: `
+  <div>
+    <div class="title-box">Consume</div>
+    <div class="box">
+      <input type="text" placeholder="orders" name="info" readonly disabled df-endpoint>
+    </div>
+  </div>
+  `,
+  data: {
+    endpoint: "",
+    profile: "",
+    topic: "",
+    group: "",
+    concurrency: "",
+    attempts: "",
+    retry_delay: "",
+    dead_letter: "",
+    max_deliveries: "",
+    tags: "",
+  } as consumeData,
+  input: 0,
+  output: 1,
+  class: "node-consume",
+};
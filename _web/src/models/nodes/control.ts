@@ -5,6 +5,8 @@ export type controlData = {
   control: string
   endpoint: string
   method: string
+  mode: string
+  timeout: string
   tags: string
 };
 
@@ -23,6 +25,8 @@ export const control: node = {
     control: "",
     endpoint: "",
     method: "POST",
+    mode: "sync",
+    timeout: "",
     tags: "",
   },
   input: 1,
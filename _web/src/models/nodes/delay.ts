@@ -0,0 +1,29 @@
+import type { node } from "@/models/node";
+
+export type delayData = {
+  info: string
+  duration: string
+  until: string
+  tags: string
+};
+
+export const delay: node = {
+  name: "delay",
+  html: `
+  <div>
+    <div class="title-box">Delay</div>
+    <div class="box">
+      <input type="text" placeholder="info" name="info" readonly disabled df-info>
+    </div>
+  </div>
+  `,
+  data: {
+    info: "",
+    duration: "",
+    until: "",
+    tags: "",
+  },
+  input: 1,
+  output: 1,
+  class: "node-delay",
+};
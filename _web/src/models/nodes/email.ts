@@ -1,11 +1,25 @@
 import type { node } from "@/models/node";
 
 export type emailData = {
+  profile: string
   from: string
   to: string
   cc: string
   bcc: string
   subject: string
+  reply_to: string
+  body: string
+  body_template: string
+  text: string
+  headers: string
+  priority: string
+  inline: string
+  attachments: string
+  attachments_template: string
+  attach_input: boolean
+  attach_filename: string
+  attach_content_type: string
+  attachments_max_size: string
   tags: string
 };
 
@@ -17,11 +31,25 @@ export const email: node = {
   </div>
   `,
   data: {
+    profile: "",
     from: "",
     to: "",
     cc: "",
     bcc: "",
     subject: "",
+    reply_to: "",
+    body: "",
+    body_template: "",
+    text: "",
+    headers: "",
+    priority: "",
+    inline: "",
+    attachments: "",
+    attachments_template: "",
+    attach_input: false,
+    attach_filename: "",
+    attach_content_type: "",
+    attachments_max_size: "",
     tags: "",
   },
   input: 2,
@@ -0,0 +1,37 @@
+import type { node } from "@/models/node";
+
+export type emailTriggerData = {
+  endpoint: string
+  profile: string
+  mailbox: string
+  interval: string
+  limit: string
+  action: string
+  folder: string
+  tags: string
+};
+
+export const emailTrigger: node = {
+  name: "emailTrigger",
+  html: `
+  <div>
+    <div class="title-box">Email Trigger</div>
+    <div class="box">
+      <input type="text" placeholder="invoice" name="info" readonly disabled df-endpoint>
+    </div>
+  </div>
+  `,
+  data: {
+    endpoint: "",
+    profile: "",
+    mailbox: "INBOX",
+    interval: "1m",
+    limit: "",
+    action: "seen",
+    folder: "",
+    tags: "",
+  } as emailTriggerData,
+  input: 0,
+  output: 1,
+  class: "node-emailTrigger",
+};
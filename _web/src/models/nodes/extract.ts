@@ -0,0 +1,29 @@
+import type { node } from "@/models/node";
+
+export type extractData = {
+  info: string
+  type: string
+  expression: string
+  tags: string
+};
+
+export const extract: node = {
+  name: "extract",
+  html: `
+  <div>
+    <div class="title-box">Extract</div>
+    <div class="box">
+      <input type="text" placeholder="info" name="info" readonly disabled df-info>
+    </div>
+  </div>
+  `,
+  data: {
+    info: "",
+    type: "jq",
+    expression: "",
+    tags: "",
+  },
+  input: 1,
+  output: 1,
+  class: "node-extract",
+};
@@ -0,0 +1,63 @@
+import type { node } from "@/models/node";
+
+export type graphqlData = {
+  info: string,
+  skip_verify: boolean,
+  retry_disabled: boolean,
+  oauth2: string,
+  tls: string,
+  proxy: string,
+  url: string,
+  auth: string,
+  headers: string,
+  query: string,
+  template: string,
+  variables: string,
+  operation_name: string,
+  timeout: string,
+  connect_timeout: string,
+  max_response_bytes: string,
+  breaker: boolean,
+  breaker_name: string,
+  extract_type: string,
+  extract: string,
+  retry_codes: string,
+  retry_decodes: string,
+  tags: string
+};
+
+export const graphql: node = {
+  name: "graphql",
+  html: `
+  <div>
+    <div class="title-box">GraphQL</div>
+    <div class="box">
+      <input type="text" placeholder="info" name="info" readonly disabled df-info>
+    </div>
+  </div>
+  `,
+  data: {
+    info: "",
+    skip_verify: false,
+    url: "",
+    auth: "",
+    headers: "",
+    query: "",
+    template: "",
+    variables: "",
+    operation_name: "",
+    timeout: "",
+    connect_timeout: "",
+    max_response_bytes: "",
+    breaker: false,
+    breaker_name: "",
+    extract_type: "jq",
+    extract: "",
+    retry_codes: "",
+    retry_decodes: "",
+    tags: "",
+  } as graphqlData,
+  input: 2,
+  output: 3,
+  class: "node-graphql",
+};
@@ -0,0 +1,41 @@
+import type { node } from "@/models/node";
+
+export type grpcData = {
+  info: string,
+  target: string,
+  method: string,
+  proto: string,
+  metadata: string,
+  tls: string,
+  plaintext: boolean,
+  skip_verify: boolean,
+  timeout: string,
+  tags: string
+};
+
+export const grpc: node = {
+  name: "grpc",
+  html: `
+  <div>
+    <div class="title-box">gRPC</div>
+    <div class="box">
+      <input type="text" placeholder="info" name="info" readonly disabled df-info>
+    </div>
+  </div>
+  `,
+  data: {
+    info: "",
+    target: "",
+    method: "",
+    proto: "",
+    metadata: "",
+    tls: "",
+    plaintext: false,
+    skip_verify: false,
+    timeout: "",
+    tags: "",
+  } as grpcData,
+  input: 1,
+  output: 3,
+  class: "node-grpc",
+};
@@ -0,0 +1,43 @@
+import type { node } from "@/models/node";
+
+export type kvData = {
+  info: string,
+  namespace: string,
+  key: string,
+  value: string,
+  expected: string,
+  delta: string,
+  ttl: string,
+  tags: string
+};
+
+const kvNode = (name: string, title: string, output: number): node => ({
+  name: name,
+  html: `
+  <div>
+    <div class="title-box">${title}</div>
+    <div class="box">
+      <input type="text" placeholder="info" name="info" readonly disabled df-info>
+    </div>
+  </div>
+  `,
+  data: {
+    info: "",
+    namespace: "",
+    key: "",
+    value: "",
+    expected: "",
+    delta: "",
+    ttl: "",
+    tags: "",
+  } as kvData,
+  input: 1,
+  output: output,
+  class: `node-kv node-${name}`,
+});
+
+export const kvGet = kvNode("kvGet", "KV Get", 2);
+export const kvSet = kvNode("kvSet", "KV Set", 1);
+export const kvDelete = kvNode("kvDelete", "KV Delete", 1);
+export const kvIncrement = kvNode("kvIncrement", "KV Increment", 1);
+export const kvCompareAndSwap = kvNode("kvCompareAndSwap", "KV Compare and Swap", 2);
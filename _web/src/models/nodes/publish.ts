@@ -0,0 +1,35 @@
+import type { node } from "@/models/node";
+
+export type publishData = {
+  info: string,
+  profile: string,
+  topic: string,
+  key: string,
+  headers: string,
+  timeout: string,
+  tags: string
+};
+
+export const publish: node = {
+  name: "publish",
+  html: `
+  <div>
+    <div class="title-box">Publish</div>
+    <div class="box">
+      <input type="text" placeholder="info" name="info" readonly disabled df-info>
+    </div>
+  </div>
+  `,
+  data: {
+    info: "",
+    profile: "",
+    topic: "",
+    key: "",
+    headers: "",
+    timeout: "",
+    tags: "",
+  } as publishData,
+  input: 1,
+  output: 2,
+  class: "node-publish",
+};
@@ -6,11 +6,34 @@ export type requestData = {
   payload_nil: boolean,
   retry_disabled: boolean,
   oauth2: string,
+  tls: string,
   proxy: string,
   url: string,
   method: string,
   auth: string,
   headers: string,
+  body_mode: string,
+  multipart: string,
+  timeout: string,
+  connect_timeout: string,
+  max_response_bytes: string,
+  breaker: boolean,
+  breaker_name: string,
+  cache: boolean,
+  cache_ttl: string,
+  cache_headers: string,
+  cache_control: boolean,
+  extract_type: string,
+  extract: string,
+  pagination: string,
+  pagination_items: string,
+  pagination_next: string,
+  pagination_param: string,
+  pagination_limit_param: string,
+  pagination_limit: string,
+  pagination_start: string,
+  pagination_max_pages: string,
+  pagination_emit: string,
   retry_codes: string,
   retry_decodes: string,
   tags: string
@@ -34,6 +57,28 @@ export const request: node = {
     method: "",
     auth: "",
     headers: "",
+    body_mode: "raw",
+    multipart: "",
+    timeout: "",
+    connect_timeout: "",
+    max_response_bytes: "",
+    breaker: false,
+    breaker_name: "",
+    cache: false,
+    cache_ttl: "",
+    cache_headers: "",
+    cache_control: false,
+    extract_type: "jq",
+    extract: "",
+    pagination: "",
+    pagination_items: "",
+    pagination_next: "",
+    pagination_param: "",
+    pagination_limit_param: "",
+    pagination_limit: "",
+    pagination_start: "",
+    pagination_max_pages: "",
+    pagination_emit: "aggregate",
     retry_codes: "",
     retry_decodes: "",
     tags: "",
@@ -0,0 +1,63 @@
+import type { node } from "@/models/node";
+
+export type soapData = {
+  info: string,
+  skip_verify: boolean,
+  retry_disabled: boolean,
+  oauth2: string,
+  tls: string,
+  proxy: string,
+  url: string,
+  auth: string,
+  headers: string,
+  envelope: string,
+  template: string,
+  soap_action: string,
+  soap_version: string,
+  timeout: string,
+  connect_timeout: string,
+  max_response_bytes: string,
+  breaker: boolean,
+  breaker_name: string,
+  extract_type: string,
+  extract: string,
+  retry_codes: string,
+  retry_decodes: string,
+  tags: string
+};
+
+export const soap: node = {
+  name: "soap",
+  html: `
+  <div>
+    <div class="title-box">SOAP</div>
+    <div class="box">
+      <input type="text" placeholder="info" name="info" readonly disabled df-info>
+    </div>
+  </div>
+  `,
+  data: {
+    info: "",
+    skip_verify: false,
+    url: "",
+    auth: "",
+    headers: "",
+    envelope: "",
+    template: "",
+    soap_action: "",
+    soap_version: "1.1",
+    timeout: "",
+    connect_timeout: "",
+    max_response_bytes: "",
+    breaker: false,
+    breaker_name: "",
+    extract_type: "jq",
+    extract: "",
+    retry_codes: "",
+    retry_decodes: "",
+    tags: "",
+  } as soapData,
+  input: 2,
+  output: 3,
+  class: "node-soap",
+};
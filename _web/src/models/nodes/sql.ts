@@ -0,0 +1,39 @@
+import type { node } from "@/models/node";
+
+export type sqlData = {
+  info: string,
+  profile: string,
+  query: string,
+  mode: string,
+  params: string,
+  transaction: boolean,
+  read_only: boolean,
+  timeout: string,
+  tags: string
+};
+
+export const sql: node = {
+  name: "sql",
+  html: `
+  <div>
+    <div class="title-box">SQL</div>
+    <div class="box">
+      <input type="text" placeholder="info" name="info" readonly disabled df-info>
+    </div>
+  </div>
+  `,
+  data: {
+    info: "",
+    profile: "",
+    query: "",
+    mode: "",
+    params: "",
+    transaction: false,
+    read_only: false,
+    timeout: "",
+    tags: "",
+  } as sqlData,
+  input: 1,
+  output: 2,
+  class: "node-sql",
+};
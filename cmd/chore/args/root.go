@@ -9,11 +9,15 @@ import (
 	"github.com/worldline-go/chore/internal/config"
 	"github.com/worldline-go/chore/internal/server"
 	"github.com/worldline-go/chore/internal/store"
+	"github.com/worldline-go/chore/pkg/flow"
+	"github.com/worldline-go/chore/pkg/kv"
+	"github.com/worldline-go/chore/pkg/registry"
+	"github.com/worldline-go/chore/pkg/request"
 	"github.com/worldline-go/initializer"
 	"github.com/worldline-go/tell"
 
-	// Add flow nodes to register in control flow algorithm.
-	_ "github.com/worldline-go/chore/pkg/flow/nodes"
+	// Add flow nodes to register in control flow algorithm and start triggers.
+	"github.com/worldline-go/chore/pkg/flow/nodes"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -192,6 +196,37 @@ func runRoot(ctx context.Context) error {
 		return err //nolint:wrapcheck // no need
 	}
 
+	flow.Durable = flow.DurableSettings(config.Application.Durable)
+	if err := flow.Durable.Validate(); err != nil {
+		return err //nolint:wrapcheck // clear error
+	}
+
+	flow.Calls = flow.CallSettings(config.Application.Call)
+
+	request.GlobalBreakers.Settings = request.BreakerSettings(config.Application.Breaker)
+	if err := request.GlobalBreakers.Settings.Validate(); err != nil {
+		return err //nolint:wrapcheck // clear error
+	}
+
+	switch config.Application.Cache.Backend {
+	case "", "memory":
+		if config.Application.Cache.MaxEntries < 0 {
+			return fmt.Errorf("cache max_entries should not be negative, got %d", config.Application.Cache.MaxEntries)
+		}
+
+		request.GlobalCache = request.NewMemoryCache(config.Application.Cache.MaxEntries)
+	case "postgres":
+		request.GlobalCache = request.NewDBCache(dbConn)
+	default:
+		return fmt.Errorf("cache backend %q not supported", config.Application.Cache.Backend)
+	}
+
+	request.StartCacheClean(ctx, wg, config.Application.Cache.CleanInterval)
+	kv.StartClean(ctx, wg, dbConn, config.Application.KV.CleanInterval)
+	flow.StartDurable(ctx, wg, registry.Reg)
+	nodes.StartEmailTriggers(ctx, wg, registry.Reg)
+	nodes.StartConsumers(ctx, wg, registry.Reg)
+
 	initializer.Shutdown.Add(func() error {
 		return server.Stop(e)
 	}, initializer.WithShutdownName("server"))
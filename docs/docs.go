@@ -15,6 +15,210 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/approval": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get one approval with id, value is base64 format",
+                "tags": [
+                    "approval"
+                ],
+                "summary": "Get approval",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "get by id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/apimodels.Data"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.ApprovalPureID"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Approve or reject a pending approval, only users in approval groups can decide",
+                "tags": [
+                    "approval"
+                ],
+                "summary": "Decide approval",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "approval id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "approve or reject",
+                        "name": "decision",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "optional comment",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApprovalDecision"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/approvals": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get list of the approvals",
+                "tags": [
+                    "approval"
+                ],
+                "summary": "List approvals",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "set the limit, default is 20",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "set the offset, default is 0",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by status (pending, approved, rejected, timeout)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/apimodels.DataMeta"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/api.ApprovalPureID"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/apimodels.Meta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    }
+                }
+            }
+        },
         "/auth": {
             "get": {
                 "security": [
@@ -373,6 +577,83 @@ const docTemplate = `{
                 }
             }
         },
+        "/breaker/reset": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Close the circuit breaker with name",
+                "tags": [
+                    "breaker"
+                ],
+                "summary": "Reset circuit breaker",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "breaker name",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/breakers": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get states of request node circuit breakers",
+                "tags": [
+                    "breaker"
+                ],
+                "summary": "List circuit breakers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/apimodels.Data"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/request.BreakerState"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
         "/control": {
             "get": {
                 "security": [
@@ -816,30 +1097,655 @@ const docTemplate = `{
                 }
             }
         },
-        "/login": {
+        "/kv": {
             "get": {
                 "security": [
                     {
-                        "BasicAuth": []
+                        "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get JWT token for 1 hour",
+                "description": "Get one not expired key of key-value store",
                 "tags": [
-                    "public"
+                    "kv"
                 ],
-                "summary": "Login with basic auth",
+                "summary": "Get key",
                 "parameters": [
                     {
-                        "type": "boolean",
+                        "type": "string",
+                        "description": "namespace of the key",
+                        "name": "namespace",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/apimodels.Data"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.KV"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Set value of the key, ttl is a duration like 10m",
+                "tags": [
+                    "kv"
+                ],
+                "summary": "New or Update key",
+                "parameters": [
+                    {
+                        "description": "send key object",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.KVBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create key if not exist or expired, ttl is a duration like 10m",
+                "tags": [
+                    "kv"
+                ],
+                "summary": "New key",
+                "parameters": [
+                    {
+                        "description": "send key object",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.KVBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete key of key-value store",
+                "tags": [
+                    "kv"
+                ],
+                "summary": "Delete key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "namespace of the key",
+                        "name": "namespace",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/kvs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get list of not expired keys in key-value store",
+                "tags": [
+                    "kv"
+                ],
+                "summary": "List keys",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "filter by namespace",
+                        "name": "namespace",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "set the limit, default is 20",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "set the offset, default is 0",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search key prefix",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/apimodels.DataMeta"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.KV"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/apimodels.Meta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get JWT token for 1 hour",
+                "tags": [
+                    "public"
+                ],
+                "summary": "Login with basic auth",
+                "parameters": [
+                    {
+                        "type": "boolean",
                         "description": "raw token output",
                         "name": "raw",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "oauth2 provider name",
-                        "name": "provider",
-                        "in": "query"
+                        "type": "string",
+                        "description": "oauth2 provider name",
+                        "name": "provider",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/apimodels.Data"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.LoginToken"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Get JWT token for 1 hour",
+                "tags": [
+                    "public"
+                ],
+                "summary": "Login",
+                "parameters": [
+                    {
+                        "description": "send login object",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginModel"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/apimodels.Data"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.LoginToken"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth2/refresh": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Force to get new token of the cached oauth2 client",
+                "tags": [
+                    "oauth2"
+                ],
+                "summary": "Refresh OAuth2 token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "client key from status",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/apimodels.Data"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/request.ServiceStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth2/status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get cached oauth2 clients with token expiry and last error",
+                "tags": [
+                    "oauth2"
+                ],
+                "summary": "OAuth2 status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/apimodels.Data"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/request.ServiceStatus"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/ping": {
+            "get": {
+                "description": "Check server is active",
+                "tags": [
+                    "public"
+                ],
+                "summary": "Ping server",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/proto": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get one file descriptor set with id or name",
+                "tags": [
+                    "proto"
+                ],
+                "summary": "Get proto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "get by id",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "get by name",
+                        "name": "name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/apimodels.Data"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.ProtoPureID"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Send and record file descriptor set, content is base64 of protoc --descriptor_set_out",
+                "tags": [
+                    "proto"
+                ],
+                "summary": "New or Update proto",
+                "parameters": [
+                    {
+                        "description": "send proto object",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProtoPureID"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Send and record new file descriptor set, content is base64 of protoc --descriptor_set_out",
+                "tags": [
+                    "proto"
+                ],
+                "summary": "New proto",
+                "parameters": [
+                    {
+                        "description": "send proto object",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProtoPure"
+                        }
                     }
                 ],
                 "responses": {
@@ -854,7 +1760,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/api.LoginToken"
+                                            "$ref": "#/definitions/apimodels.ID"
                                         }
                                     }
                                 }
@@ -867,12 +1773,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/apimodels.Error"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/apimodels.Error"
-                        }
-                    },
                     "409": {
                         "description": "Conflict",
                         "schema": {
@@ -887,21 +1787,81 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Get JWT token for 1 hour",
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete with id",
                 "tags": [
-                    "public"
+                    "proto"
                 ],
-                "summary": "Login",
+                "summary": "Delete proto",
                 "parameters": [
                     {
-                        "description": "send login object",
-                        "name": "payload",
-                        "in": "body",
+                        "type": "string",
+                        "description": "get by id",
+                        "name": "id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/api.LoginModel"
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
                         }
                     }
+                }
+            }
+        },
+        "/protos": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get list of the stored file descriptor sets",
+                "tags": [
+                    "proto"
+                ],
+                "summary": "List protos",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "set the limit, default is 20",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "set the offset, default is 0",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search item",
+                        "name": "search",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
@@ -909,13 +1869,19 @@ const docTemplate = `{
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/apimodels.Data"
+                                    "$ref": "#/definitions/apimodels.DataMeta"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/api.LoginToken"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/api.ProtoName"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/apimodels.Meta"
                                         }
                                     }
                                 }
@@ -928,18 +1894,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/apimodels.Error"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/apimodels.Error"
-                        }
-                    },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/apimodels.Error"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -949,16 +1903,63 @@ const docTemplate = `{
                 }
             }
         },
-        "/ping": {
+        "/run": {
             "get": {
-                "description": "Check server is active",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get one durable run with id",
                 "tags": [
-                    "public"
+                    "run"
+                ],
+                "summary": "Get run",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "get by id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Ping server",
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/apimodels.Data"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.RunPureID"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
                     }
                 }
             }
@@ -1062,12 +2063,96 @@ const docTemplate = `{
                             "$ref": "#/definitions/apimodels.Error"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/apimodels.Error"
-                        }
-                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/runs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get list of the durable runs",
+                "tags": [
+                    "run"
+                ],
+                "summary": "List runs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "set the limit, default is 20",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "set the offset, default is 0",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by status (running, suspended, completed, failed)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by control name",
+                        "name": "control",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by caller run id",
+                        "name": "parent_run_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/apimodels.DataMeta"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/api.RunPureID"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/apimodels.Meta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apimodels.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1238,7 +2323,7 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "get by namespace (email, oauth2)",
+                        "description": "get by namespace (email, imap, broker, database, oauth2, tls)",
                         "name": "namespace",
                         "in": "query",
                         "required": true
@@ -1285,7 +2370,7 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "get by namespace (email, oauth2)",
+                        "description": "get by namespace (email, imap, broker, database, oauth2, tls)",
                         "name": "namespace",
                         "in": "query",
                         "required": true
@@ -1327,7 +2412,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Replace with new data",
+                "description": "Replace with new data, email, imap, broker and database namespace data is validated",
                 "tags": [
                     "settings"
                 ],
@@ -1343,7 +2428,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "get by namespace (email, oauth2)",
+                        "description": "get by namespace (email, imap, broker, database, oauth2, tls)",
                         "name": "namespace",
                         "in": "query",
                         "required": true
@@ -2407,9 +3492,78 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "api.ApprovalPureID": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": "looks good"
+                },
+                "control": {
+                    "type": "string",
+                    "example": "deploy"
+                },
+                "decided_at": {
+                    "type": "string",
+                    "example": "2021-02-18T21:54:42.123Z"
+                },
+                "decided_by": {
+                    "type": "string",
+                    "example": "cf8a07d4-077e-402e-a46b-ac0ed50989ec"
+                },
+                "endpoint": {
+                    "type": "string",
+                    "example": "production"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2021-02-18T21:54:42.123Z"
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "admin",
+                        "group1"
+                    ]
+                },
+                "id": {
+                    "type": "string",
+                    "example": "cf8a07d4-077e-402e-a46b-ac0ed50989ec"
+                },
+                "node_id": {
+                    "type": "string",
+                    "example": "5"
+                },
+                "run_id": {
+                    "type": "string",
+                    "example": "cf8a07d4-077e-402e-a46b-ac0ed50989ec"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "value": {
+                    "type": "string",
+                    "format": "base64",
+                    "example": "aGVsbG8ge3submFtZX19Cg=="
+                }
+            }
+        },
         "api.AuthPureID": {
             "type": "object",
             "properties": {
+                "config": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "username": "chore"
+                    }
+                },
                 "data": {
                     "type": "string",
                     "example": "any data"
@@ -2440,6 +3594,10 @@ const docTemplate = `{
                 "name": {
                     "type": "string",
                     "example": "jira-deepcore"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "basic"
                 }
             }
         },
@@ -2517,6 +3675,24 @@ const docTemplate = `{
                 }
             }
         },
+        "api.KVBody": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "ttl": {
+                    "description": "TTL is duration like 10m, empty keeps the key until deleted.",
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
         "api.LoginModel": {
             "type": "object",
             "properties": {
@@ -2539,6 +3715,105 @@ const docTemplate = `{
                 }
             }
         },
+        "api.ProtoName": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "admin",
+                        "group1"
+                    ]
+                },
+                "id": {
+                    "type": "string",
+                    "example": "cf8a07d4-077e-402e-a46b-ac0ed50989ec"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ProtoPureID": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "format": "base64",
+                    "example": "CgtoZWxsby5wcm90bw=="
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "admin",
+                        "group1"
+                    ]
+                },
+                "id": {
+                    "type": "string",
+                    "example": "cf8a07d4-077e-402e-a46b-ac0ed50989ec"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "payments"
+                }
+            }
+        },
+        "api.RunPureID": {
+            "type": "object",
+            "properties": {
+                "control": {
+                    "type": "string",
+                    "example": "deploy"
+                },
+                "endpoint": {
+                    "type": "string",
+                    "example": "production"
+                },
+                "errors": {
+                    "type": "string",
+                    "example": "request cannot run"
+                },
+                "finished_at": {
+                    "type": "string",
+                    "example": "2021-02-18T21:54:42.123Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "cf8a07d4-077e-402e-a46b-ac0ed50989ec"
+                },
+                "locked_until": {
+                    "type": "string",
+                    "example": "2021-02-18T21:54:42.123Z"
+                },
+                "method": {
+                    "type": "string",
+                    "example": "POST"
+                },
+                "owner": {
+                    "type": "string",
+                    "example": "cf8a07d4-077e-402e-a46b-ac0ed50989ec"
+                },
+                "parent_run_id": {
+                    "type": "string",
+                    "example": "cf8a07d4-077e-402e-a46b-ac0ed50989ec"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "running"
+                },
+                "wake_at": {
+                    "type": "string",
+                    "example": "2021-02-18T21:54:42.123Z"
+                }
+            }
+        },
         "api.TemplatePureID": {
             "type": "object",
             "properties": {
@@ -2749,9 +4024,27 @@ const docTemplate = `{
                 }
             }
         },
+        "models.ApprovalDecision": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": "looks good"
+                }
+            }
+        },
         "models.AuthPure": {
             "type": "object",
             "properties": {
+                "config": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "username": "chore"
+                    }
+                },
                 "data": {
                     "type": "string",
                     "example": "any data"
@@ -2778,6 +4071,10 @@ const docTemplate = `{
                 "name": {
                     "type": "string",
                     "example": "jira-deepcore"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "basic"
                 }
             }
         },
@@ -2821,6 +4118,53 @@ const docTemplate = `{
                 }
             }
         },
+        "models.KV": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProtoPure": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "format": "base64",
+                    "example": "CgtoZWxsby5wcm90bw=="
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "admin",
+                        "group1"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "payments"
+                }
+            }
+        },
         "models.Settings": {
             "type": "object",
             "properties": {
@@ -2943,6 +4287,71 @@ const docTemplate = `{
                 }
             }
         },
+        "request.BreakerState": {
+            "type": "object",
+            "properties": {
+                "failures": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "open_until": {
+                    "type": "string"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "request.ServiceStatus": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "expiry": {
+                    "type": "string"
+                },
+                "grant_type": {
+                    "type": "string"
+                },
+                "has_token": {
+                    "type": "boolean"
+                },
+                "key": {
+                    "description": "Key is random id of the client in this instance, used to refresh.",
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_error_at": {
+                    "type": "string"
+                },
+                "last_refresh": {
+                    "type": "string"
+                },
+                "names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token_url": {
+                    "type": "string"
+                }
+            }
+        },
         "run.runModel": {
             "type": "object",
             "properties": {
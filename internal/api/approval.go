@@ -0,0 +1,252 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+
+	"github.com/worldline-go/auth/pkg/authecho"
+	"github.com/worldline-go/chore/internal/server/claims"
+	"github.com/worldline-go/chore/internal/server/middlewares"
+	"github.com/worldline-go/chore/internal/utils"
+	"github.com/worldline-go/chore/pkg/approval"
+	"github.com/worldline-go/chore/pkg/flow"
+	"github.com/worldline-go/chore/pkg/models"
+	"github.com/worldline-go/chore/pkg/models/apimodels"
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+var (
+	decisionApprove = "approve"
+	decisionReject  = "reject"
+)
+
+type ApprovalPureID struct {
+	models.ApprovalPure
+	apimodels.ID
+}
+
+// @Summary List approvals
+// @Tags approval
+// @Description Get list of the approvals
+// @Security ApiKeyAuth
+// @Router /approvals [get]
+// @Param limit query int false "set the limit, default is 20"
+// @Param offset query int false "set the offset, default is 0"
+// @Param status query string false "filter by status (pending, approved, rejected, timeout)"
+// @Success 200 {object} apimodels.DataMeta{data=[]ApprovalPureID{},meta=apimodels.Meta{}}
+// @failure 400 {object} apimodels.Error{}
+// @failure 500 {object} apimodels.Error{}
+func listApprovals(c echo.Context) error {
+	approvals := []ApprovalPureID{}
+
+	meta := &apimodels.Meta{Limit: apimodels.Limit}
+
+	if err := c.Bind(meta); err != nil {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: err.Error()})
+	}
+
+	status := c.QueryParam("status")
+
+	query := registry.Reg.DB.WithContext(c.Request().Context()).Model(&models.Approval{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	result := query.Order("created_at desc").Limit(meta.Limit).Offset(meta.Offset).Find(&approvals)
+
+	if result.Error != nil {
+		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: result.Error.Error()})
+	}
+
+	// get counts
+	query = registry.Reg.DB.WithContext(c.Request().Context()).Model(&models.Approval{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	query.Count(&meta.Count)
+
+	return c.JSON(http.StatusOK,
+		apimodels.DataMeta{
+			Meta: meta,
+			Data: apimodels.Data{Data: approvals},
+		},
+	)
+}
+
+// @Summary Get approval
+// @Tags approval
+// @Description Get one approval with id, value is base64 format
+// @Security ApiKeyAuth
+// @Router /approval [get]
+// @Param id query string true "get by id"
+// @Success 200 {object} apimodels.Data{data=ApprovalPureID{}}
+// @failure 400 {object} apimodels.Error{}
+// @failure 404 {object} apimodels.Error{}
+// @failure 500 {object} apimodels.Error{}
+func getApproval(c echo.Context) error {
+	id := c.QueryParam("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: apimodels.ErrRequiredID.Error()})
+	}
+
+	getData := new(ApprovalPureID)
+
+	result := registry.Reg.DB.WithContext(c.Request().Context()).Model(&models.Approval{}).Where("id = ?", id).First(getData)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return c.JSON(http.StatusNotFound, apimodels.Error{Error: result.Error.Error()})
+	}
+
+	if result.Error != nil {
+		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: result.Error.Error()})
+	}
+
+	return c.JSON(http.StatusOK,
+		apimodels.Data{
+			Data: getData,
+		},
+	)
+}
+
+// @Summary Decide approval
+// @Tags approval
+// @Description Approve or reject a pending approval, only users in approval groups can decide
+// @Security ApiKeyAuth
+// @Router /approval [post]
+// @Param id query string true "approval id"
+// @Param decision query string true "approve or reject"
+// @Param payload body models.ApprovalDecision{} false "optional comment"
+// @Success 204 "No Content"
+// @failure 400 {object} apimodels.Error{}
+// @failure 403 {object} apimodels.Error{}
+// @failure 404 {object} apimodels.Error{}
+// @failure 409 {object} apimodels.Error{}
+// @failure 500 {object} apimodels.Error{}
+func postApproval(c echo.Context) error {
+	id := c.QueryParam("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: apimodels.ErrRequiredID.Error()})
+	}
+
+	var status string
+
+	switch c.QueryParam("decision") {
+	case decisionApprove:
+		status = models.ApprovalApproved
+	case decisionReject:
+		status = models.ApprovalRejected
+	default:
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: "decision should be approve or reject"})
+	}
+
+	var body models.ApprovalDecision
+	if c.Request().ContentLength != 0 {
+		if err := c.Bind(&body); err != nil {
+			return c.JSON(http.StatusBadRequest, apimodels.Error{Error: err.Error()})
+		}
+	}
+
+	ctx := utils.Context(c)
+
+	record := models.ApprovalPure{}
+
+	result := registry.Reg.DB.WithContext(ctx).Model(&models.Approval{}).Where("id = ?", id).First(&record)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return c.JSON(http.StatusNotFound, apimodels.Error{Error: result.Error.Error()})
+	}
+
+	if result.Error != nil {
+		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: result.Error.Error()})
+	}
+
+	allowed, decidedBy, err := approvalDecider(c, record.Groups.Groups)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: err.Error()})
+	}
+
+	if !allowed {
+		return c.JSON(http.StatusForbidden, apimodels.Error{Error: "user not in approval groups"})
+	}
+
+	now := time.Now()
+
+	result = registry.Reg.DB.WithContext(ctx).Model(&models.Approval{}).
+		Where("id = ?", id).Where("status = ?", models.ApprovalPending).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"comment":    body.Comment,
+			"decided_by": decidedBy,
+			"decided_at": &now,
+		})
+
+	if result.Error != nil {
+		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: result.Error.Error()})
+	}
+
+	if result.RowsAffected == 0 {
+		return c.JSON(http.StatusConflict, apimodels.Error{Error: "approval already decided"})
+	}
+
+	approval.GlobalNotifier.Notify(id)
+
+	// suspended node in durable run
+	if record.RunID != "" && record.TaskID != "" {
+		if err := flow.Wake(ctx, registry.Reg.DB, record.RunID, record.TaskID); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("approval cannot wake run")
+		}
+	}
+
+	//nolint:wrapcheck // checking before
+	return c.NoContent(http.StatusNoContent)
+}
+
+// approvalDecider checks user has one of the groups, admin can always decide.
+// Approval without groups is decided only by admin.
+func approvalDecider(c echo.Context, groupsRaw []byte) (bool, string, error) {
+	// noop authetication skip
+	if v, ok := c.Get(authecho.KeyAuthNoop).(bool); ok && v {
+		return true, "", nil
+	}
+
+	claim, ok := c.Get(authecho.KeyClaims).(*claims.Custom)
+	if !ok {
+		return false, "", nil
+	}
+
+	decidedBy := claim.Subject
+	if claim.User != "" {
+		decidedBy = claim.User
+	}
+
+	var groups []string
+	if len(groupsRaw) != 0 {
+		if err := json.Unmarshal(groupsRaw, &groups); err != nil {
+			return false, "", err //nolint:wrapcheck // no need
+		}
+	}
+
+	if claim.HasRole(middlewares.AdminRoleKey) {
+		return true, decidedBy, nil
+	}
+
+	for _, group := range groups {
+		if claim.HasRole("chore_" + group) {
+			return true, decidedBy, nil
+		}
+	}
+
+	return false, decidedBy, nil
+}
+
+func Approval(e *echo.Group, authMiddleware echo.MiddlewareFunc) {
+	e.GET("/approvals", listApprovals, authMiddleware, middlewares.UserRole, middlewares.PatToken)
+	e.GET("/approval", getApproval, authMiddleware, middlewares.UserRole, middlewares.PatToken)
+	e.POST("/approval", postApproval, authMiddleware, middlewares.UserRole, middlewares.PatToken)
+}
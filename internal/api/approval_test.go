@@ -0,0 +1,144 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/labstack/echo/v4"
+	"github.com/worldline-go/auth/pkg/authecho"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	authclaims "github.com/worldline-go/auth/claims"
+	"github.com/worldline-go/chore/internal/server/claims"
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+func userClaims(user string, roles ...string) *claims.Custom {
+	roleSet := make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		roleSet[role] = struct{}{}
+	}
+
+	return &claims.Custom{Custom: authclaims.Custom{User: user, RoleSet: roleSet}}
+}
+
+func TestApprovalDecider(t *testing.T) {
+	tests := []struct {
+		name      string
+		claims    *claims.Custom
+		groups    string
+		allowed   bool
+		decidedBy string
+	}{
+		{
+			name:      "group member",
+			claims:    userClaims("alice", "chore_deploy"),
+			groups:    `["deploy"]`,
+			allowed:   true,
+			decidedBy: "alice",
+		},
+		{
+			name:      "not group member",
+			claims:    userClaims("bob", "chore_user"),
+			groups:    `["deploy"]`,
+			allowed:   false,
+			decidedBy: "bob",
+		},
+		{
+			name:      "empty groups user",
+			claims:    userClaims("bob", "chore_user"),
+			groups:    `[]`,
+			allowed:   false,
+			decidedBy: "bob",
+		},
+		{
+			name:      "empty groups admin",
+			claims:    userClaims("root", "chore_admin"),
+			allowed:   true,
+			decidedBy: "root",
+		},
+		{
+			name:    "without claims",
+			groups:  `["deploy"]`,
+			allowed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+			if tt.claims != nil {
+				c.Set(authecho.KeyClaims, tt.claims)
+			}
+
+			allowed, decidedBy, err := approvalDecider(c, []byte(tt.groups))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if allowed != tt.allowed || decidedBy != tt.decidedBy {
+				t.Errorf("approvalDecider() = %v, %q, want %v, %q", allowed, decidedBy, tt.allowed, tt.decidedBy)
+			}
+		})
+	}
+}
+
+func TestPostApproval(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(reg *registry.Registry) { registry.Reg = reg }(registry.Reg)
+	registry.Reg = &registry.Registry{DB: db}
+
+	approvalRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"status", "groups"}).AddRow("pending", []byte(`[]`))
+	}
+
+	post := func(claim *claims.Custom) int {
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/approval?id=1&decision=approve", nil), rec)
+		c.Set(authecho.KeyClaims, claim)
+
+		if err := postApproval(c); err != nil {
+			t.Fatal(err)
+		}
+
+		return rec.Code
+	}
+
+	// approval without groups is not decided by users
+	mock.ExpectQuery(`SELECT .* FROM "approvals"`).WillReturnRows(approvalRows())
+
+	if code := post(userClaims("bob", "chore_user")); code != http.StatusForbidden {
+		t.Errorf("postApproval() user code = %d, want %d", code, http.StatusForbidden)
+	}
+
+	mock.ExpectQuery(`SELECT .* FROM "approvals"`).WillReturnRows(approvalRows())
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "approvals" SET .* WHERE id = \$\d+ AND status = \$\d+`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if code := post(userClaims("root", "chore_admin")); code != http.StatusNoContent {
+		t.Errorf("postApproval() admin code = %d, want %d", code, http.StatusNoContent)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
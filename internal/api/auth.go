@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -16,6 +17,7 @@ import (
 	"github.com/worldline-go/chore/pkg/models"
 	"github.com/worldline-go/chore/pkg/models/apimodels"
 	"github.com/worldline-go/chore/pkg/registry"
+	"github.com/worldline-go/chore/pkg/request"
 )
 
 type AuthPureID struct {
@@ -154,6 +156,10 @@ func putAuth(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: err.Error()})
 	}
 
+	if err := validateAuthProfile(body.AuthPure); err != nil {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: err.Error()})
+	}
+
 	id := body.ID.ID
 	if id.String() == "00000000-0000-0000-0000-000000000000" {
 		if body.Name == "" {
@@ -209,6 +215,10 @@ func postAuth(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: apimodels.ErrRequiredName.Error()})
 	}
 
+	if err := validateAuthProfile(body); err != nil {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: err.Error()})
+	}
+
 	id, err := uuid.NewUUID()
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: err.Error()})
@@ -331,6 +341,23 @@ func deleteAuth(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// validateAuthProfile checks config of the auth type.
+func validateAuthProfile(auth models.AuthPure) error {
+	config, err := json.Marshal(auth.Config)
+	if err != nil {
+		return err //nolint:wrapcheck // no need
+	}
+
+	var profile request.AuthProfile
+	if err := json.Unmarshal(config, &profile); err != nil {
+		return fmt.Errorf("auth config: %w", err)
+	}
+
+	profile.Type = auth.Type
+
+	return profile.Validate() //nolint:wrapcheck // clear error
+}
+
 func Auth(e *echo.Group, authMiddleware echo.MiddlewareFunc) {
 	e.GET("/auths", listAuths, authMiddleware, middlewares.UserRole, middlewares.PatToken)
 	e.GET("/auth", getAuth, authMiddleware, middlewares.UserRole, middlewares.PatToken)
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/worldline-go/chore/internal/server/middlewares"
+	"github.com/worldline-go/chore/pkg/models/apimodels"
+	"github.com/worldline-go/chore/pkg/request"
+)
+
+// @Summary List circuit breakers
+// @Tags breaker
+// @Description Get states of request node circuit breakers
+// @Security ApiKeyAuth
+// @Router /breakers [get]
+// @Success 200 {object} apimodels.Data{data=[]request.BreakerState{}}
+func listBreakers(c echo.Context) error {
+	return c.JSON(http.StatusOK,
+		apimodels.Data{
+			Data: request.GlobalBreakers.States(),
+		},
+	)
+}
+
+// @Summary Reset circuit breaker
+// @Tags breaker
+// @Description Close the circuit breaker with name
+// @Security ApiKeyAuth
+// @Router /breaker/reset [post]
+// @Param name query string true "breaker name"
+// @Success 204 "No Content"
+// @failure 400 {object} apimodels.Error{}
+// @failure 404 {object} apimodels.Error{}
+func resetBreaker(c echo.Context) error {
+	name := c.QueryParam("name")
+	if name == "" {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: "name is required"})
+	}
+
+	breaker, ok := request.GlobalBreakers.Find(name)
+	if !ok {
+		return c.JSON(http.StatusNotFound, apimodels.Error{Error: "breaker not found"})
+	}
+
+	breaker.Reset()
+
+	//nolint:wrapcheck // checking before
+	return c.NoContent(http.StatusNoContent)
+}
+
+func Breaker(e *echo.Group, authMiddleware echo.MiddlewareFunc) {
+	e.GET("/breakers", listBreakers, authMiddleware, middlewares.UserRole, middlewares.PatToken)
+	e.POST("/breaker/reset", resetBreaker, authMiddleware, middlewares.AdminRole, middlewares.PatToken)
+}
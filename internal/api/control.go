@@ -13,6 +13,7 @@ import (
 	"github.com/worldline-go/chore/internal/parser"
 	"github.com/worldline-go/chore/internal/server/middlewares"
 	"github.com/worldline-go/chore/internal/utils"
+	"github.com/worldline-go/chore/pkg/flow/nodes"
 	"github.com/worldline-go/chore/pkg/models"
 	"github.com/worldline-go/chore/pkg/models/apimodels"
 	"github.com/worldline-go/chore/pkg/registry"
@@ -223,6 +224,8 @@ func postControl(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: result.Error.Error()})
 	}
 
+	nodes.ReloadConsumers()
+
 	// return recorded data's id
 	return c.JSON(http.StatusOK, apimodels.Data{Data: apimodels.ID{ID: id}})
 }
@@ -289,6 +292,8 @@ func cloneControl(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: result.Error.Error()})
 	}
 
+	nodes.ReloadConsumers()
+
 	// return recorded data's id
 	return c.JSON(http.StatusOK,
 		apimodels.Data{
@@ -342,6 +347,8 @@ func putControl(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: result.Error.Error()})
 	}
 
+	nodes.ReloadConsumers()
+
 	//nolint:wrapcheck // checking before
 	return c.NoContent(http.StatusNoContent)
 }
@@ -395,6 +402,8 @@ func patchControl(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: result.Error.Error()})
 	}
 
+	nodes.ReloadConsumers()
+
 	resultData := make(map[string]interface{})
 	resultData["id"] = body["id"]
 
@@ -446,6 +455,8 @@ func deleteControl(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: result.Error.Error()})
 	}
 
+	nodes.ReloadConsumers()
+
 	//nolint:wrapcheck // checking before
 	return c.NoContent(http.StatusNoContent)
 }
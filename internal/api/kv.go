@@ -0,0 +1,233 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+
+	"github.com/worldline-go/chore/internal/server/middlewares"
+	"github.com/worldline-go/chore/internal/utils"
+	"github.com/worldline-go/chore/pkg/kv"
+	"github.com/worldline-go/chore/pkg/models"
+	"github.com/worldline-go/chore/pkg/models/apimodels"
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+var errRequiredNamespaceKey = errors.New("namespace and key are required")
+
+type KVBody struct {
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+	// TTL is duration like 10m, empty keeps the key until deleted.
+	TTL string `json:"ttl"`
+}
+
+func (b *KVBody) validate() (time.Duration, error) {
+	b.Namespace = strings.TrimSpace(b.Namespace)
+	b.Key = strings.TrimSpace(b.Key)
+
+	if b.Namespace == "" || b.Key == "" {
+		return 0, errRequiredNamespaceKey
+	}
+
+	if b.TTL == "" {
+		return 0, nil
+	}
+
+	return time.ParseDuration(b.TTL) //nolint:wrapcheck // clear error
+}
+
+// @Summary List keys
+// @Tags kv
+// @Description Get list of not expired keys in key-value store
+// @Security ApiKeyAuth
+// @Router /kvs [get]
+// @Param namespace query string false "filter by namespace"
+// @Param limit query int false "set the limit, default is 20"
+// @Param offset query int false "set the offset, default is 0"
+// @Param search query string false "search key prefix"
+// @Success 200 {object} apimodels.DataMeta{data=[]models.KV{},meta=apimodels.Meta{}}
+// @failure 400 {object} apimodels.Error{}
+// @failure 500 {object} apimodels.Error{}
+func listKVs(c echo.Context) error {
+	records := []models.KV{}
+
+	meta := &apimodels.Meta{Limit: apimodels.Limit}
+
+	if err := c.Bind(meta); err != nil {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: err.Error()})
+	}
+
+	namespace := c.QueryParam("namespace")
+	now := time.Now()
+
+	newQuery := func() *gorm.DB {
+		query := registry.Reg.DB.WithContext(c.Request().Context()).Model(&models.KV{}).Scopes(kv.Alive(now))
+		if namespace != "" {
+			query = query.Where("namespace = ?", namespace)
+		}
+
+		if meta.Search != "" {
+			query = query.Where("key LIKE ?", meta.Search+"%")
+		}
+
+		return query
+	}
+
+	result := newQuery().Order("namespace").Order("key").Limit(meta.Limit).Offset(meta.Offset).Find(&records)
+	if result.Error != nil {
+		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: result.Error.Error()})
+	}
+
+	// get counts
+	newQuery().Count(&meta.Count)
+
+	return c.JSON(http.StatusOK,
+		apimodels.DataMeta{
+			Meta: meta,
+			Data: apimodels.Data{Data: records},
+		},
+	)
+}
+
+// @Summary Get key
+// @Tags kv
+// @Description Get one not expired key of key-value store
+// @Security ApiKeyAuth
+// @Router /kv [get]
+// @Param namespace query string true "namespace of the key"
+// @Param key query string true "key"
+// @Success 200 {object} apimodels.Data{data=models.KV{}}
+// @failure 400 {object} apimodels.Error{}
+// @failure 404 {object} apimodels.Error{}
+// @failure 500 {object} apimodels.Error{}
+func getKV(c echo.Context) error {
+	namespace := c.QueryParam("namespace")
+	key := c.QueryParam("key")
+
+	if namespace == "" || key == "" {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: errRequiredNamespaceKey.Error()})
+	}
+
+	record, err := kv.Get(utils.Context(c), registry.Reg.DB, namespace, key)
+	if errors.Is(err, kv.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, apimodels.Error{Error: err.Error()})
+	}
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK,
+		apimodels.Data{
+			Data: record,
+		},
+	)
+}
+
+// @Summary New or Update key
+// @Tags kv
+// @Description Set value of the key, ttl is a duration like 10m
+// @Security ApiKeyAuth
+// @Router /kv [put]
+// @Param payload body KVBody{} false "send key object"
+// @Success 204 "No Content"
+// @failure 400 {object} apimodels.Error{}
+// @failure 500 {object} apimodels.Error{}
+func putKV(c echo.Context) error {
+	var body KVBody
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: err.Error()})
+	}
+
+	ttl, err := body.validate()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: err.Error()})
+	}
+
+	if err := kv.Set(utils.Context(c), registry.Reg.DB, body.Namespace, body.Key, body.Value, ttl); err != nil {
+		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: err.Error()})
+	}
+
+	//nolint:wrapcheck // checking before
+	return c.NoContent(http.StatusNoContent)
+}
+
+// @Summary New key
+// @Tags kv
+// @Description Create key if not exist or expired, ttl is a duration like 10m
+// @Security ApiKeyAuth
+// @Router /kv [post]
+// @Param payload body KVBody{} false "send key object"
+// @Success 204 "No Content"
+// @failure 400 {object} apimodels.Error{}
+// @failure 409 {object} apimodels.Error{}
+// @failure 500 {object} apimodels.Error{}
+func postKV(c echo.Context) error {
+	var body KVBody
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: err.Error()})
+	}
+
+	ttl, err := body.validate()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: err.Error()})
+	}
+
+	created, err := kv.CompareAndSwap(utils.Context(c), registry.Reg.DB, body.Namespace, body.Key, nil, body.Value, ttl)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: err.Error()})
+	}
+
+	if !created {
+		return c.JSON(http.StatusConflict, apimodels.Error{Error: "key already exists"})
+	}
+
+	//nolint:wrapcheck // checking before
+	return c.NoContent(http.StatusNoContent)
+}
+
+// @Summary Delete key
+// @Tags kv
+// @Description Delete key of key-value store
+// @Security ApiKeyAuth
+// @Router /kv [delete]
+// @Param namespace query string true "namespace of the key"
+// @Param key query string true "key"
+// @Success 204 "No Content"
+// @failure 400 {object} apimodels.Error{}
+// @failure 404 {object} apimodels.Error{}
+// @failure 500 {object} apimodels.Error{}
+func deleteKV(c echo.Context) error {
+	namespace := c.QueryParam("namespace")
+	key := c.QueryParam("key")
+
+	if namespace == "" || key == "" {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: errRequiredNamespaceKey.Error()})
+	}
+
+	deleted, err := kv.Delete(utils.Context(c), registry.Reg.DB, namespace, key)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: err.Error()})
+	}
+
+	if !deleted {
+		return c.JSON(http.StatusNotFound, apimodels.Error{Error: apimodels.ErrNotFound.Error()})
+	}
+
+	//nolint:wrapcheck // checking before
+	return c.NoContent(http.StatusNoContent)
+}
+
+func KV(e *echo.Group, authMiddleware echo.MiddlewareFunc) {
+	e.GET("/kvs", listKVs, authMiddleware, middlewares.UserRole, middlewares.PatToken)
+	e.GET("/kv", getKV, authMiddleware, middlewares.UserRole, middlewares.PatToken)
+	e.POST("/kv", postKV, authMiddleware, middlewares.UserRole, middlewares.PatToken)
+	e.PUT("/kv", putKV, authMiddleware, middlewares.UserRole, middlewares.PatToken)
+	e.DELETE("/kv", deleteKV, authMiddleware, middlewares.UserRole, middlewares.PatToken)
+}
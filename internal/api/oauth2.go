@@ -0,0 +1,64 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/worldline-go/chore/internal/server/middlewares"
+	"github.com/worldline-go/chore/internal/utils"
+	"github.com/worldline-go/chore/pkg/models/apimodels"
+	"github.com/worldline-go/chore/pkg/request"
+)
+
+// @Summary OAuth2 status
+// @Tags oauth2
+// @Description Get cached oauth2 clients with token expiry and last error
+// @Security ApiKeyAuth
+// @Router /oauth2/status [get]
+// @Success 200 {object} apimodels.Data{data=[]request.ServiceStatus{}}
+func getOAuth2Status(c echo.Context) error {
+	return c.JSON(http.StatusOK,
+		apimodels.Data{
+			Data: request.GlobalRegistry.Status(),
+		},
+	)
+}
+
+// @Summary Refresh OAuth2 token
+// @Tags oauth2
+// @Description Force to get new token of the cached oauth2 client
+// @Security ApiKeyAuth
+// @Router /oauth2/refresh [post]
+// @Param key query string true "client key from status"
+// @Success 200 {object} apimodels.Data{data=request.ServiceStatus{}}
+// @failure 400 {object} apimodels.Error{}
+// @failure 404 {object} apimodels.Error{}
+// @failure 502 {object} apimodels.Error{}
+func postOAuth2Refresh(c echo.Context) error {
+	key := c.QueryParam("key")
+	if key == "" {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: "key is required"})
+	}
+
+	status, err := request.GlobalRegistry.Refresh(utils.Context(c), key)
+	if errors.Is(err, request.ErrServiceNotFound) {
+		return c.JSON(http.StatusNotFound, apimodels.Error{Error: err.Error()})
+	}
+
+	if err != nil {
+		return c.JSON(http.StatusBadGateway, apimodels.Error{Error: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK,
+		apimodels.Data{
+			Data: status,
+		},
+	)
+}
+
+func OAuth2(e *echo.Group, authMiddleware echo.MiddlewareFunc) {
+	e.GET("/oauth2/status", getOAuth2Status, authMiddleware, middlewares.AdminRole, middlewares.PatToken)
+	e.POST("/oauth2/refresh", postOAuth2Refresh, authMiddleware, middlewares.AdminRole, middlewares.PatToken)
+}
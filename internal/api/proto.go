@@ -0,0 +1,292 @@
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/worldline-go/chore/internal/server/middlewares"
+	"github.com/worldline-go/chore/internal/utils"
+	"github.com/worldline-go/chore/pkg/grpccall"
+	"github.com/worldline-go/chore/pkg/models"
+	"github.com/worldline-go/chore/pkg/models/apimodels"
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+type ProtoPureID struct {
+	models.ProtoPure
+	apimodels.ID
+}
+
+type ProtoName struct {
+	Name string `json:"name"`
+	apimodels.Groups
+	apimodels.ID
+}
+
+// @Summary List protos
+// @Tags proto
+// @Description Get list of the stored file descriptor sets
+// @Security ApiKeyAuth
+// @Router /protos [get]
+// @Param limit query int false "set the limit, default is 20"
+// @Param offset query int false "set the offset, default is 0"
+// @Param search query string false "search item"
+// @Success 200 {object} apimodels.DataMeta{data=[]ProtoName{},meta=apimodels.Meta{}}
+// @failure 400 {object} apimodels.Error{}
+// @failure 500 {object} apimodels.Error{}
+func listProtos(c echo.Context) error {
+	protos := []ProtoName{}
+
+	meta := &apimodels.Meta{Limit: apimodels.Limit}
+
+	if err := c.Bind(meta); err != nil {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: err.Error()})
+	}
+
+	query := registry.Reg.DB.WithContext(c.Request().Context()).Model(&models.Proto{}).Limit(meta.Limit).Offset(meta.Offset)
+
+	if meta.Search != "" {
+		query = query.Where("name LIKE ?", meta.Search+"%")
+	}
+
+	result := query.Find(&protos)
+
+	if result.Error != nil {
+		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: result.Error.Error()})
+	}
+
+	// get counts
+	query = registry.Reg.DB.WithContext(c.Request().Context()).Model(&models.Proto{})
+	if meta.Search != "" {
+		query = query.Where("name LIKE ?", meta.Search+"%")
+	}
+
+	query.Count(&meta.Count)
+
+	return c.JSON(http.StatusOK,
+		apimodels.DataMeta{
+			Meta: meta,
+			Data: apimodels.Data{Data: protos},
+		},
+	)
+}
+
+// @Summary Get proto
+// @Tags proto
+// @Description Get one file descriptor set with id or name
+// @Security ApiKeyAuth
+// @Router /proto [get]
+// @Param id query string false "get by id"
+// @Param name query string false "get by name"
+// @Success 200 {object} apimodels.Data{data=ProtoPureID{}}
+// @failure 400 {object} apimodels.Error{}
+// @failure 404 {object} apimodels.Error{}
+// @failure 500 {object} apimodels.Error{}
+func getProto(c echo.Context) error {
+	id := c.QueryParam("id")
+	name := c.QueryParam("name")
+
+	if id == "" && name == "" {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: apimodels.ErrRequiredIDName.Error()})
+	}
+
+	getData := new(ProtoPureID)
+
+	query := registry.Reg.DB.WithContext(c.Request().Context()).Model(&models.Proto{})
+	if id != "" {
+		query = query.Where("id = ?", id)
+	}
+
+	if name != "" {
+		query = query.Where("name = ?", name)
+	}
+
+	result := query.First(&getData)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return c.JSON(http.StatusNotFound, apimodels.Error{Error: result.Error.Error()})
+	}
+
+	if result.Error != nil {
+		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: result.Error.Error()})
+	}
+
+	return c.JSON(http.StatusOK,
+		apimodels.Data{
+			Data: getData,
+		},
+	)
+}
+
+// @Summary New or Update proto
+// @Tags proto
+// @Description Send and record file descriptor set, content is base64 of protoc --descriptor_set_out
+// @Security ApiKeyAuth
+// @Router /proto [put]
+// @Param payload body ProtoPureID{} false "send proto object"
+// @Success 204 "No Content"
+// @failure 400 {object} apimodels.Error{}
+// @failure 500 {object} apimodels.Error{}
+func putProto(c echo.Context) error {
+	var body ProtoPureID
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: err.Error()})
+	}
+
+	if err := validateProto(body.ProtoPure); err != nil {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: err.Error()})
+	}
+
+	id := body.ID.ID
+	if id.String() == "00000000-0000-0000-0000-000000000000" {
+		if body.Name == "" {
+			return c.JSON(http.StatusBadRequest, apimodels.Error{Error: apimodels.ErrRequiredName.Error()})
+		}
+
+		var err error
+		id, err = uuid.NewUUID()
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: err.Error()})
+		}
+	}
+
+	ctx := utils.Context(c)
+	result := registry.Reg.DB.WithContext(ctx).Model(&models.Proto{}).Clauses(
+		clause.OnConflict{
+			UpdateAll: true,
+			Columns:   []clause.Column{{Name: "id"}},
+		}).Create(
+		&models.Proto{
+			ProtoPure: body.ProtoPure,
+			ModelCU: apimodels.ModelCU{
+				ID: apimodels.ID{ID: id},
+			},
+		},
+	)
+
+	if result.Error != nil {
+		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: result.Error.Error()})
+	}
+
+	//nolint:wrapcheck // checking before
+	return c.NoContent(http.StatusNoContent)
+}
+
+// @Summary New proto
+// @Tags proto
+// @Description Send and record new file descriptor set, content is base64 of protoc --descriptor_set_out
+// @Security ApiKeyAuth
+// @Router /proto [post]
+// @Param payload body models.ProtoPure{} false "send proto object"
+// @Success 200 {object} apimodels.Data{data=apimodels.ID{}}
+// @failure 400 {object} apimodels.Error{}
+// @failure 409 {object} apimodels.Error{}
+// @failure 500 {object} apimodels.Error{}
+func postProto(c echo.Context) error {
+	var body models.ProtoPure
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: err.Error()})
+	}
+
+	if body.Name == "" {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: apimodels.ErrRequiredName.Error()})
+	}
+
+	if err := validateProto(body); err != nil {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: err.Error()})
+	}
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: err.Error()})
+	}
+
+	ctx := utils.Context(c)
+	result := registry.Reg.DB.WithContext(ctx).Model(&models.Proto{}).Create(
+		&models.Proto{
+			ProtoPure: body,
+			ModelCU: apimodels.ModelCU{
+				ID: apimodels.ID{ID: id},
+			},
+		},
+	)
+
+	// check write error
+	if result.Error != nil && errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return c.JSON(http.StatusConflict, apimodels.Error{Error: result.Error.Error()})
+	}
+
+	if result.Error != nil {
+		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: result.Error.Error()})
+	}
+
+	// return recorded data's id
+	return c.JSON(
+		http.StatusOK,
+		apimodels.Data{
+			Data: apimodels.ID{ID: id},
+		},
+	)
+}
+
+// @Summary Delete proto
+// @Tags proto
+// @Description Delete with id
+// @Security ApiKeyAuth
+// @Router /proto [delete]
+// @Param id query string false "get by id"
+// @Success 204 "No Content"
+// @failure 400 {object} apimodels.Error{}
+// @failure 404 {object} apimodels.Error{}
+// @failure 500 {object} apimodels.Error{}
+func deleteProto(c echo.Context) error {
+	id := c.QueryParam("id")
+
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: apimodels.ErrRequiredID.Error()})
+	}
+
+	ctx := utils.Context(c)
+	query := registry.Reg.DB.WithContext(ctx).Where("id = ?", id)
+
+	// delete directly in DB
+	result := query.Unscoped().Delete(&models.Proto{})
+
+	if result.RowsAffected == 0 {
+		return c.JSON(http.StatusNotFound, apimodels.Error{Error: "not found any releated data"})
+	}
+
+	if result.Error != nil {
+		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: result.Error.Error()})
+	}
+
+	//nolint:wrapcheck // checking before
+	return c.NoContent(http.StatusNoContent)
+}
+
+// validateProto checks content is a file descriptor set.
+func validateProto(p models.ProtoPure) error {
+	content, err := base64.StdEncoding.DecodeString(p.Content)
+	if err != nil {
+		return fmt.Errorf("content should be base64: %w", err)
+	}
+
+	_, err = grpccall.ParseDescriptors(content)
+
+	return err //nolint:wrapcheck // clear error
+}
+
+func Proto(e *echo.Group, authMiddleware echo.MiddlewareFunc) {
+	e.GET("/protos", listProtos, authMiddleware, middlewares.UserRole, middlewares.PatToken)
+	e.GET("/proto", getProto, authMiddleware, middlewares.UserRole, middlewares.PatToken)
+	e.POST("/proto", postProto, authMiddleware, middlewares.UserRole, middlewares.PatToken)
+	e.PUT("/proto", putProto, authMiddleware, middlewares.UserRole, middlewares.PatToken)
+	e.DELETE("/proto", deleteProto, authMiddleware, middlewares.UserRole, middlewares.PatToken)
+}
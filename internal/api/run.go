@@ -0,0 +1,116 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+
+	"github.com/worldline-go/chore/internal/server/middlewares"
+	"github.com/worldline-go/chore/pkg/models"
+	"github.com/worldline-go/chore/pkg/models/apimodels"
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+type RunPureID struct {
+	models.RunPure
+	apimodels.ID
+}
+
+// @Summary List runs
+// @Tags run
+// @Description Get list of the durable runs
+// @Security ApiKeyAuth
+// @Router /runs [get]
+// @Param limit query int false "set the limit, default is 20"
+// @Param offset query int false "set the offset, default is 0"
+// @Param status query string false "filter by status (running, suspended, completed, failed)"
+// @Param control query string false "filter by control name"
+// @Param parent_run_id query string false "filter by caller run id"
+// @Success 200 {object} apimodels.DataMeta{data=[]RunPureID{},meta=apimodels.Meta{}}
+// @failure 400 {object} apimodels.Error{}
+// @failure 500 {object} apimodels.Error{}
+func listRuns(c echo.Context) error {
+	runs := []RunPureID{}
+
+	meta := &apimodels.Meta{Limit: apimodels.Limit}
+
+	if err := c.Bind(meta); err != nil {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: err.Error()})
+	}
+
+	filter := func(query *gorm.DB) *gorm.DB {
+		if status := c.QueryParam("status"); status != "" {
+			query = query.Where("status = ?", status)
+		}
+
+		if control := c.QueryParam("control"); control != "" {
+			query = query.Where("control = ?", control)
+		}
+
+		if parentRunID := c.QueryParam("parent_run_id"); parentRunID != "" {
+			query = query.Where("parent_run_id = ?", parentRunID)
+		}
+
+		return query
+	}
+
+	query := filter(registry.Reg.DB.WithContext(c.Request().Context()).Model(&models.Run{}))
+
+	result := query.Order("created_at desc").Limit(meta.Limit).Offset(meta.Offset).Find(&runs)
+
+	if result.Error != nil {
+		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: result.Error.Error()})
+	}
+
+	// get counts
+	filter(registry.Reg.DB.WithContext(c.Request().Context()).Model(&models.Run{})).Count(&meta.Count)
+
+	return c.JSON(http.StatusOK,
+		apimodels.DataMeta{
+			Meta: meta,
+			Data: apimodels.Data{Data: runs},
+		},
+	)
+}
+
+// @Summary Get run
+// @Tags run
+// @Description Get one durable run with id
+// @Security ApiKeyAuth
+// @Router /run [get]
+// @Param id query string true "get by id"
+// @Success 200 {object} apimodels.Data{data=RunPureID{}}
+// @failure 400 {object} apimodels.Error{}
+// @failure 404 {object} apimodels.Error{}
+// @failure 500 {object} apimodels.Error{}
+func getRun(c echo.Context) error {
+	id := c.QueryParam("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: apimodels.ErrRequiredID.Error()})
+	}
+
+	getData := new(RunPureID)
+
+	result := registry.Reg.DB.WithContext(c.Request().Context()).Model(&models.Run{}).Where("id = ?", id).First(getData)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return c.JSON(http.StatusNotFound, apimodels.Error{Error: result.Error.Error()})
+	}
+
+	if result.Error != nil {
+		return c.JSON(http.StatusInternalServerError, apimodels.Error{Error: result.Error.Error()})
+	}
+
+	return c.JSON(http.StatusOK,
+		apimodels.Data{
+			Data: getData,
+		},
+	)
+}
+
+func Run(e *echo.Group, authMiddleware echo.MiddlewareFunc) {
+	e.GET("/runs", listRuns, authMiddleware, middlewares.UserRole, middlewares.PatToken)
+	e.GET("/run", getRun, authMiddleware, middlewares.UserRole, middlewares.PatToken)
+}
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -9,6 +11,10 @@ import (
 
 	"github.com/worldline-go/chore/internal/server/middlewares"
 	"github.com/worldline-go/chore/internal/utils"
+	"github.com/worldline-go/chore/pkg/broker"
+	"github.com/worldline-go/chore/pkg/database"
+	"github.com/worldline-go/chore/pkg/email"
+	"github.com/worldline-go/chore/pkg/mailbox"
 	"github.com/worldline-go/chore/pkg/models"
 	"github.com/worldline-go/chore/pkg/models/apimodels"
 	"github.com/worldline-go/chore/pkg/registry"
@@ -19,7 +25,7 @@ import (
 // @Description Get whole settings
 // @Security ApiKeyAuth
 // @Router /settings [get]
-// @Param namespace query string true "get by namespace (email, oauth2)"
+// @Param namespace query string true "get by namespace (email, imap, broker, database, oauth2, tls)"
 // @Param name query string false "name like email-1"
 // @Success 200 {object} apimodels.Data{}
 // @failure 400 {object} apimodels.Error{}
@@ -65,11 +71,11 @@ func getSettings(c echo.Context) error {
 
 // @Summary Replace settings
 // @Tags settings
-// @Description Replace with new data
+// @Description Replace with new data, email, imap, broker and database namespace data is validated
 // @Security ApiKeyAuth
 // @Router /settings [patch]
 // @Param payload body models.Settings{} false "send part of the settings object"
-// @Param namespace query string true "get by namespace (email, oauth2)"
+// @Param namespace query string true "get by namespace (email, imap, broker, database, oauth2, tls)"
 // @Param name query string false "name like email-1"
 // @Success 204 "No Content"
 // @failure 400 {object} apimodels.Error{}
@@ -88,6 +94,10 @@ func patchSettings(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: err.Error()})
 	}
 
+	if err := validateSettings(namespace, body); err != nil {
+		return c.JSON(http.StatusBadRequest, apimodels.Error{Error: err.Error()})
+	}
+
 	bodyModel := models.Settings{
 		SettingsPure: models.SettingsPure{
 			Name:      name,
@@ -124,7 +134,7 @@ func patchSettings(c echo.Context) error {
 // @Description Replace with new data
 // @Security ApiKeyAuth
 // @Router /settings [delete]
-// @Param namespace query string true "get by namespace (email, oauth2)"
+// @Param namespace query string true "get by namespace (email, imap, broker, database, oauth2, tls)"
 // @Param name query string false "name like email-1"
 // @Success 204 "No Content"
 // @failure 400 {object} apimodels.Error{}
@@ -157,6 +167,59 @@ func deleteSettings(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// validateSettings checks data of the namespaces used by nodes.
+func validateSettings(namespace string, body map[string]interface{}) error {
+	switch namespace {
+	case "email":
+		emailModel := models.Email{}
+		if err := settingsModel(body, &emailModel); err != nil {
+			return fmt.Errorf("email settings not valid: %w", err)
+		}
+
+		_, err := email.ParseSettings(emailModel)
+
+		return err //nolint:wrapcheck // clear error
+	case "imap":
+		imapModel := models.IMAP{}
+		if err := settingsModel(body, &imapModel); err != nil {
+			return fmt.Errorf("imap settings not valid: %w", err)
+		}
+
+		_, err := mailbox.ParseSettings(imapModel)
+
+		return err //nolint:wrapcheck // clear error
+	case "broker":
+		brokerModel := models.Broker{}
+		if err := settingsModel(body, &brokerModel); err != nil {
+			return fmt.Errorf("broker settings not valid: %w", err)
+		}
+
+		_, err := broker.ParseSettings(brokerModel)
+
+		return err //nolint:wrapcheck // clear error
+	case "database":
+		databaseModel := models.Database{}
+		if err := settingsModel(body, &databaseModel); err != nil {
+			return fmt.Errorf("database settings not valid: %w", err)
+		}
+
+		_, err := database.ParseSettings(databaseModel)
+
+		return err //nolint:wrapcheck // clear error
+	}
+
+	return nil
+}
+
+func settingsModel(body map[string]interface{}, v interface{}) error {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err //nolint:wrapcheck // clear error
+	}
+
+	return json.Unmarshal(raw, v) //nolint:wrapcheck // clear error
+}
+
 func Settings(c *echo.Group, authMiddleware echo.MiddlewareFunc) {
 	c.GET("/settings", getSettings, authMiddleware, middlewares.AdminRole, middlewares.PatToken)
 	c.PATCH("/settings", patchSettings, authMiddleware, middlewares.AdminRole, middlewares.PatToken)
@@ -41,6 +41,11 @@ var Application = struct {
 	Store    Store    `cfg:"store"`
 	Migrate  Store    `cfg:"migrate"`
 	Template Template `cfg:"template"`
+	Durable  Durable  `cfg:"durable"`
+	Call     Call     `cfg:"call"`
+	Breaker  Breaker  `cfg:"breaker"`
+	Cache    Cache    `cfg:"cache"`
+	KV       KV       `cfg:"kv"`
 
 	AuthProviders map[string]*providers.Generic `cfg:"auth_providers"`
 
@@ -67,6 +72,29 @@ var Application = struct {
 	Template: Template{
 		Trust: false,
 	},
+	Durable: Durable{
+		LockDuration:  time.Minute,
+		CheckInterval: 30 * time.Second,
+		SuspendAfter:  5 * time.Minute,
+	},
+	Call: Call{
+		MaxDepth: 10,
+	},
+	Breaker: Breaker{
+		FailureRate:    0.5,
+		MinRequests:    10,
+		Window:         time.Minute,
+		OpenDuration:   30 * time.Second,
+		HalfOpenProbes: 1,
+	},
+	Cache: Cache{
+		Backend:       "memory",
+		CleanInterval: 5 * time.Minute,
+		MaxEntries:    10000,
+	},
+	KV: KV{
+		CleanInterval: 5 * time.Minute,
+	},
 }
 
 // User settings will use if doesn't have any user on database.
@@ -95,3 +123,40 @@ type Store struct {
 type Template struct {
 	Trust bool `cfg:"trust"`
 }
+
+// Durable settings to checkpoint runs in database and resume after restart.
+type Durable struct {
+	Enabled       bool          `cfg:"enabled"`
+	LockDuration  time.Duration `cfg:"lock_duration"`
+	CheckInterval time.Duration `cfg:"check_interval"`
+	SuspendAfter  time.Duration `cfg:"suspend_after"`
+}
+
+// Call settings to limit nested control calls.
+type Call struct {
+	MaxDepth       int  `cfg:"max_depth"`
+	AllowRecursion bool `cfg:"allow_recursion"`
+}
+
+// Breaker settings of request node circuit breakers.
+type Breaker struct {
+	FailureRate    float64       `cfg:"failure_rate"`
+	MinRequests    int           `cfg:"min_requests"`
+	Window         time.Duration `cfg:"window"`
+	OpenDuration   time.Duration `cfg:"open_duration"`
+	HalfOpenProbes int           `cfg:"half_open_probes"`
+}
+
+// Cache settings of request node response cache.
+type Cache struct {
+	// Backend is memory or postgres.
+	Backend       string        `cfg:"backend"`
+	CleanInterval time.Duration `cfg:"clean_interval"`
+	// MaxEntries limits memory backend, least recently used entries removed.
+	MaxEntries int `cfg:"max_entries"`
+}
+
+// KV settings of key-value store nodes.
+type KV struct {
+	CleanInterval time.Duration `cfg:"clean_interval"`
+}
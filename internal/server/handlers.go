@@ -28,6 +28,7 @@ import (
 	"github.com/worldline-go/chore/internal/server/middlewares"
 	"github.com/worldline-go/chore/pkg/registry"
 	"github.com/worldline-go/chore/pkg/request"
+	"github.com/worldline-go/chore/pkg/transfer"
 )
 
 // @description Storage and Send API
@@ -56,6 +57,12 @@ func setHandlers(e *echo.Group, authMiddleware echo.MiddlewareFunc) error {
 	api.Token(v1, authMiddleware)
 	api.Control(v1, authMiddleware)
 	api.Settings(v1, authMiddleware)
+	api.Approval(v1, authMiddleware)
+	api.Run(v1, authMiddleware)
+	api.Breaker(v1, authMiddleware)
+	api.OAuth2(v1, authMiddleware)
+	api.Proto(v1, authMiddleware)
+	api.KV(v1, authMiddleware)
 	api.Info(v1)
 	run.API(v1, authMiddleware)
 
@@ -109,12 +116,15 @@ func Set(ctx context.Context, wg *sync.WaitGroup, db *gorm.DB) (*echo.Echo, erro
 
 	registry.Init(&registry.Registry{
 		DB: db,
-		Template: templatex.New(templatex.WithAddFuncsTpl(
-			fstore.FuncMapTpl(
-				fstore.WithLog(logz.AdapterKV{Log: log.With().Str("component", "template").Logger()}),
-				fstore.WithTrust(config.Application.Template.Trust),
+		Template: templatex.New(
+			templatex.WithAddFuncsTpl(
+				fstore.FuncMapTpl(
+					fstore.WithLog(logz.AdapterKV{Log: log.With().Str("component", "template").Logger()}),
+					fstore.WithTrust(config.Application.Template.Trust),
+				),
 			),
-		)),
+			templatex.WithAddFuncMap(transfer.XMLFuncs()),
+		),
 		Server: e,
 		JWT: registry.JWT{
 			JWT:    serverJWT,
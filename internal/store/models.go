@@ -12,5 +12,12 @@ var Models = []interface{}{
 	&models.Token{},
 	&models.Control{},
 	&models.Settings{},
+	&models.Approval{},
+	&models.Run{},
+	&models.RunTask{},
+	&models.RequestCache{},
+	&models.Proto{},
+	&models.TriggerPoll{},
+	&models.KV{},
 	// &models.Test{},
 }
@@ -0,0 +1,48 @@
+package approval
+
+import "sync"
+
+// Notifier wakes up local waiters of an approval when decision is given.
+// Other instances are catching the decision with polling the database.
+type Notifier struct {
+	waiters map[string]chan struct{}
+	mutex   sync.Mutex
+}
+
+var GlobalNotifier = &Notifier{
+	waiters: make(map[string]chan struct{}),
+}
+
+// Wait returns a channel closed on notify of the id.
+func (n *Notifier) Wait(id string) <-chan struct{} {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if ch, ok := n.waiters[id]; ok {
+		return ch
+	}
+
+	ch := make(chan struct{})
+	n.waiters[id] = ch
+
+	return ch
+}
+
+// Notify closes the waiter channel of the id.
+func (n *Notifier) Notify(id string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if ch, ok := n.waiters[id]; ok {
+		close(ch)
+		delete(n.waiters, id)
+	}
+}
+
+// Remove deletes the waiter without notify.
+func (n *Notifier) Remove(id string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	delete(n.waiters, id)
+}
@@ -0,0 +1,38 @@
+package approval
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotifier(t *testing.T) {
+	n := &Notifier{waiters: make(map[string]chan struct{})}
+
+	wait := n.Wait("1")
+	if wait != n.Wait("1") {
+		t.Fatalf("Notifier.Wait() should return same channel for same id")
+	}
+
+	other := n.Wait("2")
+
+	n.Notify("1")
+
+	select {
+	case <-wait:
+	case <-time.After(time.Second):
+		t.Fatalf("Notifier.Notify() not closed the channel")
+	}
+
+	select {
+	case <-other:
+		t.Fatalf("Notifier.Notify() closed other channel")
+	default:
+	}
+
+	n.Remove("2")
+	n.Notify("2")
+
+	if len(n.waiters) != 0 {
+		t.Errorf("Notifier waiters = %d, want 0", len(n.waiters))
+	}
+}
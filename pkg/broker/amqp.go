@@ -0,0 +1,198 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type amqpPublisher struct {
+	conn     *amqp.Connection
+	channel  *amqp.Channel
+	exchange string
+	timeout  time.Duration
+}
+
+// dialAMQP connects to the first reachable broker.
+func dialAMQP(cfg Config) (*amqp.Connection, error) {
+	amqpConfig := amqp.Config{
+		Dial: amqp.DefaultDial(cfg.timeout()),
+	}
+
+	if cfg.TLS {
+		amqpConfig.TLSClientConfig = cfg.tlsConfig()
+	}
+
+	if cfg.Username != "" {
+		amqpConfig.SASL = []amqp.Authentication{&amqp.PlainAuth{Username: cfg.Username, Password: cfg.Password}}
+	}
+
+	var (
+		conn *amqp.Connection
+		err  error
+	)
+
+	for _, url := range cfg.Brokers {
+		conn, err = amqp.DialConfig(url, amqpConfig)
+		if err == nil {
+			return conn, nil
+		}
+	}
+
+	if err == nil {
+		err = errors.New("no broker")
+	}
+
+	return nil, fmt.Errorf("amqp connect: %w", err)
+}
+
+func newAMQP(cfg Config) (*amqpPublisher, error) {
+	conn, err := dialAMQP(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("amqp channel: %w", err)
+	}
+
+	if err := channel.Confirm(false); err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("amqp confirm mode: %w", err)
+	}
+
+	return &amqpPublisher{
+		conn:     conn,
+		channel:  channel,
+		exchange: cfg.Exchange,
+		timeout:  cfg.timeout(),
+	}, nil
+}
+
+func (p *amqpPublisher) Publish(ctx context.Context, msg Message) error {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+
+	headers := make(amqp.Table, len(msg.Headers))
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+
+	confirmation, err := p.channel.PublishWithDeferredConfirmWithContext(ctx, p.exchange, msg.Topic, false, false, amqp.Publishing{
+		Headers:      headers,
+		MessageId:    msg.Key,
+		DeliveryMode: amqp.Persistent,
+		Body:         msg.Value,
+	})
+	if err != nil {
+		return fmt.Errorf("amqp publish %s: %w", msg.Topic, err)
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("amqp publish %s: %w", msg.Topic, err)
+	}
+
+	if !acked {
+		return fmt.Errorf("amqp publish %s: not acknowledged by broker", msg.Topic)
+	}
+
+	return nil
+}
+
+func (p *amqpPublisher) Close() error {
+	return p.conn.Close() //nolint:wrapcheck // clear error
+}
+
+func (p *amqpPublisher) closed() bool {
+	return p.channel.IsClosed()
+}
+
+// amqpDeliveries uses x-delivery-count of quorum queues, classic queues only report redelivered.
+func amqpDeliveries(d amqp.Delivery) int {
+	if v, ok := d.Headers["x-delivery-count"]; ok {
+		if count, err := strconv.Atoi(fmt.Sprint(v)); err == nil {
+			return count + 1
+		}
+	}
+
+	if !d.Redelivered {
+		return 1
+	}
+
+	return 0
+}
+
+// consumeAMQP reads the queue with prefetch of concurrency, failed messages are requeued.
+func consumeAMQP(ctx context.Context, cfg Config, consume ConsumeConfig, handler Handler) error {
+	conn, err := dialAMQP(cfg)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	channel, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("amqp channel: %w", err)
+	}
+
+	if err := channel.Qos(consume.Concurrency, 0, false); err != nil {
+		return fmt.Errorf("amqp qos: %w", err)
+	}
+
+	deliveries, err := channel.Consume(consume.Topic, consume.Group, false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("amqp consume %s: %w", consume.Topic, err)
+	}
+
+	return runWorkers(ctx, consume.Concurrency, func(ctx context.Context) error {
+		for {
+			var (
+				d  amqp.Delivery
+				ok bool
+			)
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case d, ok = <-deliveries:
+			}
+
+			if !ok {
+				return fmt.Errorf("amqp consume %s: deliveries closed", consume.Topic)
+			}
+
+			headers := make(map[string]string, len(d.Headers))
+			for k, v := range d.Headers {
+				headers[k] = fmt.Sprint(v)
+			}
+
+			if err := handler(ctx, Delivery{
+				Topic:      consume.Topic,
+				Key:        d.MessageId,
+				Headers:    headers,
+				Value:      d.Body,
+				Deliveries: amqpDeliveries(d),
+			}); err != nil {
+				if err := d.Nack(false, true); err != nil {
+					return fmt.Errorf("amqp nack: %w", err)
+				}
+
+				continue
+			}
+
+			if err := d.Ack(false); err != nil {
+				return fmt.Errorf("amqp ack: %w", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,161 @@
+// Package broker publishes messages to Kafka, NATS and AMQP.
+package broker
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout of the connection and publish.
+var DefaultTimeout = 30 * time.Second
+
+// Drivers of the message queues.
+const (
+	DriverKafka = "kafka"
+	DriverNATS  = "nats"
+	DriverAMQP  = "amqp"
+)
+
+// Config of the broker connection.
+type Config struct {
+	// Driver is one of the Driver values.
+	Driver string
+	// Brokers are host:port for kafka, URLs for nats and amqp.
+	Brokers  []string
+	Username string
+	Password string
+	TLS      bool
+	// SkipVerify skips server certificate check.
+	SkipVerify bool
+	// Exchange of the AMQP messages, empty is default exchange.
+	Exchange string
+	// JetStream waits acknowledgement of the NATS stream.
+	JetStream bool
+	// Timeout of the connection and publish, default is DefaultTimeout.
+	Timeout time.Duration
+}
+
+func (c Config) timeout() time.Duration {
+	if c.Timeout <= 0 {
+		return DefaultTimeout
+	}
+
+	return c.Timeout
+}
+
+// withTimeout adds timeout to the context without a deadline, acknowledgement should not wait forever.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+func (c Config) tlsConfig() *tls.Config {
+	return &tls.Config{InsecureSkipVerify: c.SkipVerify} //nolint:gosec // user option
+}
+
+// Message to publish.
+type Message struct {
+	// Topic is kafka topic, nats subject or amqp routing key.
+	Topic string
+	// Key is kafka partition key, nats and amqp message id.
+	Key     string
+	Headers map[string]string
+	Value   []byte
+}
+
+// Publisher sends messages and waits the delivery acknowledgement.
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+	Close() error
+}
+
+// Delivery is a consumed message.
+type Delivery struct {
+	Topic   string
+	Key     string
+	Headers map[string]string
+	Value   []byte
+	// Deliveries is count of deliveries of the message including this one, zero is unknown.
+	Deliveries int
+}
+
+// Handler processes a delivery, message is acknowledged only when it returns nil.
+type Handler func(ctx context.Context, d Delivery) error
+
+// ConsumeConfig selects messages to consume.
+type ConsumeConfig struct {
+	// Topic is kafka topic, nats subject or amqp queue.
+	Topic string
+	// Group is kafka consumer group, nats durable and queue group or amqp consumer tag.
+	Group string
+	// Concurrency is count of messages processed at same time, default is 1.
+	Concurrency int
+}
+
+// Consume calls handler for each message until context done or connection failed.
+// Messages in process finish before return.
+func Consume(ctx context.Context, cfg Config, consume ConsumeConfig, handler Handler) error {
+	if consume.Concurrency <= 0 {
+		consume.Concurrency = 1
+	}
+
+	switch cfg.Driver {
+	case DriverKafka:
+		return consumeKafka(ctx, cfg, consume, handler)
+	case DriverNATS:
+		return consumeNATS(ctx, cfg, consume, handler)
+	case DriverAMQP:
+		return consumeAMQP(ctx, cfg, consume, handler)
+	}
+
+	return fmt.Errorf("broker driver %q not supported", cfg.Driver)
+}
+
+// runWorkers runs count workers, first error stops others and returned.
+func runWorkers(ctx context.Context, count int, worker func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, count)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := worker(ctx); err != nil {
+				errs <- err
+
+				cancel()
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	return <-errs
+}
+
+// New connects to the broker with the driver of the config.
+func New(cfg Config) (Publisher, error) {
+	switch cfg.Driver {
+	case DriverKafka:
+		return newKafka(cfg), nil
+	case DriverNATS:
+		return newNATS(cfg)
+	case DriverAMQP:
+		return newAMQP(cfg)
+	}
+
+	return nil, fmt.Errorf("broker driver %q not supported", cfg.Driver)
+}
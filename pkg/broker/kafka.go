@@ -0,0 +1,133 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+)
+
+func kafkaDialer(cfg Config) *kafka.Dialer {
+	dialer := &kafka.Dialer{
+		Timeout:   cfg.timeout(),
+		DualStack: true,
+	}
+
+	if cfg.TLS {
+		dialer.TLS = cfg.tlsConfig()
+	}
+
+	if cfg.Username != "" {
+		dialer.SASLMechanism = plain.Mechanism{Username: cfg.Username, Password: cfg.Password}
+	}
+
+	return dialer
+}
+
+type kafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+func newKafka(cfg Config) *kafkaPublisher {
+	transport := &kafka.Transport{
+		DialTimeout: cfg.timeout(),
+	}
+
+	if cfg.TLS {
+		transport.TLS = cfg.tlsConfig()
+	}
+
+	if cfg.Username != "" {
+		transport.SASL = plain.Mechanism{Username: cfg.Username, Password: cfg.Password}
+	}
+
+	return &kafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(cfg.Brokers...),
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			// write each message without waiting a batch
+			BatchSize:    1,
+			WriteTimeout: cfg.timeout(),
+			Transport:    transport,
+		},
+	}
+}
+
+func (p *kafkaPublisher) Publish(ctx context.Context, msg Message) error {
+	headers := make([]kafka.Header, 0, len(msg.Headers))
+	for k, v := range msg.Headers {
+		headers = append(headers, kafka.Header{Key: k, Value: []byte(v)})
+	}
+
+	var key []byte
+	if msg.Key != "" {
+		key = []byte(msg.Key)
+	}
+
+	if err := p.writer.WriteMessages(ctx, kafka.Message{
+		Topic:   msg.Topic,
+		Key:     key,
+		Value:   msg.Value,
+		Headers: headers,
+	}); err != nil {
+		return fmt.Errorf("kafka publish %s: %w", msg.Topic, err)
+	}
+
+	return nil
+}
+
+func (p *kafkaPublisher) Close() error {
+	return p.writer.Close() //nolint:wrapcheck // clear error
+}
+
+// consumeKafka joins the group with a reader for each concurrency, partitions are shared between readers.
+// Offset is committed after handler succeeds, failed message stops the reader to read it again.
+func consumeKafka(ctx context.Context, cfg Config, consume ConsumeConfig, handler Handler) error {
+	return runWorkers(ctx, consume.Concurrency, func(ctx context.Context) error {
+		reader := kafka.NewReader(kafka.ReaderConfig{
+			Brokers: cfg.Brokers,
+			GroupID: consume.Group,
+			Topic:   consume.Topic,
+			Dialer:  kafkaDialer(cfg),
+		})
+
+		defer reader.Close()
+
+		for {
+			m, err := reader.FetchMessage(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+
+				return fmt.Errorf("kafka consume %s: %w", consume.Topic, err)
+			}
+
+			headers := make(map[string]string, len(m.Headers))
+			for _, h := range m.Headers {
+				headers[h.Key] = string(h.Value)
+			}
+
+			if err := handler(ctx, Delivery{
+				Topic:   m.Topic,
+				Key:     string(m.Key),
+				Headers: headers,
+				Value:   m.Value,
+			}); err != nil {
+				return fmt.Errorf("kafka consume %s offset %d: %w", consume.Topic, m.Offset, err)
+			}
+
+			// commit even consumer is stopping
+			ctxCommit, cancel := context.WithTimeout(context.WithoutCancel(ctx), cfg.timeout())
+			err = reader.CommitMessages(ctxCommit, m)
+
+			cancel()
+
+			if err != nil {
+				return fmt.Errorf("kafka commit %s: %w", consume.Topic, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,225 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+type natsPublisher struct {
+	conn      *nats.Conn
+	js        nats.JetStreamContext
+	jetStream bool
+	timeout   time.Duration
+}
+
+func connectNATS(cfg Config) (*nats.Conn, error) {
+	opts := []nats.Option{nats.Timeout(cfg.timeout())}
+
+	if cfg.Username != "" {
+		opts = append(opts, nats.UserInfo(cfg.Username, cfg.Password))
+	}
+
+	if cfg.TLS {
+		opts = append(opts, nats.Secure(cfg.tlsConfig()))
+	}
+
+	conn, err := nats.Connect(strings.Join(cfg.Brokers, ","), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("nats connect: %w", err)
+	}
+
+	return conn, nil
+}
+
+func newNATS(cfg Config) (*natsPublisher, error) {
+	conn, err := connectNATS(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &natsPublisher{conn: conn, jetStream: cfg.JetStream, timeout: cfg.timeout()}
+
+	if cfg.JetStream {
+		if p.js, err = conn.JetStream(); err != nil {
+			conn.Close()
+
+			return nil, fmt.Errorf("nats jetstream: %w", err)
+		}
+	}
+
+	return p, nil
+}
+
+func (p *natsPublisher) Publish(ctx context.Context, msg Message) error {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+
+	m := nats.NewMsg(msg.Topic)
+	m.Data = msg.Value
+
+	for k, v := range msg.Headers {
+		m.Header.Set(k, v)
+	}
+
+	if msg.Key != "" {
+		// deduplication id of the stream
+		m.Header.Set(nats.MsgIdHdr, msg.Key)
+	}
+
+	if p.jetStream {
+		if _, err := p.js.PublishMsg(m, nats.Context(ctx)); err != nil {
+			return fmt.Errorf("nats publish %s: %w", msg.Topic, err)
+		}
+
+		return nil
+	}
+
+	if err := p.conn.PublishMsg(m); err != nil {
+		return fmt.Errorf("nats publish %s: %w", msg.Topic, err)
+	}
+
+	// core nats has no ack, flush waits server to receive it
+	if err := p.conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("nats publish %s: %w", msg.Topic, err)
+	}
+
+	return nil
+}
+
+func (p *natsPublisher) Close() error {
+	return p.conn.Drain() //nolint:wrapcheck // clear error
+}
+
+func (p *natsPublisher) closed() bool {
+	return p.conn.IsClosed()
+}
+
+// natsFetchWait is the wait of a pull request, context is checked between requests.
+var natsFetchWait = 5 * time.Second
+
+// consumeNATS reads the subject with a durable pull consumer in JetStream.
+// Without JetStream messages are shared in queue group and not acknowledged.
+func consumeNATS(ctx context.Context, cfg Config, consume ConsumeConfig, handler Handler) error {
+	conn, err := connectNATS(cfg)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	if !cfg.JetStream {
+		return consumeNATSCore(ctx, conn, consume, handler)
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		return fmt.Errorf("nats jetstream: %w", err)
+	}
+
+	sub, err := js.PullSubscribe(consume.Topic, consume.Group, nats.ManualAck(), nats.MaxAckPending(consume.Concurrency))
+	if err != nil {
+		return fmt.Errorf("nats consume %s: %w", consume.Topic, err)
+	}
+
+	return runWorkers(ctx, consume.Concurrency, func(ctx context.Context) error {
+		for ctx.Err() == nil {
+			msgs, err := sub.Fetch(1, nats.MaxWait(natsFetchWait))
+			if err != nil {
+				if errors.Is(err, nats.ErrTimeout) {
+					continue
+				}
+
+				return fmt.Errorf("nats consume %s: %w", consume.Topic, err)
+			}
+
+			for _, msg := range msgs {
+				if err := natsHandle(ctx, msg, handler); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// natsHandle keeps message in progress while handler runs.
+func natsHandle(ctx context.Context, msg *nats.Msg, handler Handler) error {
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		ticker := time.NewTicker(natsFetchWait)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_ = msg.InProgress()
+			}
+		}
+	}()
+
+	d := natsDelivery(msg)
+	if meta, err := msg.Metadata(); err == nil {
+		d.Deliveries = int(meta.NumDelivered)
+	}
+
+	if err := handler(ctx, d); err != nil {
+		if err := msg.Nak(); err != nil {
+			return fmt.Errorf("nats nak: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := msg.AckSync(); err != nil {
+		return fmt.Errorf("nats ack: %w", err)
+	}
+
+	return nil
+}
+
+func consumeNATSCore(ctx context.Context, conn *nats.Conn, consume ConsumeConfig, handler Handler) error {
+	msgs := make(chan *nats.Msg, consume.Concurrency)
+
+	sub, err := conn.ChanQueueSubscribe(consume.Topic, consume.Group, msgs)
+	if err != nil {
+		return fmt.Errorf("nats consume %s: %w", consume.Topic, err)
+	}
+
+	defer sub.Unsubscribe() //nolint:errcheck // closing
+
+	return runWorkers(ctx, consume.Concurrency, func(ctx context.Context) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case msg := <-msgs:
+				// no acknowledgement, handler reports errors itself
+				_ = handler(ctx, natsDelivery(msg))
+			}
+		}
+	})
+}
+
+func natsDelivery(msg *nats.Msg) Delivery {
+	headers := make(map[string]string, len(msg.Header))
+	for k := range msg.Header {
+		headers[k] = msg.Header.Get(k)
+	}
+
+	return Delivery{
+		Topic:   msg.Subject,
+		Key:     msg.Header.Get(nats.MsgIdHdr),
+		Headers: headers,
+		Value:   msg.Data,
+	}
+}
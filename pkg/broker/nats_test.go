@@ -0,0 +1,216 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+func natsServer(t *testing.T) *server.Server {
+	t.Helper()
+
+	s, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		NoLog:     true,
+		NoSigs:    true,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go s.Start()
+
+	if !s.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+
+	t.Cleanup(s.Shutdown)
+
+	return s
+}
+
+func TestNATS_Publish(t *testing.T) {
+	s := natsServer(t)
+
+	conn, err := nats.Connect(s.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	sub, err := conn.SubscribeSync("orders.created")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := New(Config{Driver: DriverNATS, Brokers: []string{s.ClientURL()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer p.Close()
+
+	ctx := context.Background()
+
+	if err := p.Publish(ctx, Message{
+		Topic:   "orders.created",
+		Headers: map[string]string{"X-Source": "chore"},
+		Value:   []byte(`{"id":42}`),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := sub.NextMsg(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(msg.Data) != `{"id":42}` || msg.Header.Get("X-Source") != "chore" {
+		t.Errorf("message = %s, headers = %v", msg.Data, msg.Header)
+	}
+}
+
+func TestNATS_PublishJetStream(t *testing.T) {
+	s := natsServer(t)
+
+	conn, err := nats.Connect(s.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	js, err := conn.JetStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := js.AddStream(&nats.StreamConfig{Name: "ORDERS", Subjects: []string{"orders.>"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := New(Config{Driver: DriverNATS, Brokers: []string{s.ClientURL()}, JetStream: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer p.Close()
+
+	ctx := context.Background()
+
+	// same key is deduplicated by the stream
+	for i := 0; i < 2; i++ {
+		if err := p.Publish(ctx, Message{Topic: "orders.created", Key: "order-42", Value: []byte(`{"id":42}`)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	info, err := js.StreamInfo("ORDERS")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.State.Msgs != 1 {
+		t.Errorf("stream messages = %d, want 1", info.State.Msgs)
+	}
+
+	// no stream for the subject, not acknowledged
+	if err := p.Publish(ctx, Message{Topic: "payments.created", Value: []byte(`{}`)}); err == nil {
+		t.Error("publish without stream should fail")
+	}
+}
+
+func TestNATS_ConsumeJetStream(t *testing.T) {
+	s := natsServer(t)
+
+	natsFetchWait = 200 * time.Millisecond
+
+	conn, err := nats.Connect(s.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	js, err := conn.JetStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := js.AddStream(&nats.StreamConfig{Name: "ORDERS", Subjects: []string{"orders.>"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []string{"1", "2", "3"} {
+		if _, err := js.Publish("orders.created", []byte(v)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := Config{Driver: DriverNATS, Brokers: []string{s.ClientURL()}, JetStream: true}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		mutex     sync.Mutex
+		processed = map[string]int{}
+	)
+
+	errConsume := make(chan error, 1)
+
+	go func() {
+		errConsume <- Consume(ctx, cfg, ConsumeConfig{Topic: "orders.created", Group: "chore", Concurrency: 2}, func(_ context.Context, d Delivery) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			processed[string(d.Value)]++
+
+			if d.Deliveries != processed[string(d.Value)] {
+				t.Errorf("deliveries of %s = %d, want %d", d.Value, d.Deliveries, processed[string(d.Value)])
+			}
+
+			// first try of 2 fails and redelivered
+			if string(d.Value) == "2" && processed["2"] == 1 {
+				return errors.New("run failed")
+			}
+
+			if len(processed) == 3 && processed["2"] == 2 {
+				cancel()
+			}
+
+			return nil
+		})
+	}()
+
+	select {
+	case err := <-errConsume:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("consume timeout")
+	}
+
+	if processed["1"] != 1 || processed["2"] != 2 || processed["3"] != 1 {
+		t.Errorf("processed = %v", processed)
+	}
+
+	info, err := js.ConsumerInfo("ORDERS", "chore")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.NumAckPending != 0 || info.NumPending != 0 {
+		t.Errorf("ack pending = %d, pending = %d", info.NumAckPending, info.NumPending)
+	}
+}
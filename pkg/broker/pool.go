@@ -0,0 +1,37 @@
+package broker
+
+import (
+	"encoding/json"
+
+	"github.com/worldline-go/chore/pkg/refpool"
+)
+
+// closer reports publisher connection closed and cannot publish anymore.
+type closer interface {
+	closed() bool
+}
+
+// pools keeps publishers of the profiles between runs.
+var pools = refpool.New(
+	func(p Publisher) { _ = p.Close() },
+	func(p Publisher) bool {
+		c, ok := p.(closer)
+
+		return !ok || !c.closed()
+	},
+)
+
+func (c Config) key() string {
+	b, _ := json.Marshal(c)
+
+	return string(b)
+}
+
+// Acquire returns shared publisher of the profile, publisher is opened again when config changed.
+// Release should be called after the run, publisher is closed when not used by anyone
+// after config change or idle time.
+func Acquire(name string, cfg Config) (Publisher, func(), error) {
+	return pools.Acquire(name, cfg.key(), func() (Publisher, error) { //nolint:wrapcheck // clear error
+		return New(cfg)
+	})
+}
@@ -0,0 +1,73 @@
+package broker
+
+import (
+	"testing"
+	"time"
+)
+
+func waitClosed(t *testing.T, p Publisher) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+
+	for !p.(closer).closed() {
+		if time.Now().After(deadline) {
+			t.Fatal("publisher not closed")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestAcquire(t *testing.T) {
+	defer func(v time.Duration) { pools.Idle = v }(pools.Idle)
+	pools.Idle = 50 * time.Millisecond
+
+	s := natsServer(t)
+	cfg := Config{Driver: DriverNATS, Brokers: []string{s.ClientURL()}}
+
+	first, releaseFirst, err := Acquire("events", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, releaseSecond, err := Acquire("events", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first != second {
+		t.Fatal("Acquire() same config not shared")
+	}
+
+	// changed profile opens new publisher, running one kept until released
+	cfg.JetStream = true
+
+	changed, releaseChanged, err := Acquire("events", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if changed == first {
+		t.Fatal("Acquire() changed config shared old publisher")
+	}
+
+	releaseFirst()
+	releaseFirst()
+
+	if first.(closer).closed() {
+		t.Fatal("publisher closed while used")
+	}
+
+	releaseSecond()
+	waitClosed(t, first)
+
+	// not used publisher closed after idle
+	releaseChanged()
+
+	if changed.(closer).closed() {
+		t.Fatal("publisher closed before idle")
+	}
+
+	waitClosed(t, changed)
+}
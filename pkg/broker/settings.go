@@ -0,0 +1,44 @@
+package broker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/worldline-go/chore/pkg/models"
+)
+
+// ParseSettings checks stored broker profile and returns connection config.
+func ParseSettings(m models.Broker) (Config, error) {
+	cfg := Config{
+		Driver:     strings.ToLower(strings.TrimSpace(m.Driver)),
+		Username:   strings.TrimSpace(m.Username),
+		Password:   m.Password,
+		TLS:        m.TLS,
+		SkipVerify: m.SkipVerify,
+		Exchange:   strings.TrimSpace(m.Exchange),
+		JetStream:  m.JetStream,
+	}
+
+	switch cfg.Driver {
+	case DriverKafka, DriverNATS, DriverAMQP:
+	default:
+		return cfg, fmt.Errorf("broker driver %q not supported, use kafka, nats or amqp", m.Driver)
+	}
+
+	cfg.Brokers = strings.FieldsFunc(m.Brokers, func(r rune) bool { return r == ',' || r == ' ' || r == '\n' })
+
+	if len(cfg.Brokers) == 0 {
+		return cfg, errors.New("broker address is empty")
+	}
+
+	if timeout := strings.TrimSpace(m.Timeout); timeout != "" {
+		var err error
+		if cfg.Timeout, err = time.ParseDuration(timeout); err != nil {
+			return cfg, fmt.Errorf("broker timeout %q cannot parse: %w", timeout, err)
+		}
+	}
+
+	return cfg, nil
+}
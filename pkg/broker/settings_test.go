@@ -0,0 +1,63 @@
+package broker
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/worldline-go/chore/pkg/models"
+)
+
+func TestParseSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       models.Broker
+		want    Config
+		wantErr bool
+	}{
+		{
+			name: "kafka cluster",
+			m: models.Broker{
+				Driver: "Kafka", Brokers: "kafka-1:9092, kafka-2:9092", Username: "chore", Password: "x",
+				TLS: true, Timeout: "10s",
+			},
+			want: Config{
+				Driver: DriverKafka, Brokers: []string{"kafka-1:9092", "kafka-2:9092"}, Username: "chore", Password: "x",
+				TLS: true, Timeout: 10 * time.Second,
+			},
+		},
+		{
+			name: "amqp exchange",
+			m:    models.Broker{Driver: "amqp", Brokers: "amqp://rabbitmq:5672/events", Exchange: "orders"},
+			want: Config{Driver: DriverAMQP, Brokers: []string{"amqp://rabbitmq:5672/events"}, Exchange: "orders"},
+		},
+		{
+			name:    "driver",
+			m:       models.Broker{Driver: "redis", Brokers: "redis:6379"},
+			wantErr: true,
+		},
+		{
+			name:    "brokers",
+			m:       models.Broker{Driver: "nats", Brokers: " , "},
+			wantErr: true,
+		},
+		{
+			name:    "timeout",
+			m:       models.Broker{Driver: "nats", Brokers: "nats://nats:4222", Timeout: "10"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSettings(tt.m)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Package database runs parameterised queries on named database connections.
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/worldline-go/chore/pkg/refpool"
+)
+
+// DriverPostgres is the supported database driver.
+const DriverPostgres = "postgres"
+
+// Config of the database connection.
+type Config struct {
+	Driver string
+	DSN    string
+	// MaxOpenConns limits the pool, zero is unlimited.
+	MaxOpenConns int
+	// ReadOnly runs all queries in read only transactions.
+	ReadOnly bool
+}
+
+func (c Config) key() string {
+	b, _ := json.Marshal(c)
+
+	return string(b)
+}
+
+// pools keeps connections of the profiles between runs.
+var pools = refpool.New(func(db *sql.DB) { _ = db.Close() }, nil)
+
+// connect opens the connection pool, replaced in tests.
+var connect = open
+
+// Acquire returns shared connection pool of the profile, pool is opened again when config changed.
+// Release should be called after the run, pool is closed when not used by anyone
+// after config change or idle time.
+func Acquire(name string, cfg Config) (*sql.DB, func(), error) {
+	return pools.Acquire(name, cfg.key(), func() (*sql.DB, error) { //nolint:wrapcheck // clear error
+		return connect(name, cfg)
+	})
+}
+
+func open(name string, cfg Config) (*sql.DB, error) {
+	if cfg.Driver != DriverPostgres {
+		return nil, fmt.Errorf("database driver %q not supported", cfg.Driver)
+	}
+
+	db, err := gorm.Open(postgres.Open(cfg.DSN), &gorm.Config{
+		// queries and parameters are user data, not logged
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("database %s connection: %w", name, err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("database %s connection: %w", name, err)
+	}
+
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+
+	return sqlDB, nil
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestAcquire(t *testing.T) {
+	defer func(v time.Duration) { pools.Idle = v }(pools.Idle)
+	pools.Idle = 50 * time.Millisecond
+
+	mocks := map[*sql.DB]sqlmock.Sqlmock{}
+
+	defer func(v func(string, Config) (*sql.DB, error)) { connect = v }(connect)
+	connect = func(string, Config) (*sql.DB, error) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			return nil, err
+		}
+
+		mock.ExpectClose()
+		mocks[db] = mock
+
+		return db, nil
+	}
+
+	closed := func(db *sql.DB) bool {
+		return mocks[db].ExpectationsWereMet() == nil
+	}
+
+	waitClosed := func(db *sql.DB) {
+		t.Helper()
+
+		deadline := time.Now().Add(2 * time.Second)
+
+		for !closed(db) {
+			if time.Now().After(deadline) {
+				t.Fatal("pool not closed")
+			}
+
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	cfg := Config{Driver: DriverPostgres, DSN: "host=db"}
+
+	first, releaseFirst, err := Acquire("orders", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, releaseSecond, err := Acquire("orders", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first != second {
+		t.Fatal("Acquire() same config not shared")
+	}
+
+	// changed profile opens new pool, running one kept until released
+	cfg.ReadOnly = true
+
+	changed, releaseChanged, err := Acquire("orders", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if changed == first {
+		t.Fatal("Acquire() changed config shared old pool")
+	}
+
+	releaseFirst()
+	releaseFirst()
+
+	if closed(first) {
+		t.Fatal("pool closed while used")
+	}
+
+	releaseSecond()
+	waitClosed(first)
+
+	// not used pool closed after idle
+	releaseChanged()
+
+	if closed(changed) {
+		t.Fatal("pool closed before idle")
+	}
+
+	waitClosed(changed)
+}
@@ -0,0 +1,422 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Statement modes, auto selects query for statements returning rows.
+const (
+	ModeAuto  = ""
+	ModeQuery = "query"
+	ModeExec  = "exec"
+)
+
+// Options of the statements run.
+type Options struct {
+	Mode string
+	// Transaction runs all statements in one transaction, rollback on error.
+	Transaction bool
+	// ReadOnly runs statements in a read only transaction, database rejects writes.
+	ReadOnly bool
+	// Params maps parameter names to dotted paths in data, default path is the name.
+	Params map[string]string
+}
+
+// Statement is a query with positional binds.
+type Statement struct {
+	Query string
+	Names []string
+}
+
+// Parse splits query to statements with `;` and replaces `@name` parameters with `$n` binds.
+// Quoted strings, identifiers, comments and dollar quoted bodies are kept as is.
+func Parse(query string) []Statement {
+	var (
+		statements []Statement
+		buf        strings.Builder
+		names      []string
+		index      map[string]int
+	)
+
+	flush := func() {
+		if q := strings.TrimSpace(buf.String()); q != "" {
+			statements = append(statements, Statement{Query: q, Names: names})
+		}
+
+		buf.Reset()
+
+		names = nil
+		index = nil
+	}
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+
+		switch {
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(query[i+1:], c)
+			if end < 0 {
+				// unterminated quote kept to the end, database reports the error
+				end = len(query) - i - 2 //nolint:gomnd // quote markers
+			}
+
+			buf.WriteString(query[i : i+end+2])
+			i += end + 1
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+
+			buf.WriteString(query[i : i+end])
+			i += end - 1
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				end = len(query) - i - 4 //nolint:gomnd // comment markers
+			}
+
+			buf.WriteString(query[i : i+end+4])
+			i += end + 3 //nolint:gomnd // comment markers
+		case c == '$' && dollarTag(query[i:]) != "":
+			tag := dollarTag(query[i:])
+
+			end := strings.Index(query[i+len(tag):], tag)
+			if end < 0 {
+				end = len(query) - i - 2*len(tag)
+			}
+
+			buf.WriteString(query[i : i+end+2*len(tag)])
+			i += end + 2*len(tag) - 1
+		case c == '@' && i+1 < len(query) && isNameStart(query[i+1]):
+			j := i + 1
+			for j < len(query) && isNamePart(query[j]) {
+				j++
+			}
+
+			name := query[i+1 : j]
+
+			if index == nil {
+				index = make(map[string]int)
+			}
+
+			n, ok := index[name]
+			if !ok {
+				names = append(names, name)
+				n = len(names)
+				index[name] = n
+			}
+
+			buf.WriteString("$" + strconv.Itoa(n))
+			i = j - 1
+		case c == ';':
+			flush()
+		default:
+			buf.WriteByte(c)
+		}
+	}
+
+	flush()
+
+	return statements
+}
+
+// dollarTag returns tag like $$ or $body$ at start of s.
+func dollarTag(s string) string {
+	for j := 1; j < len(s); j++ {
+		if s[j] == '$' {
+			return s[:j+1]
+		}
+
+		if !isNamePart(s[j]) || (j == 1 && !isNameStart(s[j])) {
+			return ""
+		}
+	}
+
+	return ""
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNamePart(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
+
+// isQuery reports statement returns rows.
+func isQuery(query string) bool {
+	fields := keywords(query)
+	if len(fields) == 0 {
+		return false
+	}
+
+	switch fields[0] {
+	case "SELECT", "WITH", "SHOW", "VALUES", "TABLE", "EXPLAIN":
+		return true
+	}
+
+	for _, f := range fields {
+		if f == "RETURNING" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// keywords returns words of the statement in upper case, leading comments are skipped.
+func keywords(query string) []string {
+	for {
+		query = strings.TrimSpace(query)
+
+		switch {
+		case strings.HasPrefix(query, "--"):
+			end := strings.IndexByte(query, '\n')
+			if end < 0 {
+				return nil
+			}
+
+			query = query[end:]
+		case strings.HasPrefix(query, "/*"):
+			end := strings.Index(query, "*/")
+			if end < 0 {
+				return nil
+			}
+
+			query = query[end+2:]
+		default:
+			return strings.FieldsFunc(strings.ToUpper(query), func(r rune) bool {
+				return r > unicode.MaxASCII || !isNamePart(byte(r))
+			})
+		}
+	}
+}
+
+// isTransactionControl reports statement ends the transaction or changes session,
+// read only transaction is bypassed with them.
+func isTransactionControl(query string) bool {
+	fields := keywords(query)
+	if len(fields) == 0 {
+		return false
+	}
+
+	switch fields[0] {
+	case "BEGIN", "START", "COMMIT", "ROLLBACK", "END", "ABORT", "SET", "RESET", "SAVEPOINT", "RELEASE":
+		return true
+	case "PREPARE":
+		return len(fields) > 1 && fields[1] == "TRANSACTION"
+	}
+
+	return false
+}
+
+// args gets bind values from data.
+func (s Statement) args(data interface{}, params map[string]string) ([]interface{}, error) {
+	args := make([]interface{}, 0, len(s.Names))
+
+	for _, name := range s.Names {
+		path := name
+		if v, ok := params[name]; ok {
+			path = v
+		}
+
+		v, ok := lookup(data, path)
+		if !ok {
+			return nil, fmt.Errorf("parameter %s not found in data with path %q", name, path)
+		}
+
+		arg, err := bindValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", name, err)
+		}
+
+		args = append(args, arg)
+	}
+
+	return args, nil
+}
+
+// lookup finds dotted path like order.items.0.id in data.
+func lookup(data interface{}, path string) (interface{}, bool) {
+	v := data
+
+	for _, key := range strings.Split(path, ".") {
+		switch d := v.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if v, ok = d[key]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(d) {
+				return nil, false
+			}
+
+			v = d[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// bindValue converts json values, whole numbers to integer and objects to json text.
+func bindValue(v interface{}) (interface{}, error) {
+	switch value := v.(type) {
+	case float64:
+		if value == math.Trunc(value) && math.Abs(value) < 1<<53 {
+			return int64(value), nil
+		}
+
+		return value, nil
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return nil, err //nolint:wrapcheck // clear error
+		}
+
+		return string(b), nil
+	}
+
+	return v, nil
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Run executes the query statements with parameters from data.
+// Each result is rows as list of objects or rows_affected object.
+func Run(ctx context.Context, db *sql.DB, query string, data interface{}, opts Options) ([]interface{}, error) {
+	statements := Parse(query)
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("query is empty")
+	}
+
+	if opts.ReadOnly {
+		for i, s := range statements {
+			if isTransactionControl(s.Query) {
+				return nil, fmt.Errorf("statement %d: transaction control not allowed in read only query", i+1)
+			}
+		}
+	}
+
+	if !opts.Transaction && !opts.ReadOnly {
+		return runStatements(ctx, db, statements, data, opts)
+	}
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: opts.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+
+	results, err := runStatements(ctx, tx, statements, data, opts)
+	if err != nil {
+		_ = tx.Rollback()
+
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return results, nil
+}
+
+func runStatements(ctx context.Context, q queryer, statements []Statement, data interface{}, opts Options) ([]interface{}, error) {
+	results := make([]interface{}, 0, len(statements))
+
+	for i, s := range statements {
+		args, err := s.args(data, opts.Params)
+		if err != nil {
+			return nil, fmt.Errorf("statement %d %w", i+1, err)
+		}
+
+		mode := opts.Mode
+		if mode == ModeAuto && isQuery(s.Query) {
+			mode = ModeQuery
+		}
+
+		if mode == ModeQuery {
+			rows, err := queryRows(ctx, q, s.Query, args)
+			if err != nil {
+				return nil, fmt.Errorf("statement %d: %w", i+1, err)
+			}
+
+			results = append(results, rows)
+
+			continue
+		}
+
+		result, err := q.ExecContext(ctx, s.Query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("statement %d: %w", i+1, err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("statement %d: %w", i+1, err)
+		}
+
+		results = append(results, map[string]interface{}{"rows_affected": affected})
+	}
+
+	return results, nil
+}
+
+func queryRows(ctx context.Context, q queryer, query string, args []interface{}) ([]map[string]interface{}, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // clear error
+	}
+
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err //nolint:wrapcheck // clear error
+	}
+
+	result := []map[string]interface{}{}
+
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err //nolint:wrapcheck // clear error
+		}
+
+		row := make(map[string]interface{}, len(columns))
+
+		for i, column := range columns {
+			if b, ok := values[i].([]byte); ok {
+				row[column] = string(b)
+
+				continue
+			}
+
+			row[column] = values[i]
+		}
+
+		result = append(result, row)
+	}
+
+	return result, rows.Err() //nolint:wrapcheck // clear error
+}
@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []Statement
+	}{
+		{
+			name:  "named parameters",
+			query: "SELECT * FROM orders WHERE id = @id AND status = @status OR parent = @id",
+			want: []Statement{
+				{Query: "SELECT * FROM orders WHERE id = $1 AND status = $2 OR parent = $1", Names: []string{"id", "status"}},
+			},
+		},
+		{
+			name:  "quoted and casts",
+			query: "SELECT '@id; x', \"@col\", @id::int, tags @> '{a}' -- @comment;\nFROM t /* @c; */",
+			want: []Statement{
+				{Query: "SELECT '@id; x', \"@col\", $1::int, tags @> '{a}' -- @comment;\nFROM t /* @c; */", Names: []string{"id"}},
+			},
+		},
+		{
+			name:  "statements",
+			query: "UPDATE stock SET count = count - @count WHERE id = @id;\nINSERT INTO log (body) VALUES ($body$ a; @b $body$);\n",
+			want: []Statement{
+				{Query: "UPDATE stock SET count = count - $1 WHERE id = $2", Names: []string{"count", "id"}},
+				{Query: "INSERT INTO log (body) VALUES ($body$ a; @b $body$)"},
+			},
+		},
+		{
+			name:  "unterminated quote",
+			query: "SELECT 'abc; @id",
+			want:  []Statement{{Query: "SELECT 'abc; @id"}},
+		},
+		{
+			name:  "unterminated comment and dollar quote",
+			query: "SELECT $$ a; /* b",
+			want:  []Statement{{Query: "SELECT $$ a; /* b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	data := map[string]interface{}{
+		"order": map[string]interface{}{"id": 42.0, "items": []interface{}{"apple"}},
+		"count": 2.0,
+	}
+
+	mock.ExpectQuery(`-- lookup\s+SELECT id, name FROM orders WHERE id = \$1`).
+		WithArgs(int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(42), []byte("apple")))
+
+	// leading comment not changes auto mode
+	results, err := Run(context.Background(), db, "-- lookup\nSELECT id, name FROM orders WHERE id = @id", data, Options{
+		Params: map[string]string{"id": "order.id"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []interface{}{[]map[string]interface{}{{"id": int64(42), "name": "apple"}}}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("Run() = %#v, want %#v", results, want)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE stock SET count = count - \$1`).WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`INSERT INTO log \(items\) VALUES \(\$1\)`).WithArgs(`["apple"]`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	results, err = Run(context.Background(), db,
+		"UPDATE stock SET count = count - @count; INSERT INTO log (items) VALUES (@items)", data, Options{
+			Transaction: true,
+			Params:      map[string]string{"items": "order.items"},
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want = []interface{}{map[string]interface{}{"rows_affected": int64(3)}, map[string]interface{}{"rows_affected": int64(1)}}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("Run() = %#v, want %#v", results, want)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM orders`).WillReturnError(errors.New("cannot execute DELETE in a read-only transaction"))
+	mock.ExpectRollback()
+
+	if _, err := Run(context.Background(), db, "DELETE FROM orders", data, Options{ReadOnly: true}); err == nil {
+		t.Error("Run() read only should fail")
+	}
+
+	// read only transaction cannot be ended to run writes
+	for _, query := range []string{
+		"COMMIT; DELETE FROM orders",
+		"SELECT 1; /* end */ end; DELETE FROM orders",
+		"SET SESSION CHARACTERISTICS AS TRANSACTION READ WRITE",
+		"prepare transaction 'x'; DELETE FROM orders",
+	} {
+		if _, err := Run(context.Background(), db, query, data, Options{ReadOnly: true}); err == nil {
+			t.Errorf("Run() read only %q should fail", query)
+		}
+	}
+
+	if _, err := Run(context.Background(), db, "SELECT @missing", data, Options{}); err == nil {
+		t.Error("Run() missing parameter should fail")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
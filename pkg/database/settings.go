@@ -0,0 +1,109 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/worldline-go/chore/pkg/models"
+)
+
+// readOnlyParam makes new transactions of the session read only on the server side.
+const readOnlyParam = "default_transaction_read_only"
+
+// readOnlyDSN adds read only parameter to url or keyword/value dsn.
+func readOnlyDSN(dsn string) (string, error) {
+	if !strings.HasPrefix(dsn, "postgres://") && !strings.HasPrefix(dsn, "postgresql://") {
+		return dsn + " " + readOnlyParam + "=on", nil
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", errors.New("database dsn is not valid url")
+	}
+
+	query := u.Query()
+	query.Set(readOnlyParam, "on")
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// ParseSettings checks stored database profile and returns connection config.
+func ParseSettings(m models.Database) (Config, error) {
+	cfg := Config{
+		Driver:   strings.ToLower(strings.TrimSpace(m.Driver)),
+		DSN:      strings.TrimSpace(m.DSN),
+		ReadOnly: m.ReadOnly,
+	}
+
+	if cfg.Driver == "" {
+		cfg.Driver = DriverPostgres
+	}
+
+	if cfg.Driver != DriverPostgres {
+		return cfg, fmt.Errorf("database driver %q not supported, use postgres", m.Driver)
+	}
+
+	if v := strings.TrimSpace(m.MaxOpenConns); v != "" {
+		maxOpenConns, err := strconv.Atoi(v)
+		if err != nil || maxOpenConns < 0 {
+			return cfg, fmt.Errorf("database max open connections %q is not valid", v)
+		}
+
+		cfg.MaxOpenConns = maxOpenConns
+	}
+
+	if cfg.DSN != "" {
+		if !cfg.ReadOnly {
+			return cfg, nil
+		}
+
+		dsn, err := readOnlyDSN(cfg.DSN)
+		if err != nil {
+			return cfg, err
+		}
+
+		cfg.DSN = dsn
+
+		return cfg, nil
+	}
+
+	host := strings.TrimSpace(m.Host)
+	if host == "" {
+		return cfg, errors.New("database host is empty")
+	}
+
+	port := strings.TrimSpace(m.Port)
+	if port == "" {
+		port = "5432"
+	}
+
+	if p, err := strconv.Atoi(port); err != nil || p <= 0 || p > 65535 {
+		return cfg, fmt.Errorf("database port %q is not valid", m.Port)
+	}
+
+	sslMode := strings.TrimSpace(m.SSLMode)
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	query := url.Values{"sslmode": []string{sslMode}}
+	if cfg.ReadOnly {
+		query.Set(readOnlyParam, "on")
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(strings.TrimSpace(m.User), m.Password),
+		Host:     host + ":" + port,
+		Path:     "/" + strings.TrimSpace(m.DBName),
+		RawQuery: query.Encode(),
+	}
+
+	cfg.DSN = dsn.String()
+
+	return cfg, nil
+}
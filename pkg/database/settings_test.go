@@ -0,0 +1,60 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/worldline-go/chore/pkg/models"
+)
+
+func TestParseSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       models.Database
+		want    Config
+		wantErr bool
+	}{
+		{
+			name: "fields",
+			m:    models.Database{Host: "db", User: "chore", Password: "p@ss", DBName: "orders", MaxOpenConns: "5", ReadOnly: true},
+			want: Config{Driver: DriverPostgres, DSN: "postgres://chore:p%40ss@db:5432/orders?default_transaction_read_only=on&sslmode=disable", MaxOpenConns: 5, ReadOnly: true},
+		},
+		{
+			name: "dsn",
+			m:    models.Database{Driver: "Postgres", DSN: "host=db user=chore dbname=orders"},
+			want: Config{Driver: DriverPostgres, DSN: "host=db user=chore dbname=orders"},
+		},
+		{
+			name: "dsn read only",
+			m:    models.Database{DSN: "host=db user=chore dbname=orders", ReadOnly: true},
+			want: Config{Driver: DriverPostgres, DSN: "host=db user=chore dbname=orders default_transaction_read_only=on", ReadOnly: true},
+		},
+		{
+			name: "dsn url read only",
+			m:    models.Database{DSN: "postgres://chore@db/orders?sslmode=require", ReadOnly: true},
+			want: Config{Driver: DriverPostgres, DSN: "postgres://chore@db/orders?default_transaction_read_only=on&sslmode=require", ReadOnly: true},
+		},
+		{
+			name:    "driver",
+			m:       models.Database{Driver: "oracle", DSN: "db"},
+			wantErr: true,
+		},
+		{
+			name:    "host",
+			m:       models.Database{User: "chore"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSettings(tt.m)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package email
 
 import (
+	"crypto/tls"
+	"fmt"
 	"io"
 	"time"
 
@@ -9,41 +11,136 @@ import (
 
 var EmailTimeout = 1 * time.Minute
 
+// AttachmentsMaxSize is default limit of total attachments size in bytes.
+var AttachmentsMaxSize int64 = 10 << 20
+
+// TLS modes of the SMTP connection.
+const (
+	// TLSModeAuto uses SSL on port 465 and STARTTLS when server supports it.
+	TLSModeAuto = ""
+	// TLSModeSSL connects with implicit TLS.
+	TLSModeSSL = "ssl"
+	// TLSModeStartTLS requires STARTTLS.
+	TLSModeStartTLS = "starttls"
+	// TLSModeNone sends without TLS.
+	TLSModeNone = "none"
+)
+
+// Config of the SMTP connection.
+type Config struct {
+	Host     string
+	Port     int
+	NoAuth   bool
+	Email    string
+	Password string
+	// TLSMode is one of the TLSMode values.
+	TLSMode    string
+	SkipVerify bool
+	// LocalName is hostname sent with HELO, default is localhost.
+	LocalName string
+	// Timeout of the connection, default is EmailTimeout.
+	Timeout time.Duration
+}
+
 type Client struct {
 	d *gomail.Dialer
 }
 
 type Attach struct {
 	FileName string
-	Content  io.Reader
+	// ContentType of the file, empty uses type of the file extension.
+	ContentType string
+	Content     io.Reader
 }
 
-func NewClient(host string, port int, noAuth bool, mail, password string) Client {
+func NewClient(cfg Config) (Client, error) {
 	var mailDialer *gomail.Dialer
 
-	if noAuth {
-		mailDialer = &gomail.Dialer{Host: host, Port: port}
+	if cfg.NoAuth {
+		mailDialer = &gomail.Dialer{Host: cfg.Host, Port: cfg.Port, SSL: cfg.Port == 465} //nolint:gomnd // smtps port
 	} else {
-		mailDialer = gomail.NewDialer(host, port, mail, password)
+		mailDialer = gomail.NewDialer(cfg.Host, cfg.Port, cfg.Email, cfg.Password)
 	}
 
+	switch cfg.TLSMode {
+	case TLSModeAuto:
+	case TLSModeSSL:
+		mailDialer.SSL = true
+	case TLSModeStartTLS:
+		mailDialer.SSL = false
+		mailDialer.StartTLSPolicy = gomail.MandatoryStartTLS
+	case TLSModeNone:
+		mailDialer.SSL = false
+		mailDialer.StartTLSPolicy = gomail.NoStartTLS
+	default:
+		return Client{}, fmt.Errorf("email tls mode %q not supported", cfg.TLSMode)
+	}
+
+	if cfg.SkipVerify {
+		mailDialer.TLSConfig = &tls.Config{ServerName: cfg.Host, InsecureSkipVerify: true} //nolint:gosec // user option
+	}
+
+	mailDialer.LocalName = cfg.LocalName
+
 	mailDialer.Timeout = EmailTimeout
+	if cfg.Timeout > 0 {
+		mailDialer.Timeout = cfg.Timeout
+	}
 
-	return Client{mailDialer}
+	return Client{mailDialer}, nil
+}
+
+// Message to send, HTML is the main body and Text is plain-text alternative.
+type Message struct {
+	HTML    []byte
+	Text    []byte
+	Headers map[string][]string
+	// Attachments are files of the email.
+	Attachments []Attach
+	// Inline files are referenced in HTML with cid:FileName.
+	Inline []Attach
 }
 
 // Send with headers.
 // Headers should not be empty string array!
 func (c *Client) Send(msg []byte, headers map[string][]string, attachments []Attach) error {
+	return c.SendMessage(Message{HTML: msg, Headers: headers, Attachments: attachments})
+}
+
+// SendMessage sends multipart/alternative when both HTML and Text exist.
+func (c *Client) SendMessage(msg Message) error {
 	m := gomail.NewMessage()
 
-	m.SetHeaders(headers)
+	m.SetHeaders(msg.Headers)
 
-	for _, attach := range attachments {
-		m.AttachReader(attach.FileName, attach.Content)
+	for _, attach := range msg.Attachments {
+		m.AttachReader(attach.FileName, attach.Content, attach.settings()...)
 	}
 
-	m.SetBody("text/html", string(msg))
+	for _, inline := range msg.Inline {
+		m.EmbedReader(inline.FileName, inline.Content, inline.settings()...)
+	}
+
+	switch {
+	case len(msg.Text) > 0 && len(msg.HTML) > 0:
+		// last part is preferred one
+		m.SetBody("text/plain", string(msg.Text))
+		m.AddAlternative("text/html", string(msg.HTML))
+	case len(msg.Text) > 0:
+		m.SetBody("text/plain", string(msg.Text))
+	default:
+		m.SetBody("text/html", string(msg.HTML))
+	}
 
 	return c.d.DialAndSend(m)
 }
+
+func (a Attach) settings() []gomail.FileSetting {
+	if a.ContentType == "" {
+		return nil
+	}
+
+	return []gomail.FileSetting{gomail.SetHeader(map[string][]string{
+		"Content-Type": {a.ContentType},
+	})}
+}
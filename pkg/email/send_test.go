@@ -1,6 +1,10 @@
 package email
 
 import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -11,13 +15,13 @@ func TestClient_Send(t *testing.T) {
 	}
 	tests := []struct {
 		name    string
-		client  Client
+		config  Config
 		args    args
 		wantErr bool
 	}{
 		{
 			name:   "test",
-			client: NewClient("smtp.office365.com", 587, false, "eray.ates@ingenico.com", "---"),
+			config: Config{Host: "smtp.office365.com", Port: 587, Email: "eray.ates@ingenico.com", Password: "---"},
 			args: args{
 				msg: []byte("<h1>this is test</h1>"),
 				headers: map[string][]string{
@@ -32,9 +36,132 @@ func TestClient_Send(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// SKIP THIS TEST
 			t.SkipNow()
-			if err := tt.client.Send(tt.args.msg, tt.args.headers, nil); (err != nil) != tt.wantErr {
+			client, err := NewClient(tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := client.Send(tt.args.msg, tt.args.headers, nil); (err != nil) != tt.wantErr {
 				t.Errorf("Client.Send() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+// smtpServer is a local SMTP stand-in, returns address and channel of the received data.
+func smtpServer(t *testing.T) (string, <-chan string) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { l.Close() })
+
+	received := make(chan string, 1)
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(s string) { _, _ = conn.Write([]byte(s + "\r\n")) }
+
+		reply("220 localhost ready")
+
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "DATA"):
+				reply("354 end with .")
+
+				var data strings.Builder
+
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+
+					if l == ".\r\n" {
+						break
+					}
+
+					data.WriteString(l)
+				}
+
+				received <- data.String()
+
+				reply("250 queued")
+			case strings.HasPrefix(cmd, "QUIT"):
+				reply("221 bye")
+
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	return l.Addr().String(), received
+}
+
+func TestClient_SendMessage(t *testing.T) {
+	addr, received := smtpServer(t)
+
+	host, portRaw, _ := net.SplitHostPort(addr)
+	port, _ := strconv.Atoi(portRaw)
+
+	client, err := NewClient(Config{Host: host, Port: port, NoAuth: true, TLSMode: TLSModeNone})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = client.SendMessage(Message{
+		HTML: []byte(`<p>report</p><img src="cid:logo.png">`),
+		Text: []byte("report"),
+		Headers: map[string][]string{
+			"From":             {"chore@example.com"},
+			"To":               {"team@example.com"},
+			"Subject":          {"daily"},
+			"Reply-To":         {"support@example.com"},
+			"X-Priority":       {"1"},
+			"List-Unsubscribe": {"<mailto:unsubscribe@example.com>"},
+		},
+		Inline: []Attach{{FileName: "logo.png", ContentType: "image/png", Content: strings.NewReader("png")}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := <-received
+
+	for _, want := range []string{
+		"multipart/related",
+		"multipart/alternative",
+		"Content-Type: text/plain",
+		"Content-Type: text/html",
+		"Content-ID: <logo.png>",
+		"Reply-To: support@example.com",
+		"X-Priority: 1",
+		"List-Unsubscribe: <mailto:unsubscribe@example.com>",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("message has not %q:\n%s", want, data)
+		}
+	}
+
+	if strings.Index(data, "text/plain") > strings.Index(data, "text/html") {
+		t.Errorf("plain text should be before html")
+	}
+}
@@ -0,0 +1,64 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/worldline-go/chore/pkg/models"
+)
+
+// ParseSettings checks stored email profile and returns connection config.
+func ParseSettings(m models.Email) (Config, error) {
+	cfg := Config{
+		Host:       strings.TrimSpace(m.Host),
+		NoAuth:     m.NoAuth,
+		Email:      strings.TrimSpace(m.Email),
+		Password:   m.Password,
+		TLSMode:    strings.ToLower(strings.TrimSpace(m.TLSMode)),
+		SkipVerify: m.SkipVerify,
+		LocalName:  strings.TrimSpace(m.LocalName),
+	}
+
+	if cfg.Host == "" {
+		return cfg, errors.New("email host is empty")
+	}
+
+	port, err := strconv.Atoi(strings.TrimSpace(m.Port))
+	if err != nil || port <= 0 || port > 65535 {
+		return cfg, fmt.Errorf("email port %q is not valid", m.Port)
+	}
+
+	cfg.Port = port
+
+	switch cfg.TLSMode {
+	case TLSModeAuto, TLSModeSSL, TLSModeStartTLS, TLSModeNone:
+	default:
+		return cfg, fmt.Errorf("email tls mode %q not supported, use ssl, starttls or none", m.TLSMode)
+	}
+
+	if cfg.Email != "" {
+		if _, err := mail.ParseAddress(cfg.Email); err != nil {
+			return cfg, fmt.Errorf("email address %q is not valid: %w", cfg.Email, err)
+		}
+	} else if !cfg.NoAuth {
+		return cfg, errors.New("email address is empty")
+	}
+
+	if replyTo := strings.TrimSpace(m.ReplyTo); replyTo != "" {
+		if _, err := mail.ParseAddressList(replyTo); err != nil {
+			return cfg, fmt.Errorf("email reply-to %q is not valid: %w", replyTo, err)
+		}
+	}
+
+	if timeout := strings.TrimSpace(m.Timeout); timeout != "" {
+		if cfg.Timeout, err = time.ParseDuration(timeout); err != nil {
+			return cfg, fmt.Errorf("email timeout %q cannot parse: %w", timeout, err)
+		}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,75 @@
+package email
+
+import (
+	"testing"
+	"time"
+
+	"github.com/worldline-go/chore/pkg/models"
+)
+
+func TestParseSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       models.Email
+		want    Config
+		wantErr bool
+	}{
+		{
+			name: "starttls profile",
+			m: models.Email{
+				Host: "smtp.example.com", Port: "587", Email: "team@example.com", Password: "x",
+				TLSMode: "STARTTLS", LocalName: "chore.local", Timeout: "30s", ReplyTo: "support@example.com",
+			},
+			want: Config{
+				Host: "smtp.example.com", Port: 587, Email: "team@example.com", Password: "x",
+				TLSMode: TLSModeStartTLS, LocalName: "chore.local", Timeout: 30 * time.Second,
+			},
+		},
+		{
+			name: "relay without auth",
+			m:    models.Email{Host: "relay", Port: "25", NoAuth: true, TLSMode: "none"},
+			want: Config{Host: "relay", Port: 25, NoAuth: true, TLSMode: TLSModeNone},
+		},
+		{
+			name:    "port",
+			m:       models.Email{Host: "smtp.example.com", Port: "smtp", Email: "team@example.com"},
+			wantErr: true,
+		},
+		{
+			name:    "tls mode",
+			m:       models.Email{Host: "smtp.example.com", Port: "587", Email: "team@example.com", TLSMode: "tls"},
+			wantErr: true,
+		},
+		{
+			name:    "reply-to",
+			m:       models.Email{Host: "smtp.example.com", Port: "587", Email: "team@example.com", ReplyTo: "support"},
+			wantErr: true,
+		},
+		{
+			name:    "email with auth",
+			m:       models.Email{Host: "smtp.example.com", Port: "587"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSettings(tt.m)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseSettings() = %+v, want %+v", got, tt.want)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if _, err := NewClient(got); err != nil {
+				t.Errorf("NewClient() error = %v", err)
+			}
+		})
+	}
+}
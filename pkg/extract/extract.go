@@ -0,0 +1,112 @@
+package extract
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/itchyny/gojq"
+
+	"github.com/worldline-go/chore/pkg/transfer"
+)
+
+// Extract types.
+const (
+	TypeJQ       = "jq"
+	TypeJSONPath = "jsonpath"
+)
+
+var ErrTypeNotSupported = errors.New("extract type not supported")
+
+// Extractor runs a compiled jq or JSONPath expression.
+type Extractor struct {
+	jq       *gojq.Code
+	jsonPath string
+}
+
+// New compiles expression, empty type is jq.
+func New(extractType, expression string) (*Extractor, error) {
+	switch extractType {
+	case "", TypeJQ:
+		query, err := gojq.Parse(expression)
+		if err != nil {
+			return nil, fmt.Errorf("jq parse: %w", err)
+		}
+
+		code, err := gojq.Compile(query)
+		if err != nil {
+			return nil, fmt.Errorf("jq compile: %w", err)
+		}
+
+		return &Extractor{jq: code}, nil
+	case TypeJSONPath:
+		// validate expression
+		if _, err := jsonpath.New(expression); err != nil {
+			return nil, fmt.Errorf("jsonpath parse: %w", err)
+		}
+
+		return &Extractor{jsonPath: expression}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrTypeNotSupported, extractType)
+}
+
+// Run returns extracted value, jq returns list when there are more than one result.
+func (e *Extractor) Run(data interface{}) (interface{}, error) {
+	if e.jq == nil {
+		v, err := jsonpath.Get(e.jsonPath, data)
+		if err != nil {
+			return nil, fmt.Errorf("jsonpath: %w", err)
+		}
+
+		return v, nil
+	}
+
+	var results []interface{}
+
+	iter := e.jq.Run(data)
+
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+
+		if err, ok := v.(error); ok {
+			var haltErr *gojq.HaltError
+			if errors.As(err, &haltErr) && haltErr.Value() == nil {
+				break
+			}
+
+			return nil, fmt.Errorf("jq: %w", err)
+		}
+
+		results = append(results, v)
+	}
+
+	switch len(results) {
+	case 0:
+		return nil, nil
+	case 1:
+		return results[0], nil
+	}
+
+	return results, nil
+}
+
+// Bytes extracts from json/yaml bytes and returns result as bytes.
+func (e *Extractor) Bytes(value []byte) ([]byte, error) {
+	var data interface{}
+
+	if err := json.Unmarshal(value, &data); err != nil {
+		data = transfer.BytesToData(value)
+	}
+
+	result, err := e.Run(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer.DataToBytes(result), nil
+}
@@ -0,0 +1,73 @@
+package extract
+
+import "testing"
+
+func TestExtractor_Bytes(t *testing.T) {
+	value := []byte(`{"issues": [{"key": "CHORE-1", "points": 3}, {"key": "CHORE-2", "points": 5}], "total": 2}`)
+
+	tests := []struct {
+		name        string
+		extractType string
+		expression  string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:       "jq field",
+			expression: ".total",
+			want:       "2",
+		},
+		{
+			name:        "jq list",
+			extractType: TypeJQ,
+			expression:  "[.issues[].key]",
+			want:        `["CHORE-1","CHORE-2"]`,
+		},
+		{
+			name:       "jq multiple results",
+			expression: ".issues[] | .key",
+			want:       `["CHORE-1","CHORE-2"]`,
+		},
+		{
+			name:       "jq string",
+			expression: ".issues[0].key",
+			want:       "CHORE-1",
+		},
+		{
+			name:        "jsonpath",
+			extractType: TypeJSONPath,
+			expression:  "$.issues[*].points",
+			want:        "[3,5]",
+		},
+		{
+			name:        "jsonpath missing",
+			extractType: TypeJSONPath,
+			expression:  "$.missing",
+			wantErr:     true,
+		},
+		{
+			name:        "unknown type",
+			extractType: "xpath",
+			expression:  "/issues",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.extractType, tt.expression)
+			if err == nil {
+				var got []byte
+
+				got, err = e.Bytes(value)
+				if err == nil && string(got) != tt.want {
+					t.Errorf("Extractor.Bytes() = %s, want %s", got, tt.want)
+				}
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Extractor.Bytes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
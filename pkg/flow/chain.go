@@ -0,0 +1,86 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrCallDepth = errors.New("max call depth exceeded")
+	ErrCallCycle = errors.New("call cycle detected")
+)
+
+const CtxCallChain ContextType = "call_chain"
+
+// CallSettings limits nested control calls.
+type CallSettings struct {
+	// MaxDepth is maximum number of nested controls, 0 means no limit.
+	MaxDepth int
+	// AllowRecursion disables cycle detection, MaxDepth still protects.
+	AllowRecursion bool
+}
+
+var Calls = CallSettings{
+	MaxDepth: 10, //nolint:gomnd // default depth
+}
+
+// Call is one control in the call chain.
+type Call struct {
+	Control  string
+	Endpoint string
+	RunID    string
+}
+
+func (c Call) String() string {
+	return c.Control + "/" + c.Endpoint
+}
+
+type CallChain []Call
+
+func (c CallChain) String() string {
+	calls := make([]string, 0, len(c))
+	for _, call := range c {
+		calls = append(calls, call.String())
+	}
+
+	return strings.Join(calls, " -> ")
+}
+
+// ParentRunID returns durable run id of the caller.
+func (c CallChain) ParentRunID() string {
+	if len(c) < 2 { //nolint:gomnd // caller exist
+		return ""
+	}
+
+	return c[len(c)-2].RunID
+}
+
+// GetCallChain returns controls called until now.
+func GetCallChain(ctx context.Context) CallChain {
+	v, _ := ctx.Value(CtxCallChain).(CallChain)
+
+	return v
+}
+
+// addCall checks depth and cycle and returns new chain with the call.
+func addCall(chain CallChain, call Call, settings CallSettings) (CallChain, error) {
+	newChain := make(CallChain, 0, len(chain)+1)
+	newChain = append(newChain, chain...)
+	newChain = append(newChain, call)
+
+	if settings.MaxDepth > 0 && len(newChain) > settings.MaxDepth {
+		return nil, fmt.Errorf("%w %d: %s", ErrCallDepth, settings.MaxDepth, newChain)
+	}
+
+	if !settings.AllowRecursion {
+		for _, c := range chain {
+			if c.Control == call.Control && c.Endpoint == call.Endpoint {
+				return nil, fmt.Errorf("%w: %s", ErrCallCycle, newChain)
+			}
+		}
+	}
+
+	return newChain, nil
+}
@@ -0,0 +1,73 @@
+package flow
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAddCall(t *testing.T) {
+	chain := CallChain{
+		{Control: "deploy", Endpoint: "production", RunID: "1"},
+		{Control: "notify", Endpoint: "mail", RunID: "2"},
+	}
+
+	tests := []struct {
+		name     string
+		call     Call
+		settings CallSettings
+		wantLen  int
+		wantErr  error
+	}{
+		{
+			name:     "new call",
+			call:     Call{Control: "audit", Endpoint: "log"},
+			settings: CallSettings{MaxDepth: 10},
+			wantLen:  3,
+		},
+		{
+			name:     "cycle",
+			call:     Call{Control: "deploy", Endpoint: "production"},
+			settings: CallSettings{MaxDepth: 10},
+			wantErr:  ErrCallCycle,
+		},
+		{
+			name:     "same control other endpoint",
+			call:     Call{Control: "deploy", Endpoint: "test"},
+			settings: CallSettings{MaxDepth: 10},
+			wantLen:  3,
+		},
+		{
+			name:     "recursion allowed",
+			call:     Call{Control: "deploy", Endpoint: "production"},
+			settings: CallSettings{MaxDepth: 10, AllowRecursion: true},
+			wantLen:  3,
+		},
+		{
+			name:     "max depth",
+			call:     Call{Control: "audit", Endpoint: "log"},
+			settings: CallSettings{MaxDepth: 2, AllowRecursion: true},
+			wantErr:  ErrCallDepth,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := addCall(chain, tt.call, tt.settings)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("addCall() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if len(got) != tt.wantLen {
+				t.Errorf("addCall() len = %d, want %d", len(got), tt.wantLen)
+			}
+		})
+	}
+
+	if got := chain.ParentRunID(); got != "1" {
+		t.Errorf("CallChain.ParentRunID() = %v, want 1", got)
+	}
+
+	if got := chain.String(); got != "deploy/production -> notify/mail" {
+		t.Errorf("CallChain.String() = %v", got)
+	}
+}
@@ -1,6 +1,10 @@
 package convert
 
-import "strings"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 func GetBoolean(value interface{}) bool {
 	switch v := value.(type) {
@@ -13,6 +17,31 @@ func GetBoolean(value interface{}) bool {
 	}
 }
 
+// GetInt returns integer of number or string value, empty value is 0.
+func GetInt(value interface{}) (int, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return v, nil
+	case float64:
+		return int(v), nil
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return 0, nil
+		}
+
+		i, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, fmt.Errorf("value %s cannot convert to integer", v)
+		}
+
+		return i, nil
+	default:
+		return 0, fmt.Errorf("value %v cannot convert to integer", v)
+	}
+}
+
 func GetList(value interface{}) []string {
 	switch v := value.(type) {
 	case []string:
@@ -91,3 +91,32 @@ func TestGetBoolean(t *testing.T) {
 		})
 	}
 }
+
+func TestGetInt(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    int
+		wantErr bool
+	}{
+		{name: "nil", value: nil, want: 0},
+		{name: "empty string", value: " ", want: 0},
+		{name: "string", value: "25", want: 25},
+		{name: "float", value: float64(10), want: 10},
+		{name: "int", value: 3, want: 3},
+		{name: "wrong string", value: "ten", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetInt(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetInt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("GetInt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,603 @@
+package flow
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+
+	"github.com/worldline-go/chore/pkg/models"
+	"github.com/worldline-go/chore/pkg/models/apimodels"
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+// DurableSettings for checkpointing runs to the database.
+type DurableSettings struct {
+	Enabled bool
+	// LockDuration is lease of a run, other instances resume it after expired.
+	LockDuration time.Duration
+	// CheckInterval is period to search unfinished and suspended runs.
+	CheckInterval time.Duration
+	// SuspendAfter is minimum waiting time to suspend a node instead of waiting in memory.
+	SuspendAfter time.Duration
+}
+
+var Durable = DurableSettings{
+	LockDuration:  time.Minute,
+	CheckInterval: 30 * time.Second,
+	SuspendAfter:  5 * time.Minute,
+}
+
+// Validate checks intervals of the enabled durable runs, zero values cannot tick.
+func (s DurableSettings) Validate() error {
+	if !s.Enabled {
+		return nil
+	}
+
+	if s.LockDuration <= 0 {
+		return fmt.Errorf("durable lock_duration should be greater than zero, got %v", s.LockDuration)
+	}
+
+	if s.CheckInterval <= 0 {
+		return fmt.Errorf("durable check_interval should be greater than zero, got %v", s.CheckInterval)
+	}
+
+	return nil
+}
+
+// InstanceID is owner of the durable runs of this process.
+var InstanceID = uuid.NewString()
+
+var (
+	ErrSuspended  = fmt.Errorf("%w: suspended", ErrStopGoroutine)
+	ErrNotDurable = errors.New("run is not durable")
+)
+
+const (
+	CtxTaskID    ContextType = "task_id"
+	CtxResumedAt ContextType = "resumed_at"
+	CtxDeadline  ContextType = "deadline"
+)
+
+// resumeBatch is maximum number of runs to resume in one check.
+const resumeBatch = 100
+
+var durableTrigger = make(chan struct{}, 1)
+
+// TaskID returns checkpoint id of the running node.
+func TaskID(ctx context.Context) string {
+	v, _ := ctx.Value(CtxTaskID).(string)
+
+	return v
+}
+
+// ResumedAt returns wake time of a suspended node.
+func ResumedAt(ctx context.Context) (time.Time, bool) {
+	v, ok := ctx.Value(CtxResumedAt).(time.Time)
+
+	return v, ok
+}
+
+// Deadline returns recorded deadline of a waiting node before the restart.
+func Deadline(ctx context.Context) (time.Time, bool) {
+	v, ok := ctx.Value(CtxDeadline).(time.Time)
+
+	return v, ok
+}
+
+type durableRet struct {
+	data   []byte
+	values []byte
+}
+
+func (r *durableRet) GetBinaryData() []byte {
+	return r.data
+}
+
+func (r *durableRet) GetBinaryValues() []byte {
+	return r.values
+}
+
+type durableRetRespond struct {
+	durableRet
+	respond Respond
+}
+
+func (r *durableRetRespond) GetRespondData() Respond {
+	return r.respond
+}
+
+type durableRun struct {
+	db     *gorm.DB
+	runID  uuid.UUID
+	cancel context.CancelFunc
+}
+
+func newDurableRun(ctx context.Context, db *gorm.DB, controlName, endpoint, method, parentRunID string) (*durableRun, error) {
+	id := uuid.New()
+	lockedUntil := time.Now().Add(Durable.LockDuration)
+
+	result := db.WithContext(ctx).Create(&models.Run{
+		RunPure: models.RunPure{
+			Control:     controlName,
+			Endpoint:    endpoint,
+			Method:      method,
+			ParentRunID: parentRunID,
+			Status:      models.RunRunning,
+			Owner:       InstanceID,
+			LockedUntil: &lockedUntil,
+		},
+		ModelCU: apimodels.ModelCU{
+			ID: apimodels.ID{ID: id},
+		},
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to create run: %w", result.Error)
+	}
+
+	return &durableRun{
+		db:    db,
+		runID: id,
+	}, nil
+}
+
+// heartbeat extends the lease of the run until finish.
+func (d *durableRun) heartbeat(ctx context.Context, wg *sync.WaitGroup) {
+	ctx, d.cancel = context.WithCancel(ctx)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(Durable.LockDuration / 3) //nolint:gomnd // extend before expire
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				lockedUntil := time.Now().Add(Durable.LockDuration)
+
+				result := d.db.WithContext(ctx).Model(&models.Run{}).
+					Where("id = ?", d.runID).Where("owner = ?", InstanceID).
+					Update("locked_until", &lockedUntil)
+				if result.Error != nil {
+					log.Ctx(ctx).Warn().Err(result.Error).Msg("durable run lock cannot extend")
+				}
+			}
+		}
+	}()
+}
+
+func (d *durableRun) addTask(ctx context.Context, next Connection, value NodeRet) (string, error) {
+	task := models.RunTask{
+		RunID:  d.runID,
+		Node:   next.Node,
+		Input:  next.Output,
+		Status: models.TaskPending,
+		Data:   value.GetBinaryData(),
+		ModelCU: apimodels.ModelCU{
+			ID: apimodels.ID{ID: uuid.New()},
+		},
+	}
+
+	if v, ok := value.(NodeRetValues); ok {
+		task.Values = v.GetBinaryValues()
+	}
+
+	if v, ok := value.(NodeRetRespondData); ok {
+		respond, err := json.Marshal(v.GetRespondData())
+		if err != nil {
+			return "", fmt.Errorf("respond data cannot marshal: %w", err)
+		}
+
+		task.Respond = respond
+	}
+
+	if result := d.db.WithContext(ctx).Create(&task); result.Error != nil {
+		return "", fmt.Errorf("failed to create task: %w", result.Error)
+	}
+
+	return task.ID.ID.String(), nil
+}
+
+// afterRun records result of the node run.
+func (d *durableRun) afterRun(ctx context.Context, taskID, node string, err error) {
+	ctx = context.WithoutCancel(ctx)
+
+	switch {
+	case errors.Is(err, ErrSuspended):
+		return
+	case errors.Is(err, ErrStopGoroutine):
+		// value waiting in the node for other inputs
+		d.setTaskStatus(ctx, taskID, models.TaskHeld)
+
+		return
+	case err != nil:
+		d.setTaskStatus(ctx, taskID, models.TaskDone)
+
+		return
+	}
+
+	// node consumed held values
+	result := d.db.WithContext(ctx).Model(&models.RunTask{}).
+		Where("run_id = ?", d.runID).
+		Where("(id = ?) OR (node = ? AND status = ?)", taskID, node, models.TaskHeld).
+		Update("status", models.TaskDone)
+	if result.Error != nil {
+		log.Ctx(ctx).Warn().Err(result.Error).Msg("durable task cannot update")
+	}
+}
+
+func (d *durableRun) setTaskStatus(ctx context.Context, taskID, status string) {
+	result := d.db.WithContext(ctx).Model(&models.RunTask{}).Where("id = ?", taskID).Update("status", status)
+	if result.Error != nil {
+		log.Ctx(ctx).Warn().Err(result.Error).Msg("durable task cannot update")
+	}
+}
+
+// finish set last status of the run.
+// Closing application leaves run as running to resume it later.
+func (d *durableRun) finish(ctx context.Context, errs []error) {
+	if d.cancel != nil {
+		d.cancel()
+	}
+
+	canceled := ctx.Err() != nil
+	ctx = context.WithoutCancel(ctx)
+
+	query := d.db.WithContext(ctx).Model(&models.Run{}).Where("id = ?", d.runID)
+
+	if canceled {
+		now := time.Now()
+		if result := query.Update("locked_until", &now); result.Error != nil {
+			log.Ctx(ctx).Warn().Err(result.Error).Msg("durable run lock cannot release")
+		}
+
+		return
+	}
+
+	suspended := struct {
+		Count  int64
+		WakeAt *time.Time
+	}{}
+
+	result := d.db.WithContext(ctx).Model(&models.RunTask{}).
+		Select("count(*) as count, min(not_before) as wake_at").
+		Where("run_id = ?", d.runID).Where("status = ?", models.TaskSuspended).
+		Scan(&suspended)
+	if result.Error != nil {
+		log.Ctx(ctx).Error().Err(result.Error).Msg("durable run suspended tasks cannot get")
+
+		return
+	}
+
+	if suspended.Count > 0 {
+		result := query.Updates(map[string]interface{}{
+			"status":       models.RunSuspended,
+			"wake_at":      suspended.WakeAt,
+			"owner":        "",
+			"locked_until": nil,
+		})
+		if result.Error != nil {
+			log.Ctx(ctx).Error().Err(result.Error).Msg("durable run cannot suspend")
+		}
+
+		log.Ctx(ctx).Info().Msg("suspended control flow")
+
+		return
+	}
+
+	status := models.RunCompleted
+	errMsgs := make([]string, 0, len(errs))
+
+	for _, err := range errs {
+		errMsgs = append(errMsgs, err.Error())
+	}
+
+	if len(errMsgs) > 0 {
+		status = models.RunFailed
+	}
+
+	now := time.Now()
+
+	result = query.Updates(map[string]interface{}{
+		"status":       status,
+		"errors":       strings.Join(errMsgs, "\n"),
+		"finished_at":  &now,
+		"owner":        "",
+		"locked_until": nil,
+		"wake_at":      nil,
+	})
+	if result.Error != nil {
+		log.Ctx(ctx).Error().Err(result.Error).Msg("durable run cannot finish")
+	}
+
+	// checkpoints not needed anymore
+	if result := d.db.WithContext(ctx).Where("run_id = ?", d.runID).Delete(&models.RunTask{}); result.Error != nil {
+		log.Ctx(ctx).Warn().Err(result.Error).Msg("durable run tasks cannot delete")
+	}
+}
+
+// IsDurable returns true if running node has a checkpoint.
+func (r *NodesReg) IsDurable(ctx context.Context) bool {
+	return r.durable != nil && TaskID(ctx) != ""
+}
+
+// RunID returns durable run id.
+func (r *NodesReg) RunID() string {
+	if r.durable == nil {
+		return ""
+	}
+
+	return r.durable.runID.String()
+}
+
+// Suspend records running node to continue at wakeAt and returns ErrSuspended to stop the node.
+// Nil wakeAt waits a Wake call.
+func (r *NodesReg) Suspend(ctx context.Context, wakeAt *time.Time) error {
+	taskID := TaskID(ctx)
+	if r.durable == nil || taskID == "" {
+		return ErrNotDurable
+	}
+
+	result := r.durable.db.WithContext(ctx).Model(&models.RunTask{}).Where("id = ?", taskID).
+		Updates(map[string]interface{}{
+			"status":     models.TaskSuspended,
+			"not_before": wakeAt,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to suspend: %w", result.Error)
+	}
+
+	log.Ctx(ctx).Info().Msgf("suspended node until %v", wakeAt)
+
+	return ErrSuspended
+}
+
+// SetDeadline records end of the waiting in memory, resumed node gets it with Deadline.
+func (r *NodesReg) SetDeadline(ctx context.Context, deadline time.Time) error {
+	taskID := TaskID(ctx)
+	if r.durable == nil || taskID == "" {
+		return ErrNotDurable
+	}
+
+	result := r.durable.db.WithContext(ctx).Model(&models.RunTask{}).Where("id = ?", taskID).
+		Update("not_before", &deadline)
+	if result.Error != nil {
+		return fmt.Errorf("failed to set deadline: %w", result.Error)
+	}
+
+	return nil
+}
+
+// Wake makes suspended task of the run due now.
+func Wake(ctx context.Context, db *gorm.DB, runID, taskID string) error {
+	now := time.Now()
+
+	result := db.WithContext(ctx).Model(&models.RunTask{}).
+		Where("id = ?", taskID).Where("status = ?", models.TaskSuspended).
+		Update("not_before", &now)
+	if result.Error != nil {
+		return fmt.Errorf("failed to wake task: %w", result.Error)
+	}
+
+	result = db.WithContext(ctx).Model(&models.Run{}).
+		Where("id = ?", runID).Where("status = ?", models.RunSuspended).
+		Update("wake_at", &now)
+	if result.Error != nil {
+		return fmt.Errorf("failed to wake run: %w", result.Error)
+	}
+
+	select {
+	case durableTrigger <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// StartDurable resumes unfinished runs and suspended runs periodically.
+func StartDurable(ctx context.Context, wg *sync.WaitGroup, appStore *registry.Registry) {
+	if !Durable.Enabled {
+		return
+	}
+
+	logDurable := log.With().Str("component", "durable").Logger()
+	ctx = logDurable.WithContext(ctx)
+
+	logDurable.Info().Str("instance", InstanceID).Msg("durable runs enabled")
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(Durable.CheckInterval)
+		defer ticker.Stop()
+
+		for {
+			resumeRuns(ctx, wg, appStore)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-durableTrigger:
+			}
+		}
+	}()
+}
+
+func resumeRuns(ctx context.Context, wg *sync.WaitGroup, appStore *registry.Registry) {
+	now := time.Now()
+
+	var runs []models.Run
+
+	result := appStore.DB.WithContext(ctx).Model(&models.Run{}).
+		Where("(status = ? AND locked_until < ?) OR (status = ? AND wake_at <= ?)",
+			models.RunRunning, now, models.RunSuspended, now).
+		Limit(resumeBatch).
+		Find(&runs)
+	if result.Error != nil {
+		log.Ctx(ctx).Error().Err(result.Error).Msg("unfinished runs cannot get")
+
+		return
+	}
+
+	for i := range runs {
+		lockedUntil := time.Now().Add(Durable.LockDuration)
+
+		// claim the run, other instances can try at same time
+		result := appStore.DB.WithContext(ctx).Model(&models.Run{}).
+			Where("id = ?", runs[i].ID.ID).
+			Where("(status = ? AND locked_until < ?) OR (status = ? AND wake_at <= ?)",
+				models.RunRunning, now, models.RunSuspended, now).
+			Updates(map[string]interface{}{
+				"status":       models.RunRunning,
+				"owner":        InstanceID,
+				"locked_until": &lockedUntil,
+			})
+		if result.Error != nil {
+			log.Ctx(ctx).Error().Err(result.Error).Msg("run cannot claim")
+
+			continue
+		}
+
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		if err := ResumeRun(ctx, wg, runs[i], appStore); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("run", runs[i].ID.ID.String()).Msg("run cannot resume")
+
+			failRun(ctx, appStore.DB, runs[i].ID.ID, err)
+		}
+	}
+}
+
+func failRun(ctx context.Context, db *gorm.DB, runID uuid.UUID, err error) {
+	now := time.Now()
+
+	result := db.WithContext(ctx).Model(&models.Run{}).Where("id = ?", runID).
+		Updates(map[string]interface{}{
+			"status":       models.RunFailed,
+			"errors":       err.Error(),
+			"finished_at":  &now,
+			"owner":        "",
+			"locked_until": nil,
+		})
+	if result.Error != nil {
+		log.Ctx(ctx).Error().Err(result.Error).Msg("run cannot fail")
+	}
+}
+
+// ResumeRun starts run again from checkpoints with the current content of the control.
+func ResumeRun(ctx context.Context, wg *sync.WaitGroup, run models.Run, appStore *registry.Registry) error {
+	control := models.Control{}
+
+	result := appStore.DB.WithContext(ctx).Where("name = ?", run.Control).First(&control)
+	if result.Error != nil {
+		return fmt.Errorf("control %s cannot get: %w", run.Control, result.Error)
+	}
+
+	content, err := base64.StdEncoding.DecodeString(control.Content)
+	if err != nil {
+		return fmt.Errorf("failed to decode content; %w", err)
+	}
+
+	nodesData, err := ParseData(content)
+	if err != nil {
+		return err
+	}
+
+	ctx = log.Ctx(ctx).With().
+		Str("control", run.Control).Str("endpoint", run.Endpoint).Str("run", run.ID.ID.String()).
+		Logger().WithContext(ctx)
+
+	nodesReg, err := DataToNode(ctx, run.Control, run.Endpoint, run.Method, nodesData, appStore)
+	if err != nil {
+		return err
+	}
+
+	if err := VisitAndFetch(ctx, nodesReg); err != nil {
+		nodesReg.runCleanup()
+
+		return err
+	}
+
+	var tasks []models.RunTask
+
+	result = appStore.DB.WithContext(ctx).Model(&models.RunTask{}).
+		Where("run_id = ?", run.ID.ID).
+		Where("status IN ?", []string{models.TaskPending, models.TaskHeld, models.TaskSuspended}).
+		Order("created_at").
+		Find(&tasks)
+	if result.Error != nil {
+		nodesReg.runCleanup()
+
+		return fmt.Errorf("tasks cannot get: %w", result.Error)
+	}
+
+	nodesReg.durable = &durableRun{
+		db:    appStore.DB,
+		runID: run.ID.ID,
+	}
+	nodesReg.durable.heartbeat(ctx, wg)
+
+	// caller of the run not exist anymore
+	ctx = context.WithValue(ctx, CtxCallChain, CallChain{
+		{Control: run.Control, Endpoint: run.Endpoint, RunID: run.ID.ID.String()},
+	})
+
+	log.Ctx(ctx).Info().Msgf("resume control flow with %d tasks", len(tasks))
+
+	now := time.Now()
+
+	wg.Add(1)
+	go goAndRun(ctx, wg, nodesReg, func() {
+		for i := range tasks {
+			task := &tasks[i]
+			ctxTask := ctx
+
+			if task.Status == models.TaskSuspended {
+				if task.NotBefore == nil || task.NotBefore.After(now) {
+					continue
+				}
+
+				ctxTask = context.WithValue(ctx, CtxResumedAt, *task.NotBefore)
+			} else if task.NotBefore != nil {
+				ctxTask = context.WithValue(ctx, CtxDeadline, *task.NotBefore)
+			}
+
+			var value NodeRet = &durableRet{data: task.Data, values: task.Values}
+
+			if len(task.Respond) > 0 {
+				var respond Respond
+				if err := json.Unmarshal(task.Respond, &respond); err == nil {
+					value = &durableRetRespond{
+						durableRet: durableRet{data: task.Data, values: task.Values},
+						respond:    respond,
+					}
+				}
+			}
+
+			nodesReg.durable.setTaskStatus(ctx, task.ID.ID.String(), models.TaskPending)
+
+			nodesReg.wgx.Add(1)
+			nodesReg.UpdateStuck(CountTotalIncrease, false)
+
+			go branchRun(ctxTask, Connection{Node: task.Node, Output: task.Input}, nodesReg, value, task.ID.ID.String())
+		}
+	})
+
+	return nil
+}
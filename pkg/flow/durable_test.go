@@ -0,0 +1,410 @@
+package flow
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/worldline-go/chore/pkg/models"
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+func mockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db, mock
+}
+
+// expectUpdate is an update in the default transaction of gorm.
+func expectUpdate(mock sqlmock.Sqlmock, query string, args ...driver.Value) {
+	mock.ExpectBegin()
+
+	exec := mock.ExpectExec(query)
+	if len(args) > 0 {
+		exec = exec.WithArgs(args...)
+	}
+
+	exec.WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+}
+
+func waitExpectations(t *testing.T, mock sqlmock.Sqlmock) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		err := mock.ExpectationsWereMet()
+		if err == nil {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal(err)
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func setDurable(t *testing.T, settings DurableSettings) {
+	t.Helper()
+
+	old := Durable
+	Durable = settings
+
+	t.Cleanup(func() { Durable = old })
+}
+
+func TestDurableSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings DurableSettings
+		wantErr  bool
+	}{
+		{name: "disabled", settings: DurableSettings{}},
+		{name: "valid", settings: DurableSettings{Enabled: true, LockDuration: time.Minute, CheckInterval: time.Second}},
+		{name: "zero lock", settings: DurableSettings{Enabled: true, CheckInterval: time.Second}, wantErr: true},
+		{name: "zero check", settings: DurableSettings{Enabled: true, LockDuration: time.Minute}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.settings.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("DurableSettings.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDurableRun_checkpoint(t *testing.T) {
+	db, mock := mockDB(t)
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "runs"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	d, err := newDurableRun(ctx, db, "deploy", "production", "POST", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "run_tasks"`).
+		WithArgs(d.runID, "2", "input_1", models.TaskPending, []byte("hello"), []byte(nil), nil,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	taskID, err := d.addTask(ctx, Connection{Node: "2", Output: "input_1"}, &nodeRetOutput{[]byte("hello")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// suspended node already recorded its status
+	d.afterRun(ctx, taskID, "2", ErrSuspended)
+
+	// waiting other inputs
+	expectUpdate(mock, `UPDATE "run_tasks" SET "status"=\$1,"updated_at"=\$2 WHERE id = \$3`,
+		models.TaskHeld, sqlmock.AnyArg(), taskID)
+	d.afterRun(ctx, taskID, "2", ErrStopGoroutine)
+
+	// failed node not run again
+	expectUpdate(mock, `UPDATE "run_tasks" SET "status"=\$1,"updated_at"=\$2 WHERE id = \$3`,
+		models.TaskDone, sqlmock.AnyArg(), taskID)
+	d.afterRun(ctx, taskID, "2", errors.New("failed"))
+
+	// completed node consumes held values
+	expectUpdate(mock, `UPDATE "run_tasks" SET "status"=\$1,"updated_at"=\$2 WHERE run_id = \$3 AND \(\(id = \$4\) OR \(node = \$5 AND status = \$6\)\)`,
+		models.TaskDone, sqlmock.AnyArg(), d.runID, taskID, "2", models.TaskHeld)
+	d.afterRun(ctx, taskID, "2", nil)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDurableRun_heartbeat(t *testing.T) {
+	setDurable(t, DurableSettings{Enabled: true, LockDuration: 30 * time.Millisecond})
+
+	db, mock := mockDB(t)
+	d := &durableRun{db: db, runID: uuid.New()}
+
+	expectUpdate(mock, `UPDATE "runs" SET "locked_until"=\$1,"updated_at"=\$2 WHERE id = \$3 AND owner = \$4`,
+		sqlmock.AnyArg(), sqlmock.AnyArg(), d.runID, InstanceID)
+
+	wg := &sync.WaitGroup{}
+	d.heartbeat(context.Background(), wg)
+
+	waitExpectations(t, mock)
+
+	d.cancel()
+	wg.Wait()
+}
+
+func TestDurableRun_finish(t *testing.T) {
+	t.Run("completed", func(t *testing.T) {
+		db, mock := mockDB(t)
+		d := &durableRun{db: db, runID: uuid.New()}
+
+		mock.ExpectQuery(`SELECT count\(\*\) as count, min\(not_before\) as wake_at FROM "run_tasks"`).
+			WithArgs(d.runID, models.TaskSuspended).
+			WillReturnRows(sqlmock.NewRows([]string{"count", "wake_at"}).AddRow(0, nil))
+		expectUpdate(mock, `UPDATE "runs" SET "errors"=\$1,"finished_at"=\$2,"locked_until"=\$3,"owner"=\$4,"status"=\$5,"wake_at"=\$6,"updated_at"=\$7 WHERE id = \$8`,
+			"node failed", sqlmock.AnyArg(), nil, "", models.RunFailed, nil, sqlmock.AnyArg(), d.runID)
+		expectUpdate(mock, `DELETE FROM "run_tasks" WHERE run_id = \$1`, d.runID)
+
+		d.finish(context.Background(), []error{errors.New("node failed")})
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("suspended", func(t *testing.T) {
+		db, mock := mockDB(t)
+		d := &durableRun{db: db, runID: uuid.New()}
+		wakeAt := time.Now().Add(time.Hour)
+
+		mock.ExpectQuery(`SELECT count\(\*\) as count, min\(not_before\) as wake_at FROM "run_tasks"`).
+			WillReturnRows(sqlmock.NewRows([]string{"count", "wake_at"}).AddRow(1, wakeAt))
+		expectUpdate(mock, `UPDATE "runs" SET "locked_until"=\$1,"owner"=\$2,"status"=\$3,"wake_at"=\$4,"updated_at"=\$5 WHERE id = \$6`,
+			nil, "", models.RunSuspended, &wakeAt, sqlmock.AnyArg(), d.runID)
+
+		d.finish(context.Background(), nil)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		db, mock := mockDB(t)
+		d := &durableRun{db: db, runID: uuid.New()}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// lease released, run stays running for other instances
+		expectUpdate(mock, `UPDATE "runs" SET "locked_until"=\$1,"updated_at"=\$2 WHERE id = \$3`,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), d.runID)
+
+		d.finish(ctx, nil)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestNodesReg_SuspendWake(t *testing.T) {
+	db, mock := mockDB(t)
+	reg := NewNodesReg("deploy", "production", "POST", &registry.Registry{DB: db})
+	ctx := context.WithValue(context.Background(), CtxTaskID, "task-1")
+
+	if err := reg.Suspend(ctx, nil); !errors.Is(err, ErrNotDurable) {
+		t.Errorf("Suspend() error = %v, want ErrNotDurable", err)
+	}
+
+	reg.durable = &durableRun{db: db, runID: uuid.New()}
+	wakeAt := time.Now().Add(time.Hour)
+
+	expectUpdate(mock, `UPDATE "run_tasks" SET "not_before"=\$1,"status"=\$2,"updated_at"=\$3 WHERE id = \$4`,
+		wakeAt, models.TaskSuspended, sqlmock.AnyArg(), "task-1")
+
+	if err := reg.Suspend(ctx, &wakeAt); !errors.Is(err, ErrSuspended) || !errors.Is(err, ErrStopGoroutine) {
+		t.Errorf("Suspend() error = %v, want ErrSuspended", err)
+	}
+
+	expectUpdate(mock, `UPDATE "run_tasks" SET "not_before"=\$1,"updated_at"=\$2 WHERE id = \$3`,
+		sqlmock.AnyArg(), sqlmock.AnyArg(), "task-1")
+
+	if err := reg.SetDeadline(ctx, wakeAt); err != nil {
+		t.Fatal(err)
+	}
+
+	expectUpdate(mock, `UPDATE "run_tasks" SET "not_before"=\$1,"updated_at"=\$2 WHERE id = \$3 AND status = \$4`,
+		sqlmock.AnyArg(), sqlmock.AnyArg(), "task-1", models.TaskSuspended)
+	expectUpdate(mock, `UPDATE "runs" SET "wake_at"=\$1,"updated_at"=\$2 WHERE id = \$3 AND status = \$4`,
+		sqlmock.AnyArg(), sqlmock.AnyArg(), "run-1", models.RunSuspended)
+
+	if err := Wake(ctx, db, "run-1", "task-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-durableTrigger:
+	default:
+		t.Error("Wake() not triggered resume check")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// durableTestNode records values, it is start node for "start" endpoint.
+type durableTestNode struct {
+	next     []Connection
+	received chan durableTestValue
+	checked  bool
+	nodeID   string
+}
+
+type durableTestValue struct {
+	data     string
+	taskID   string
+	deadline time.Time
+}
+
+func (n *durableTestNode) Run(ctx context.Context, _ *sync.WaitGroup, _ *registry.Registry, value NodeRet, _ string) (NodeRet, error) {
+	deadline, _ := Deadline(ctx)
+	n.received <- durableTestValue{data: string(value.GetBinaryData()), taskID: TaskID(ctx), deadline: deadline}
+
+	return value, nil
+}
+
+func (n *durableTestNode) GetType() string                         { return "durableTest" }
+func (n *durableTestNode) Fetch(context.Context, *gorm.DB) error   { return nil }
+func (n *durableTestNode) IsFetched() bool                         { return true }
+func (n *durableTestNode) Validate(context.Context) error          { return nil }
+func (n *durableTestNode) ActiveInput(string, map[string]struct{}) {}
+func (n *durableTestNode) Next(int) []Connection                   { return n.next }
+func (n *durableTestNode) NextCount() int                          { return 1 }
+func (n *durableTestNode) IsRespond() bool                         { return false }
+func (n *durableTestNode) Check()                                  { n.checked = true }
+func (n *durableTestNode) IsChecked() bool                         { return n.checked }
+func (n *durableTestNode) IsDisabled() bool                        { return false }
+func (n *durableTestNode) NodeID() string                          { return n.nodeID }
+func (n *durableTestNode) Tags() []string                          { return nil }
+func (n *durableTestNode) Endpoint() string                        { return "start" }
+func (n *durableTestNode) Methods() []string                       { return []string{"POST"} }
+
+func TestResumeRuns(t *testing.T) {
+	setDurable(t, DurableSettings{Enabled: true, LockDuration: time.Hour, CheckInterval: time.Hour})
+
+	received := make(chan durableTestValue, 1)
+
+	NodeTypes["durableTest"] = func(_ context.Context, _ *NodesReg, data NodeData, nodeID string) (Noder, error) {
+		var next []Connection
+		if outputs := PrepareOutputs(data.Outputs); len(outputs) > 0 {
+			next = outputs[0]
+		}
+
+		return &durableTestNode{next: next, received: received, nodeID: nodeID}, nil
+	}
+	defer delete(NodeTypes, "durableTest")
+
+	content := base64.StdEncoding.EncodeToString([]byte(`{
+		"1": {"name": "durableTest", "data": {}, "outputs": {"output_1": {"connections": [{"node": "2", "output": "input_1"}]}}},
+		"2": {"name": "durableTest", "data": {}, "outputs": {}}
+	}`))
+
+	db, mock := mockDB(t)
+	appStore := &registry.Registry{DB: db}
+
+	claimed := uuid.New()
+	taken := uuid.New()
+	taskID := uuid.New()
+	deadline := time.Now().Add(time.Minute).Round(time.Second)
+
+	mock.ExpectQuery(`SELECT \* FROM "runs" WHERE \(status = \$1 AND locked_until < \$2\) OR \(status = \$3 AND wake_at <= \$4\) LIMIT 100`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "control", "endpoint", "method", "status"}).
+			AddRow(taken, "deploy", "start", "POST", models.RunRunning).
+			AddRow(claimed, "deploy", "start", "POST", models.RunRunning))
+
+	// other instance claimed the first one
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "runs" SET "locked_until"=\$1,"owner"=\$2,"status"=\$3,"updated_at"=\$4 WHERE id = \$5`).
+		WithArgs(sqlmock.AnyArg(), InstanceID, models.RunRunning, sqlmock.AnyArg(), taken,
+			models.RunRunning, sqlmock.AnyArg(), models.RunSuspended, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	expectUpdate(mock, `UPDATE "runs" SET "locked_until"=\$1,"owner"=\$2,"status"=\$3,"updated_at"=\$4 WHERE id = \$5`)
+
+	mock.ExpectQuery(`SELECT \* FROM "controls" WHERE name = \$1`).
+		WithArgs("deploy").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "content"}).AddRow("deploy", content))
+
+	mock.ExpectQuery(`SELECT \* FROM "run_tasks" WHERE run_id = \$1 AND status IN \(\$2,\$3,\$4\) ORDER BY created_at`).
+		WithArgs(claimed, models.TaskPending, models.TaskHeld, models.TaskSuspended).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "run_id", "node", "input", "status", "data", "not_before"}).
+			AddRow(taskID, claimed, "2", "input_1", models.TaskPending, []byte("checkpoint"), deadline))
+
+	expectUpdate(mock, `UPDATE "run_tasks" SET "status"=\$1,"updated_at"=\$2 WHERE id = \$3`,
+		models.TaskPending, sqlmock.AnyArg(), taskID.String())
+	expectUpdate(mock, `UPDATE "run_tasks" SET "status"=\$1,"updated_at"=\$2 WHERE run_id = \$3`)
+	mock.ExpectQuery(`SELECT count\(\*\) as count`).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "wake_at"}).AddRow(0, nil))
+	expectUpdate(mock, `UPDATE "runs" SET "errors"=\$1,.*"status"=\$5`,
+		"", sqlmock.AnyArg(), nil, "", models.RunCompleted, nil, sqlmock.AnyArg(), claimed)
+	expectUpdate(mock, `DELETE FROM "run_tasks" WHERE run_id = \$1`, claimed)
+
+	wg := &sync.WaitGroup{}
+	resumeRuns(context.Background(), wg, appStore)
+	wg.Wait()
+
+	select {
+	case v := <-received:
+		if v.data != "checkpoint" || v.taskID != taskID.String() || !v.deadline.Equal(deadline) {
+			t.Errorf("resumed node got %+v", v)
+		}
+	default:
+		t.Error("resumed node not run")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestResumeRuns_fail(t *testing.T) {
+	setDurable(t, DurableSettings{Enabled: true, LockDuration: time.Hour, CheckInterval: time.Hour})
+
+	db, mock := mockDB(t)
+	runID := uuid.New()
+
+	mock.ExpectQuery(`SELECT \* FROM "runs"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "control", "endpoint", "method", "status"}).
+			AddRow(runID, "removed", "start", "POST", models.RunSuspended))
+	expectUpdate(mock, `UPDATE "runs" SET "locked_until"=\$1,"owner"=\$2,"status"=\$3,"updated_at"=\$4 WHERE id = \$5`)
+	mock.ExpectQuery(`SELECT \* FROM "controls" WHERE name = \$1`).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "content"}))
+
+	// control not exist anymore, run cannot continue
+	expectUpdate(mock, `UPDATE "runs" SET "errors"=\$1,"finished_at"=\$2,"locked_until"=\$3,"owner"=\$4,"status"=\$5,"updated_at"=\$6 WHERE id = \$7`,
+		sqlmock.AnyArg(), sqlmock.AnyArg(), nil, "", models.RunFailed, sqlmock.AnyArg(), runID)
+
+	wg := &sync.WaitGroup{}
+	resumeRuns(context.Background(), wg, &registry.Registry{DB: db})
+	wg.Wait()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
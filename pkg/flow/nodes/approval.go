@@ -0,0 +1,361 @@
+package nodes
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+
+	"github.com/worldline-go/chore/pkg/approval"
+	"github.com/worldline-go/chore/pkg/flow"
+	"github.com/worldline-go/chore/pkg/flow/convert"
+	"github.com/worldline-go/chore/pkg/models"
+	"github.com/worldline-go/chore/pkg/models/apimodels"
+	"github.com/worldline-go/chore/pkg/registry"
+	"github.com/worldline-go/chore/pkg/transfer"
+)
+
+var approvalType = "approval"
+
+// ApprovalPollInterval is checking period of the decision in database.
+var ApprovalPollInterval = 10 * time.Second
+
+type ApprovalRet struct {
+	output    []byte
+	selection []int
+}
+
+func (r *ApprovalRet) GetBinaryData() []byte {
+	return r.output
+}
+
+func (r *ApprovalRet) GetSelection() []int {
+	return r.selection
+}
+
+var _ flow.NodeRetSelection = (*ApprovalRet)(nil)
+
+// Approval node has one input and four outputs.
+// Outputs are approved, rejected, timeout and notify.
+type Approval struct {
+	reg          *flow.NodesReg
+	groups       []string
+	timeout      string
+	outputs      [][]flow.Connection
+	stuckContext context.Context
+	checked      bool
+	disabled     bool
+	nodeID       string
+	tags         []string
+}
+
+// Run records a pending approval and waits the decision.
+func (n *Approval) Run(ctx context.Context, _ *sync.WaitGroup, reg *registry.Registry, value flow.NodeRet, _ string) (flow.NodeRet, error) {
+	durable := n.reg.IsDurable(ctx)
+
+	if durable {
+		// resumed node has already a record
+		var record models.Approval
+
+		result := reg.DB.WithContext(ctx).Where("task_id = ?", flow.TaskID(ctx)).Limit(1).Find(&record)
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to get approval: %w", result.Error)
+		}
+
+		if result.RowsAffected > 0 {
+			ctx = log.Ctx(ctx).With().Str("approval", record.ID.ID.String()).Logger().WithContext(ctx)
+
+			return n.suspend(ctx, reg.DB, record.ID.ID.String(), record.ExpiresAt, value)
+		}
+	}
+
+	data := transfer.BytesToData(value.GetBinaryData())
+
+	timeoutRendered, err := renderValue(reg, n.timeout, data)
+	if err != nil {
+		return nil, fmt.Errorf("timeout %w", err)
+	}
+
+	var expiresAt *time.Time
+
+	if timeoutRendered = strings.TrimSpace(timeoutRendered); timeoutRendered != "" {
+		timeout, err := time.ParseDuration(timeoutRendered)
+		if err != nil {
+			return nil, fmt.Errorf("timeout %q cannot parse: %w", timeoutRendered, err)
+		}
+
+		expires := time.Now().Add(timeout)
+		expiresAt = &expires
+	}
+
+	groups, err := json.Marshal(n.groups)
+	if err != nil {
+		return nil, fmt.Errorf("groups cannot marshal: %w", err)
+	}
+
+	id := uuid.New()
+
+	record := models.Approval{
+		ApprovalPure: models.ApprovalPure{
+			Control:   n.reg.ControlName(),
+			Endpoint:  n.reg.StartName(),
+			NodeID:    n.nodeID,
+			Status:    models.ApprovalPending,
+			Value:     base64.StdEncoding.EncodeToString(value.GetBinaryData()),
+			ExpiresAt: expiresAt,
+			RunID:     n.reg.RunID(),
+			TaskID:    flow.TaskID(ctx),
+			Groups:    apimodels.Groups{Groups: groups},
+		},
+		ModelCU: apimodels.ModelCU{
+			ID: apimodels.ID{ID: id},
+		},
+	}
+
+	if result := reg.DB.WithContext(ctx).Create(&record); result.Error != nil {
+		return nil, fmt.Errorf("failed to create approval: %w", result.Error)
+	}
+
+	ctx = log.Ctx(ctx).With().Str("approval", id.String()).Logger().WithContext(ctx)
+	log.Ctx(ctx).Info().Msg("waiting approval")
+
+	// notify output
+	if len(n.outputs) > 3 {
+		n.reg.Branch(ctx, n.Next(3), &ApprovalRet{
+			output: transfer.DataToBytes(map[string]interface{}{
+				"id":         id.String(),
+				"control":    record.Control,
+				"endpoint":   record.Endpoint,
+				"node_id":    record.NodeID,
+				"groups":     n.groups,
+				"expires_at": expiresAt,
+				"value":      data,
+			}),
+		})
+	}
+
+	if durable {
+		return n.suspend(ctx, reg.DB, id.String(), expiresAt, value)
+	}
+
+	status, err := n.wait(ctx, reg.DB, id.String(), expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return approvalResult(ctx, status, value), nil
+}
+
+// suspend stops the node until decision or expire time in durable runs.
+func (n *Approval) suspend(ctx context.Context, db *gorm.DB, id string, expiresAt *time.Time, value flow.NodeRet) (flow.NodeRet, error) {
+	status, err := approvalStatus(ctx, db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if status == models.ApprovalPending && expiresAt != nil && !expiresAt.After(time.Now()) {
+		if status, err = expireApproval(ctx, db, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if status != models.ApprovalPending {
+		return approvalResult(ctx, status, value), nil
+	}
+
+	if err := n.reg.Suspend(ctx, expiresAt); !errors.Is(err, flow.ErrSuspended) {
+		return nil, err
+	}
+
+	// decision can come before suspend recorded
+	if status, err := approvalStatus(ctx, db, id); err == nil && status != models.ApprovalPending {
+		if err := flow.Wake(ctx, db, n.reg.RunID(), flow.TaskID(ctx)); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("approval cannot wake")
+		}
+	}
+
+	return nil, flow.ErrSuspended
+}
+
+func approvalResult(ctx context.Context, status string, value flow.NodeRet) flow.NodeRet {
+	log.Ctx(ctx).Info().Msgf("approval %s", status)
+
+	switch status {
+	case models.ApprovalApproved:
+		return &ApprovalRet{output: value.GetBinaryData(), selection: []int{0}}
+	case models.ApprovalRejected:
+		return &ApprovalRet{output: value.GetBinaryData(), selection: []int{1}}
+	default:
+		return &ApprovalRet{output: value.GetBinaryData(), selection: []int{2}}
+	}
+}
+
+// wait until decision comes, local decisions wake up directly, others catch with polling.
+func (n *Approval) wait(ctx context.Context, db *gorm.DB, id string, expiresAt *time.Time) (string, error) {
+	notify := approval.GlobalNotifier.Wait(id)
+	defer approval.GlobalNotifier.Remove(id)
+
+	ticker := time.NewTicker(ApprovalPollInterval)
+	defer ticker.Stop()
+
+	var timeoutChan <-chan time.Time
+
+	if expiresAt != nil {
+		timer := time.NewTimer(time.Until(*expiresAt))
+		defer timer.Stop()
+
+		timeoutChan = timer.C
+	}
+
+	for {
+		select {
+		case <-notify:
+			// closed channel, check it once
+			notify = nil
+		case <-ticker.C:
+		case <-timeoutChan:
+			return expireApproval(ctx, db, id)
+		case <-n.stuckContext.Done():
+			log.Ctx(ctx).Warn().Msg("stuck detected, terminated node approval")
+
+			return "", flow.ErrStopGoroutine
+		case <-ctx.Done():
+			log.Ctx(ctx).Warn().Msg("program closed, terminated node approval")
+
+			return "", flow.ErrStopGoroutine
+		}
+
+		status, err := approvalStatus(ctx, db, id)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("approval status check")
+
+			continue
+		}
+
+		if status != models.ApprovalPending {
+			return status, nil
+		}
+	}
+}
+
+func approvalStatus(ctx context.Context, db *gorm.DB, id string) (string, error) {
+	var record models.ApprovalPure
+
+	result := db.WithContext(ctx).Model(&models.Approval{}).Select("status").Where("id = ?", id).First(&record)
+	if result.Error != nil {
+		return "", fmt.Errorf("failed to get approval: %w", result.Error)
+	}
+
+	return record.Status, nil
+}
+
+// expireApproval set timeout status if it is still pending.
+func expireApproval(ctx context.Context, db *gorm.DB, id string) (string, error) {
+	now := time.Now()
+
+	result := db.WithContext(ctx).Model(&models.Approval{}).
+		Where("id = ?", id).Where("status = ?", models.ApprovalPending).
+		Updates(map[string]interface{}{
+			"status":     models.ApprovalTimeout,
+			"decided_at": &now,
+		})
+	if result.Error != nil {
+		return "", fmt.Errorf("failed to expire approval: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		// decision given at same time
+		return approvalStatus(ctx, db, id)
+	}
+
+	return models.ApprovalTimeout, nil
+}
+
+func (n *Approval) GetType() string {
+	return approvalType
+}
+
+func (n *Approval) Fetch(_ context.Context, _ *gorm.DB) error {
+	return nil
+}
+
+func (n *Approval) IsFetched() bool {
+	return true
+}
+
+func (n *Approval) IsRespond() bool {
+	return false
+}
+
+func (n *Approval) Validate(ctx context.Context) error {
+	n.stuckContext = n.reg.GetStuctCancel(ctx)
+
+	return nil
+}
+
+func (n *Approval) Next(i int) []flow.Connection {
+	return n.outputs[i]
+}
+
+func (n *Approval) NextCount() int {
+	return len(n.outputs)
+}
+
+func (n *Approval) IsDisabled() bool {
+	return n.disabled
+}
+
+func (n *Approval) ActiveInput(_ string, tags map[string]struct{}) {
+	if !convert.IsTagsEnabled(n.tags, tags) {
+		n.disabled = true
+
+		return
+	}
+}
+
+func (n *Approval) Check() {
+	n.checked = true
+}
+
+func (n *Approval) IsChecked() bool {
+	return n.checked
+}
+
+func (n *Approval) NodeID() string {
+	return n.nodeID
+}
+
+func (n *Approval) Tags() []string {
+	return n.tags
+}
+
+func NewApproval(_ context.Context, reg *flow.NodesReg, data flow.NodeData, nodeID string) (flow.Noder, error) {
+	outputs := flow.PrepareOutputs(data.Outputs)
+
+	groups := convert.GetList(data.Data["groups"])
+	timeout, _ := data.Data["timeout"].(string)
+
+	tags := convert.GetList(data.Data["tags"])
+
+	return &Approval{
+		reg:     reg,
+		outputs: outputs,
+		groups:  groups,
+		timeout: timeout,
+		nodeID:  nodeID,
+		tags:    tags,
+	}, nil
+}
+
+//nolint:gochecknoinits // moduler nodes
+func init() {
+	flow.NodeTypes[approvalType] = NewApproval
+}
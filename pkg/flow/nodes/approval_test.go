@@ -0,0 +1,113 @@
+package nodes
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/rytsh/mugo/pkg/templatex"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/worldline-go/chore/pkg/flow"
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+func TestApproval_Run(t *testing.T) {
+	defer func(v time.Duration) { ApprovalPollInterval = v }(ApprovalPollInterval)
+	ApprovalPollInterval = 10 * time.Millisecond
+
+	tests := []struct {
+		name      string
+		timeout   string
+		expect    func(mock sqlmock.Sqlmock)
+		selection []int
+	}{
+		{
+			name: "approved",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT "status" FROM "approvals"`).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("approved"))
+			},
+			selection: []int{0},
+		},
+		{
+			name: "rejected",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT "status" FROM "approvals"`).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("pending"))
+				mock.ExpectQuery(`SELECT "status" FROM "approvals"`).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("rejected"))
+			},
+			selection: []int{1},
+		},
+		{
+			name:    "timeout",
+			timeout: "{{ .wait }}",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT "status" FROM "approvals"`).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("pending"))
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "approvals" SET .*"status"=\$\d+`).
+					WithArgs(sqlmock.AnyArg(), "timeout", sqlmock.AnyArg(), sqlmock.AnyArg(), "pending").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			selection: []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer sqlDB.Close()
+
+			db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			reg := &registry.Registry{Template: templatex.New(), DB: db}
+			ctx := context.Background()
+
+			n := &Approval{
+				reg:     flow.NewNodesReg("deploy", "production", "POST", reg),
+				groups:  []string{"deploy"},
+				timeout: tt.timeout,
+				outputs: make([][]flow.Connection, 3),
+				nodeID:  "1",
+			}
+
+			if err := n.Validate(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			mock.ExpectBegin()
+			mock.ExpectExec(`INSERT INTO "approvals"`).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+			tt.expect(mock)
+
+			ret, err := n.Run(ctx, nil, reg, &EndpointRet{output: []byte(`{"wait":"30ms"}`)}, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := ret.(*ApprovalRet); !reflect.DeepEqual(got.selection, tt.selection) || string(got.output) != `{"wait":"30ms"}` {
+				t.Errorf("Approval.Run() selection = %v, output = %s", got.selection, got.output)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
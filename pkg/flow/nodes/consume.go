@@ -0,0 +1,463 @@
+package nodes
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+
+	"github.com/worldline-go/chore/pkg/broker"
+	"github.com/worldline-go/chore/pkg/flow"
+	"github.com/worldline-go/chore/pkg/flow/convert"
+	"github.com/worldline-go/chore/pkg/models"
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+var consumeType = "consume"
+
+// ConsumeMethod is method of the flows started with consume trigger.
+const ConsumeMethod = "CONSUME"
+
+var (
+	// ConsumeCheckInterval is period to sync consumers with saved controls.
+	ConsumeCheckInterval = 30 * time.Second
+	// ConsumeRestartDelay is wait before connecting again after a consumer failed.
+	ConsumeRestartDelay = 10 * time.Second
+)
+
+// consumeConfig is consumer settings of a consume node.
+type consumeConfig struct {
+	nodeID      string
+	endpoint    string
+	profile     string
+	topic       string
+	group       string
+	concurrency int
+	attempts    int
+	retryDelay  time.Duration
+	deadLetter  string
+	// maxDeliveries drops failed message without dead letter after broker delivered it that many times.
+	maxDeliveries int
+}
+
+func newConsumeConfig(data flow.NodeData, nodeID string) (consumeConfig, error) {
+	cfg := consumeConfig{
+		nodeID:        nodeID,
+		group:         "chore",
+		concurrency:   1,
+		attempts:      3, //nolint:gomnd // default attempts
+		retryDelay:    time.Second,
+		maxDeliveries: 5, //nolint:gomnd // default max deliveries
+	}
+
+	cfg.endpoint, _ = data.Data["endpoint"].(string)
+	cfg.profile, _ = data.Data["profile"].(string)
+	cfg.topic, _ = data.Data["topic"].(string)
+	cfg.deadLetter, _ = data.Data["dead_letter"].(string)
+
+	cfg.endpoint = strings.TrimSpace(cfg.endpoint)
+	if cfg.endpoint == "" {
+		cfg.endpoint = "consume-" + nodeID
+	}
+
+	if cfg.profile = strings.TrimSpace(cfg.profile); cfg.profile == "" {
+		return cfg, fmt.Errorf("consume %s profile is empty", cfg.endpoint)
+	}
+
+	if cfg.topic = strings.TrimSpace(cfg.topic); cfg.topic == "" {
+		return cfg, fmt.Errorf("consume %s topic is empty", cfg.endpoint)
+	}
+
+	if v, _ := data.Data["group"].(string); strings.TrimSpace(v) != "" {
+		cfg.group = strings.TrimSpace(v)
+	}
+
+	cfg.deadLetter = strings.TrimSpace(cfg.deadLetter)
+
+	for _, v := range []struct {
+		key   string
+		value *int
+	}{
+		{key: "concurrency", value: &cfg.concurrency},
+		{key: "attempts", value: &cfg.attempts},
+		{key: "max_deliveries", value: &cfg.maxDeliveries},
+	} {
+		raw := data.Data[v.key]
+		if raw == nil || strings.TrimSpace(fmt.Sprint(raw)) == "" {
+			continue
+		}
+
+		i, err := strconv.Atoi(strings.TrimSpace(fmt.Sprint(raw)))
+		if err != nil || i <= 0 {
+			return cfg, fmt.Errorf("consume %s %s %q is not valid", cfg.endpoint, v.key, fmt.Sprint(raw))
+		}
+
+		*v.value = i
+	}
+
+	if v, _ := data.Data["retry_delay"].(string); strings.TrimSpace(v) != "" {
+		retryDelay, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			return cfg, fmt.Errorf("consume %s retry delay %q cannot parse: %w", cfg.endpoint, v, err)
+		}
+
+		cfg.retryDelay = retryDelay
+	}
+
+	return cfg, nil
+}
+
+// Consume node has one output, starts the flow for each message of the topic.
+type Consume struct {
+	config   consumeConfig
+	err      error
+	outputs  [][]flow.Connection
+	checked  bool
+	disabled bool
+	nodeID   string
+	tags     []string
+}
+
+var _ flow.NoderEndpoint = (*Consume)(nil)
+
+func (n *Consume) Run(_ context.Context, _ *sync.WaitGroup, _ *registry.Registry, value flow.NodeRet, _ string) (flow.NodeRet, error) {
+	return &EndpointRet{output: value.GetBinaryData()}, nil
+}
+
+func (n *Consume) GetType() string {
+	return consumeType
+}
+
+func (n *Consume) Fetch(_ context.Context, _ *gorm.DB) error {
+	return nil
+}
+
+func (n *Consume) IsFetched() bool {
+	return true
+}
+
+func (n *Consume) IsRespond() bool {
+	return false
+}
+
+func (n *Consume) Validate(_ context.Context) error {
+	return n.err
+}
+
+func (n *Consume) Next(i int) []flow.Connection {
+	return n.outputs[i]
+}
+
+func (n *Consume) NextCount() int {
+	return len(n.outputs)
+}
+
+func (n *Consume) Check() {
+	n.checked = true
+}
+
+func (n *Consume) IsChecked() bool {
+	return n.checked
+}
+
+func (n *Consume) IsDisabled() bool {
+	return n.disabled
+}
+
+func (n *Consume) ActiveInput(string, map[string]struct{}) {}
+
+func (n *Consume) Endpoint() string {
+	return n.config.endpoint
+}
+
+func (n *Consume) Methods() []string {
+	return []string{ConsumeMethod}
+}
+
+func (n *Consume) Tags() []string {
+	return n.tags
+}
+
+func (n *Consume) NodeID() string {
+	return n.nodeID
+}
+
+func NewConsume(_ context.Context, _ *flow.NodesReg, data flow.NodeData, nodeID string) (flow.Noder, error) {
+	cfg, err := newConsumeConfig(data, nodeID)
+
+	return &Consume{
+		config:  cfg,
+		err:     err,
+		outputs: flow.PrepareOutputs(data.Outputs),
+		nodeID:  nodeID,
+		tags:    convert.GetList(data.Data["tags"]),
+	}, nil
+}
+
+//nolint:gochecknoinits // moduler nodes
+func init() {
+	flow.NodeTypes[consumeType] = NewConsume
+}
+
+// consumers are running consume triggers of this instance with control and node id key.
+var consumers = struct {
+	mutex   sync.Mutex
+	running map[string]*runningConsumer
+	reload  chan struct{}
+}{
+	running: make(map[string]*runningConsumer),
+	reload:  make(chan struct{}, 1),
+}
+
+type runningConsumer struct {
+	// version changes when control content changed
+	version string
+	cancel  context.CancelFunc
+}
+
+// ReloadConsumers syncs consumers with controls without waiting check interval.
+// Call it after a control saved or deleted.
+func ReloadConsumers() {
+	select {
+	case consumers.reload <- struct{}{}:
+	default:
+	}
+}
+
+// StartConsumers runs consume triggers of all controls and keeps them in sync with saved controls.
+// Changed triggers restart, removed triggers stop after their messages in process.
+func StartConsumers(ctx context.Context, wg *sync.WaitGroup, appStore *registry.Registry) {
+	logConsume := log.With().Str("component", consumeType).Logger()
+	ctx = logConsume.WithContext(ctx)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(ConsumeCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			syncConsumers(ctx, wg, appStore)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-consumers.reload:
+			}
+		}
+	}()
+}
+
+func syncConsumers(ctx context.Context, wg *sync.WaitGroup, appStore *registry.Registry) {
+	var controls []models.Control
+
+	result := appStore.DB.WithContext(ctx).Model(&models.Control{}).Select("name", "content").Find(&controls)
+	if result.Error != nil {
+		log.Ctx(ctx).Error().Err(result.Error).Msg("controls cannot get")
+
+		return
+	}
+
+	consumers.mutex.Lock()
+	defer consumers.mutex.Unlock()
+
+	active := make(map[string]struct{}, len(consumers.running))
+
+	for i := range controls {
+		content, err := base64.StdEncoding.DecodeString(controls[i].Content)
+		if err != nil || !bytes.Contains(content, []byte(`"`+consumeType+`"`)) {
+			continue
+		}
+
+		nodesData, err := flow.ParseData(content)
+		if err != nil {
+			continue
+		}
+
+		sum := sha256.Sum256(content)
+		version := hex.EncodeToString(sum[:])
+
+		for nodeID, data := range nodesData {
+			if data.Name != consumeType {
+				continue
+			}
+
+			cfg, err := newConsumeConfig(data, nodeID)
+			if err != nil {
+				log.Ctx(ctx).Warn().Err(err).Str("control", controls[i].Name).Msg("consume trigger skipped")
+
+				continue
+			}
+
+			key := controls[i].Name + "/" + nodeID
+			active[key] = struct{}{}
+
+			if running, ok := consumers.running[key]; ok {
+				if running.version == version {
+					continue
+				}
+
+				running.cancel()
+			}
+
+			ctxConsumer, cancel := context.WithCancel(ctx)
+			consumers.running[key] = &runningConsumer{version: version, cancel: cancel}
+
+			logCtx := log.Ctx(ctx).With().Str("control", controls[i].Name).Str("endpoint", cfg.endpoint).Logger()
+
+			wg.Add(1)
+			go runConsumer(logCtx.WithContext(ctxConsumer), ctx, wg, appStore, controls[i].Name, content, cfg)
+		}
+	}
+
+	for key, running := range consumers.running {
+		if _, ok := active[key]; !ok {
+			running.cancel()
+			delete(consumers.running, key)
+		}
+	}
+}
+
+// runConsumer consumes until ctx done, connection errors restart the consumer after a delay.
+// Flows run with ctxRun, so stopping a consumer not cancels runs in process.
+func runConsumer(ctx, ctxRun context.Context, wg *sync.WaitGroup, appStore *registry.Registry, controlName string, content []byte, cfg consumeConfig) {
+	defer wg.Done()
+
+	for {
+		err := consume(ctx, ctxRun, wg, appStore, controlName, content, cfg)
+		if ctx.Err() != nil {
+			log.Ctx(ctx).Info().Msg("consumer stopped")
+
+			return
+		}
+
+		log.Ctx(ctx).Error().Err(err).Msgf("consumer failed, restarting in %s", ConsumeRestartDelay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(ConsumeRestartDelay):
+		}
+	}
+}
+
+func consume(ctx, ctxRun context.Context, wg *sync.WaitGroup, appStore *registry.Registry, controlName string, content []byte, cfg consumeConfig) error {
+	brokerConfig, err := fetchBroker(ctx, appStore.DB, cfg.profile)
+	if err != nil {
+		return err
+	}
+
+	// kafka cannot skip a failed offset, it blocks the partition
+	if brokerConfig.Driver == broker.DriverKafka && cfg.deadLetter == "" {
+		return fmt.Errorf("consume %s kafka consumer needs dead letter topic", cfg.endpoint)
+	}
+
+	var deadLetter broker.Publisher
+
+	if cfg.deadLetter != "" {
+		deadLetter, err = broker.New(brokerConfig)
+		if err != nil {
+			return fmt.Errorf("dead letter publisher: %w", err)
+		}
+
+		defer deadLetter.Close()
+	}
+
+	log.Ctx(ctx).Info().Str("topic", cfg.topic).Str("group", cfg.group).Msg("consumer started")
+
+	run := func(ctx context.Context, value []byte) error {
+		return consumeRun(ctxRun, wg, appStore, controlName, content, cfg.endpoint, value)
+	}
+
+	return broker.Consume(ctx, brokerConfig, broker.ConsumeConfig{ //nolint:wrapcheck // clear error
+		Topic:       cfg.topic,
+		Group:       cfg.group,
+		Concurrency: cfg.concurrency,
+	}, consumeHandler(cfg, run, deadLetter))
+}
+
+// consumeHandler tries run for attempts, after that message sent to dead letter topic.
+// Error returned when there is no dead letter or it is not accepted, so message is not acknowledged.
+// Without dead letter, message is dropped after max deliveries when broker reports the count.
+func consumeHandler(cfg consumeConfig, run func(ctx context.Context, value []byte) error, deadLetter broker.Publisher) broker.Handler {
+	return func(ctx context.Context, d broker.Delivery) error {
+		var err error
+
+		for attempt := 1; attempt <= cfg.attempts; attempt++ {
+			if attempt > 1 {
+				select {
+				case <-ctx.Done():
+					return errors.Join(err, ctx.Err())
+				case <-time.After(cfg.retryDelay):
+				}
+			}
+
+			if err = run(ctx, d.Value); err == nil {
+				return nil
+			}
+
+			log.Ctx(ctx).Warn().Err(err).Str("key", d.Key).Int("attempt", attempt).Msg("consumed message run failed")
+		}
+
+		if deadLetter == nil {
+			if d.Deliveries >= cfg.maxDeliveries {
+				log.Ctx(ctx).Error().Err(err).Str("key", d.Key).Int("deliveries", d.Deliveries).Msg("consumed message dropped after max deliveries")
+
+				return nil
+			}
+
+			// broker delivers it again
+			return fmt.Errorf("consumed message failed after %d attempts: %w", cfg.attempts, err)
+		}
+
+		headers := make(map[string]string, len(d.Headers)+3) //nolint:gomnd // error headers
+		for k, v := range d.Headers {
+			headers[k] = v
+		}
+
+		headers["X-Chore-Error"] = err.Error()
+		headers["X-Chore-Attempts"] = strconv.Itoa(cfg.attempts)
+		headers["X-Chore-Topic"] = d.Topic
+
+		if errDL := deadLetter.Publish(ctx, broker.Message{
+			Topic:   cfg.deadLetter,
+			Key:     d.Key,
+			Headers: headers,
+			Value:   d.Value,
+		}); errDL != nil {
+			return fmt.Errorf("dead letter %s: %w", cfg.deadLetter, errDL)
+		}
+
+		log.Ctx(ctx).Warn().Str("key", d.Key).Str("dead_letter", cfg.deadLetter).Msg("consumed message sent to dead letter")
+
+		return nil
+	}
+}
+
+// consumeRun starts the flow and waits it, node errors fail the run.
+func consumeRun(ctx context.Context, wg *sync.WaitGroup, appStore *registry.Registry, controlName string, content []byte, endpoint string, value []byte) error {
+	nodesReg, err := flow.StartFlow(ctx, wg, controlName, endpoint, ConsumeMethod, content, appStore, value)
+	if err != nil {
+		return err //nolint:wrapcheck // clear error
+	}
+
+	select {
+	case <-nodesReg.Done():
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck // clear error
+	}
+
+	return errors.Join(nodesReg.Errors()...)
+}
@@ -0,0 +1,128 @@
+package nodes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/worldline-go/chore/pkg/broker"
+	"github.com/worldline-go/chore/pkg/flow"
+)
+
+func TestNewConsumeConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]interface{}
+		want    consumeConfig
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			data: map[string]interface{}{"profile": "events", "topic": "orders"},
+			want: consumeConfig{
+				nodeID: "3", endpoint: "consume-3", profile: "events", topic: "orders", group: "chore",
+				concurrency: 1, attempts: 3, retryDelay: time.Second, maxDeliveries: 5,
+			},
+		},
+		{
+			name: "dead letter",
+			data: map[string]interface{}{
+				"endpoint": "orders", "profile": "events", "topic": "orders", "group": "billing",
+				"concurrency": "4", "attempts": 5.0, "retry_delay": "5s", "dead_letter": "orders.dlq", "max_deliveries": "2",
+			},
+			want: consumeConfig{
+				nodeID: "3", endpoint: "orders", profile: "events", topic: "orders", group: "billing",
+				concurrency: 4, attempts: 5, retryDelay: 5 * time.Second, deadLetter: "orders.dlq", maxDeliveries: 2,
+			},
+		},
+		{
+			name:    "without topic",
+			data:    map[string]interface{}{"profile": "events"},
+			wantErr: true,
+		},
+		{
+			name:    "concurrency",
+			data:    map[string]interface{}{"profile": "events", "topic": "orders", "concurrency": "0"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newConsumeConfig(flow.NodeData{Data: tt.data}, "3")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newConsumeConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("newConsumeConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsumeHandler(t *testing.T) {
+	cfg := consumeConfig{attempts: 3, deadLetter: "orders.dlq"}
+	delivery := broker.Delivery{Topic: "orders", Key: "42", Headers: map[string]string{"X-Source": "shop"}, Value: []byte(`{"id":42}`)}
+
+	runs := 0
+	run := func(context.Context, []byte) error {
+		runs++
+		if runs < 2 {
+			return errors.New("run failed")
+		}
+
+		return nil
+	}
+
+	deadLetter := &fakePublisher{}
+
+	if err := consumeHandler(cfg, run, deadLetter)(context.Background(), delivery); err != nil {
+		t.Fatal(err)
+	}
+
+	if runs != 2 || len(deadLetter.messages) != 0 {
+		t.Errorf("runs = %d, dead letters = %d", runs, len(deadLetter.messages))
+	}
+
+	runs = 0
+	failRun := func(context.Context, []byte) error {
+		runs++
+
+		return errors.New("run failed")
+	}
+
+	if err := consumeHandler(cfg, failRun, deadLetter)(context.Background(), delivery); err != nil {
+		t.Fatal(err)
+	}
+
+	if runs != 3 || len(deadLetter.messages) != 1 {
+		t.Fatalf("runs = %d, dead letters = %d", runs, len(deadLetter.messages))
+	}
+
+	msg := deadLetter.messages[0]
+	if msg.Topic != "orders.dlq" || msg.Key != "42" || string(msg.Value) != `{"id":42}` ||
+		msg.Headers["X-Source"] != "shop" || msg.Headers["X-Chore-Error"] != "run failed" ||
+		msg.Headers["X-Chore-Attempts"] != "3" || msg.Headers["X-Chore-Topic"] != "orders" {
+		t.Errorf("dead letter = %+v", msg)
+	}
+
+	// not acknowledged when dead letter fails
+	deadLetter.err = errors.New("broker down")
+	if err := consumeHandler(cfg, failRun, deadLetter)(context.Background(), delivery); err == nil {
+		t.Error("consumeHandler() should fail")
+	}
+
+	// not acknowledged without dead letter, message not dropped
+	runs = 0
+	if err := consumeHandler(consumeConfig{attempts: 2, maxDeliveries: 3}, failRun, nil)(context.Background(), delivery); err == nil || runs != 2 {
+		t.Errorf("consumeHandler() without dead letter error = %v, runs = %d", err, runs)
+	}
+
+	// dropped after max deliveries
+	delivery.Deliveries = 3
+	if err := consumeHandler(consumeConfig{attempts: 1, maxDeliveries: 3}, failRun, nil)(context.Background(), delivery); err != nil {
+		t.Errorf("consumeHandler() after max deliveries error = %v", err)
+	}
+}
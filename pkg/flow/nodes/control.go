@@ -5,7 +5,9 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog/log"
 
@@ -13,12 +15,20 @@ import (
 	"github.com/worldline-go/chore/pkg/flow/convert"
 	"github.com/worldline-go/chore/pkg/models"
 	"github.com/worldline-go/chore/pkg/registry"
+	"github.com/worldline-go/chore/pkg/transfer"
 
 	"gorm.io/gorm"
 )
 
 var controlType = "control"
 
+// Control modes.
+var (
+	ControlModeSync         = "sync"
+	ControlModeAsync        = "async"
+	ControlModeWaitComplete = "wait-complete"
+)
+
 type ControlRet struct {
 	respond flow.Respond
 }
@@ -31,9 +41,19 @@ func (r *ControlRet) GetBinaryData() []byte {
 	return r.respond.Data
 }
 
+// ControlDirectRet passes input value to the output in async mode.
+type ControlDirectRet struct {
+	flow.NodeRet
+}
+
+func (r *ControlDirectRet) IsDirectGo() flow.NodeRet {
+	return r.NodeRet
+}
+
 var (
 	_ flow.NodeRetRespondData = (*ControlRet)(nil)
 	_ flow.NodeRet            = (*ControlRet)(nil)
+	_ flow.NodeDirectGo       = (*ControlDirectRet)(nil)
 )
 
 // Control node has one input and one output.
@@ -41,6 +61,8 @@ type Control struct {
 	controlName  string
 	endpointName string
 	methodName   string
+	mode         string
+	timeout      string
 	inputs       []flow.Inputs
 	outputs      [][]flow.Connection
 	checked      bool
@@ -50,32 +72,111 @@ type Control struct {
 	control      models.Control
 }
 
-// Run get values from active input nodes and it will not run until last input comes.
+// Run starts the other control and waits it based on the mode.
 func (n *Control) Run(ctx context.Context, wg *sync.WaitGroup, reg *registry.Registry, value flow.NodeRet, _ string) (flow.NodeRet, error) {
 	content, err := base64.StdEncoding.DecodeString(n.control.Content)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode content; %w", err)
 	}
 
-	log.Ctx(ctx).Info().Msgf("internal call control=[%s] endpoint=[%s]", n.control.Name, n.endpointName)
+	timeoutRendered, err := renderValue(reg, n.timeout, transfer.BytesToData(value.GetBinaryData()))
+	if err != nil {
+		return nil, fmt.Errorf("timeout %w", err)
+	}
+
+	ctxFlow := ctx
+	cancel := context.CancelFunc(func() {})
 
-	nodesReg, err := flow.StartFlow(ctx, wg, n.control.Name, n.endpointName, n.methodName, content, reg, value.GetBinaryData())
-	if errors.Is(err, flow.ErrEndpointNotFound) {
-		return nil, fmt.Errorf("endpoint not found %s; %w", n.endpointName, err)
+	if timeoutRendered = strings.TrimSpace(timeoutRendered); timeoutRendered != "" {
+		timeout, err := time.ParseDuration(timeoutRendered)
+		if err != nil {
+			return nil, fmt.Errorf("timeout %q cannot parse: %w", timeoutRendered, err)
+		}
+
+		ctxFlow, cancel = context.WithTimeout(ctx, timeout)
 	}
 
+	log.Ctx(ctx).Info().Msgf("internal call control=[%s] endpoint=[%s] mode=[%s]", n.control.Name, n.endpointName, n.mode)
+
+	nodesReg, err := flow.StartFlow(ctxFlow, wg, n.control.Name, n.endpointName, n.methodName, content, reg, value.GetBinaryData())
 	if err != nil {
+		cancel()
+
+		if errors.Is(err, flow.ErrEndpointNotFound) {
+			return nil, fmt.Errorf("endpoint not found %s; %w", n.endpointName, err)
+		}
+
 		return nil, fmt.Errorf("failed to start [control:%s;endpoint:%s] content; %w", n.controlName, n.endpointName, err)
 	}
 
-	respondChan := nodesReg.GetChan()
+	// release timeout context after the other control finished
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer cancel()
+
+		select {
+		case <-nodesReg.Done():
+		case <-ctxFlow.Done():
+		}
+	}()
+
+	switch n.mode {
+	case ControlModeAsync:
+		return &ControlDirectRet{value}, nil
+	case ControlModeWaitComplete:
+		return n.waitComplete(ctx, ctxFlow, nodesReg, value)
+	}
+
+	// other control can respond before reading it
+	respondChan := nodesReg.RespondChan()
 	if respondChan == nil {
 		return nil, nil
 	}
 
-	valueChan := <-respondChan
+	select {
+	case valueChan := <-respondChan:
+		return &ControlRet{valueChan}, nil
+	case <-ctxFlow.Done():
+		return nil, n.ctxError(ctx, ctxFlow)
+	}
+}
+
+// waitComplete waits all nodes of the other control and returns collected errors.
+func (n *Control) waitComplete(ctx, ctxFlow context.Context, nodesReg *flow.NodesReg, value flow.NodeRet) (flow.NodeRet, error) {
+	select {
+	case <-nodesReg.Done():
+	case <-ctxFlow.Done():
+		return nil, n.ctxError(ctx, ctxFlow)
+	}
 
-	return &ControlRet{valueChan}, nil
+	if errs := nodesReg.Errors(); len(errs) > 0 {
+		errMsgs := make([]string, 0, len(errs))
+		for _, err := range errs {
+			errMsgs = append(errMsgs, err.Error())
+		}
+
+		return nil, fmt.Errorf("[control:%s;endpoint:%s] completed with errors: %s", n.controlName, n.endpointName, strings.Join(errMsgs, "; "))
+	}
+
+	// respond of the other control if exist
+	if respondChan := nodesReg.RespondChan(); respondChan != nil {
+		if valueChan, ok := <-respondChan; ok {
+			return &ControlRet{valueChan}, nil
+		}
+	}
+
+	return &ControlDirectRet{value}, nil
+}
+
+func (n *Control) ctxError(ctx, ctxFlow context.Context) error {
+	if ctx.Err() != nil {
+		log.Ctx(ctx).Warn().Msg("program closed, terminated node control")
+
+		return flow.ErrStopGoroutine
+	}
+
+	return fmt.Errorf("[control:%s;endpoint:%s] %w", n.controlName, n.endpointName, ctxFlow.Err())
 }
 
 func (n *Control) Special(_ interface{}) interface{} {
@@ -153,6 +254,16 @@ func NewControl(_ context.Context, _ *flow.NodesReg, data flow.NodeData, nodeID
 	controlName, _ := data.Data["control"].(string)
 	endpointName, _ := data.Data["endpoint"].(string)
 	methodName, _ := data.Data["method"].(string)
+	mode, _ := data.Data["mode"].(string)
+	timeout, _ := data.Data["timeout"].(string)
+
+	switch mode {
+	case "":
+		mode = ControlModeSync
+	case ControlModeSync, ControlModeAsync, ControlModeWaitComplete:
+	default:
+		return nil, fmt.Errorf("control mode %q not supported", mode)
+	}
 
 	tags := convert.GetList(data.Data["tags"])
 
@@ -162,6 +273,8 @@ func NewControl(_ context.Context, _ *flow.NodesReg, data flow.NodeData, nodeID
 		controlName:  controlName,
 		endpointName: endpointName,
 		methodName:   methodName,
+		mode:         mode,
+		timeout:      timeout,
 		nodeID:       nodeID,
 		tags:         tags,
 	}, nil
@@ -0,0 +1,161 @@
+package nodes
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rytsh/mugo/pkg/templatex"
+
+	"github.com/worldline-go/chore/pkg/flow"
+	"github.com/worldline-go/chore/pkg/models"
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+// controlContent is endpoint -> delay -> respond flow.
+func controlContent(duration string) string {
+	return base64.StdEncoding.EncodeToString([]byte(`{
+		"1": {"name": "endpoint", "data": {"endpoint": "test", "methods": "POST"}, "outputs": {"output_1": {"connections": [{"node": "2", "output": "input_1"}]}}},
+		"2": {"name": "delay", "data": {"duration": "` + duration + `"}, "outputs": {"output_1": {"connections": [{"node": "3", "output": "input_1"}]}}},
+		"3": {"name": "respond", "data": {"status": "200"}, "outputs": {}}
+	}`))
+}
+
+func TestControl_Run(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		timeout string
+		delay   string
+		want    string
+		respond bool
+		direct  bool
+		wantErr error
+		errMsg  string
+	}{
+		{
+			name:    "sync respond",
+			mode:    ControlModeSync,
+			delay:   "0s",
+			want:    "hello",
+			respond: true,
+		},
+		{
+			name:   "async not wait",
+			mode:   ControlModeAsync,
+			delay:  "50ms",
+			want:   "hello",
+			direct: true,
+		},
+		{
+			name:    "wait complete",
+			mode:    ControlModeWaitComplete,
+			delay:   "20ms",
+			want:    "hello",
+			respond: true,
+		},
+		{
+			name:   "wait complete with errors",
+			mode:   ControlModeWaitComplete,
+			delay:  "soon",
+			errMsg: "completed with errors",
+		},
+		{
+			name:    "sync timeout",
+			mode:    ControlModeSync,
+			timeout: "{{ .timeout }}",
+			delay:   "10s",
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name:    "wait complete timeout",
+			mode:    ControlModeWaitComplete,
+			timeout: "20ms",
+			delay:   "10s",
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := &registry.Registry{Template: templatex.New()}
+
+			node, err := NewControl(context.Background(), nil, flow.NodeData{
+				Data: map[string]interface{}{
+					"control":  "other",
+					"endpoint": "test",
+					"method":   "POST",
+					"mode":     tt.mode,
+					"timeout":  tt.timeout,
+				},
+			}, "1")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			n := node.(*Control)
+			n.control = models.Control{ControlPureContent: models.ControlPureContent{Content: controlContent(tt.delay)}}
+			n.control.Name = "other"
+
+			wg := &sync.WaitGroup{}
+			value := []byte(`{"timeout": "20ms"}`)
+
+			if tt.want != "" {
+				value = []byte(tt.want)
+			}
+
+			start := time.Now()
+			got, err := n.Run(context.Background(), wg, reg, &EndpointRet{output: value}, "input_1")
+
+			if tt.wantErr != nil || tt.errMsg != "" {
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("Control.Run() error = %v, want %v", err, tt.wantErr)
+				}
+
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("Control.Run() error = %v, want %q", err, tt.errMsg)
+				}
+
+				wg.Wait()
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.direct {
+				if _, ok := got.(flow.NodeDirectGo); !ok {
+					t.Errorf("Control.Run() = %T, want direct go", got)
+				}
+
+				if elapsed := time.Since(start); elapsed >= 50*time.Millisecond {
+					t.Errorf("Control.Run() waited other control %v", elapsed)
+				}
+			}
+
+			if _, ok := got.(*ControlRet); ok != tt.respond {
+				t.Errorf("Control.Run() = %T, respond of other control %v", got, tt.respond)
+			}
+
+			if string(got.GetBinaryData()) != tt.want {
+				t.Errorf("Control.Run() = %s, want %s", got.GetBinaryData(), tt.want)
+			}
+
+			wg.Wait()
+		})
+	}
+}
+
+func TestNewControl_mode(t *testing.T) {
+	if _, err := NewControl(context.Background(), nil, flow.NodeData{
+		Data: map[string]interface{}{"mode": "later"},
+	}, "1"); err == nil {
+		t.Error("NewControl() want error for unknown mode")
+	}
+}
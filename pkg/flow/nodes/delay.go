@@ -0,0 +1,241 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+
+	"github.com/worldline-go/chore/pkg/flow"
+	"github.com/worldline-go/chore/pkg/flow/convert"
+	"github.com/worldline-go/chore/pkg/registry"
+	"github.com/worldline-go/chore/pkg/transfer"
+)
+
+var delayType = "delay"
+
+type DelayRet struct {
+	flow.NodeRet
+}
+
+func (r *DelayRet) IsDirectGo() flow.NodeRet {
+	return r.NodeRet
+}
+
+var _ flow.NodeDirectGo = (*DelayRet)(nil)
+
+// Delay node has one input and one output.
+// Holds the value for a duration or until a timestamp.
+type Delay struct {
+	reg          *flow.NodesReg
+	duration     string
+	until        string
+	outputs      [][]flow.Connection
+	stuckContext context.Context
+	checked      bool
+	disabled     bool
+	nodeID       string
+	tags         []string
+}
+
+// Run waits the rendered duration and passes the value to the output.
+func (n *Delay) Run(ctx context.Context, _ *sync.WaitGroup, reg *registry.Registry, value flow.NodeRet, _ string) (flow.NodeRet, error) {
+	// resumed after suspend, waiting already done
+	if resumedAt, ok := flow.ResumedAt(ctx); ok && !resumedAt.After(time.Now()) {
+		return &DelayRet{value}, nil
+	}
+
+	now := time.Now()
+
+	// resumed after restart, continue with remaining time
+	deadline, resumed := flow.Deadline(ctx)
+	if !resumed {
+		wait, err := n.waitDuration(reg, value.GetBinaryData(), now)
+		if err != nil {
+			return nil, err
+		}
+
+		deadline = now.Add(wait)
+	}
+
+	wait := deadline.Sub(now)
+	if wait <= 0 {
+		return &DelayRet{value}, nil
+	}
+
+	if n.reg.IsDurable(ctx) {
+		// long waits not hold in memory
+		if wait > flow.Durable.SuspendAfter {
+			return nil, n.reg.Suspend(ctx, &deadline)
+		}
+
+		if !resumed {
+			if err := n.reg.SetDeadline(ctx, deadline); err != nil {
+				log.Ctx(ctx).Warn().Err(err).Msg("delay deadline cannot record")
+			}
+		}
+	}
+
+	log.Ctx(ctx).Debug().Msgf("delay for %s", wait)
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		// continue process
+	case <-n.stuckContext.Done():
+		log.Ctx(ctx).Warn().Msg("stuck detected, terminated node delay")
+
+		return nil, flow.ErrStopGoroutine
+	case <-ctx.Done():
+		log.Ctx(ctx).Warn().Msg("program closed, terminated node delay")
+
+		return nil, flow.ErrStopGoroutine
+	}
+
+	return &DelayRet{value}, nil
+}
+
+// waitDuration returns remaining time to wait, until has priority over duration.
+func (n *Delay) waitDuration(reg *registry.Registry, value []byte, now time.Time) (time.Duration, error) {
+	data := transfer.BytesToData(value)
+
+	if n.until != "" {
+		untilRendered, err := renderValue(reg, n.until, data)
+		if err != nil {
+			return 0, fmt.Errorf("until %w", err)
+		}
+
+		untilRendered = strings.TrimSpace(untilRendered)
+		if untilRendered != "" {
+			until, err := parseTime(untilRendered)
+			if err != nil {
+				return 0, err
+			}
+
+			return until.Sub(now), nil
+		}
+	}
+
+	durationRendered, err := renderValue(reg, n.duration, data)
+	if err != nil {
+		return 0, fmt.Errorf("duration %w", err)
+	}
+
+	durationRendered = strings.TrimSpace(durationRendered)
+	if durationRendered == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(durationRendered)
+	if err != nil {
+		return 0, fmt.Errorf("duration %q cannot parse: %w", durationRendered, err)
+	}
+
+	return duration, nil
+}
+
+// parseTime accepts RFC3339 or unix seconds.
+func parseTime(v string) (time.Time, error) {
+	if unix, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Unix(unix, 0), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("time %q should be RFC3339 or unix seconds: %w", v, err)
+	}
+
+	return t, nil
+}
+
+func (n *Delay) GetType() string {
+	return delayType
+}
+
+func (n *Delay) Fetch(_ context.Context, _ *gorm.DB) error {
+	return nil
+}
+
+func (n *Delay) IsFetched() bool {
+	return true
+}
+
+func (n *Delay) IsRespond() bool {
+	return false
+}
+
+func (n *Delay) Validate(ctx context.Context) error {
+	if n.duration == "" && n.until == "" {
+		return fmt.Errorf("duration or until should be set")
+	}
+
+	n.stuckContext = n.reg.GetStuctCancel(ctx)
+
+	return nil
+}
+
+func (n *Delay) Next(i int) []flow.Connection {
+	return n.outputs[i]
+}
+
+func (n *Delay) NextCount() int {
+	return len(n.outputs)
+}
+
+func (n *Delay) IsDisabled() bool {
+	return n.disabled
+}
+
+func (n *Delay) ActiveInput(_ string, tags map[string]struct{}) {
+	if !convert.IsTagsEnabled(n.tags, tags) {
+		n.disabled = true
+
+		return
+	}
+}
+
+func (n *Delay) Check() {
+	n.checked = true
+}
+
+func (n *Delay) IsChecked() bool {
+	return n.checked
+}
+
+func (n *Delay) NodeID() string {
+	return n.nodeID
+}
+
+func (n *Delay) Tags() []string {
+	return n.tags
+}
+
+func NewDelay(_ context.Context, reg *flow.NodesReg, data flow.NodeData, nodeID string) (flow.Noder, error) {
+	outputs := flow.PrepareOutputs(data.Outputs)
+
+	duration, _ := data.Data["duration"].(string)
+	until, _ := data.Data["until"].(string)
+
+	tags := convert.GetList(data.Data["tags"])
+
+	return &Delay{
+		reg:      reg,
+		outputs:  outputs,
+		duration: strings.TrimSpace(duration),
+		until:    strings.TrimSpace(until),
+		nodeID:   nodeID,
+		tags:     tags,
+	}, nil
+}
+
+//nolint:gochecknoinits // moduler nodes
+func init() {
+	flow.NodeTypes[delayType] = NewDelay
+}
@@ -0,0 +1,102 @@
+package nodes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rytsh/mugo/pkg/templatex"
+	"github.com/worldline-go/chore/pkg/flow"
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+func TestDelay_waitDuration(t *testing.T) {
+	now := time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC)
+	reg := &registry.Registry{Template: templatex.New()}
+
+	tests := []struct {
+		name     string
+		duration string
+		until    string
+		value    []byte
+		want     time.Duration
+		wantErr  bool
+	}{
+		{
+			name:     "fixed duration",
+			duration: "1m30s",
+			want:     90 * time.Second,
+		},
+		{
+			name:     "templated duration",
+			duration: "{{ .wait }}",
+			value:    []byte(`{"wait": "2h"}`),
+			want:     2 * time.Hour,
+		},
+		{
+			name:  "until RFC3339",
+			until: "{{ .at }}",
+			value: []byte(`{"at": "2022-01-01T10:05:00Z"}`),
+			want:  5 * time.Minute,
+		},
+		{
+			name:  "until unix seconds in past",
+			until: "1641027600",
+			want:  -time.Hour,
+		},
+		{
+			name:     "empty until falls back to duration",
+			until:    "{{ if .at }}{{ .at }}{{ end }}",
+			duration: "5s",
+			value:    []byte(`{}`),
+			want:     5 * time.Second,
+		},
+		{
+			name:     "wrong duration",
+			duration: "soon",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &Delay{duration: tt.duration, until: tt.until}
+
+			got, err := n.waitDuration(reg, tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Delay.waitDuration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Delay.waitDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDelay_Run_deadline(t *testing.T) {
+	reg := &registry.Registry{Template: templatex.New()}
+	n := &Delay{
+		reg:          flow.NewNodesReg("test", "test", "POST", reg),
+		duration:     "1h",
+		stuckContext: context.Background(),
+	}
+
+	// restarted run continues with remaining time of the recorded deadline
+	ctx := context.WithValue(context.Background(), flow.CtxDeadline, time.Now().Add(20*time.Millisecond))
+
+	start := time.Now()
+
+	ret, err := n.Run(ctx, nil, reg, &DelayRet{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := ret.(flow.NodeDirectGo); !ok {
+		t.Errorf("Delay.Run() = %T, want direct go", ret)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Delay.Run() waited %v, want remaining time", elapsed)
+	}
+}
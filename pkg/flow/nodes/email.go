@@ -3,14 +3,18 @@ package nodes
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/rs/zerolog/log"
 	"github.com/rytsh/mugo/pkg/templatex"
+	"gopkg.in/yaml.v3"
 	"gorm.io/gorm"
 
 	"github.com/worldline-go/chore/pkg/email"
@@ -23,6 +27,9 @@ import (
 
 var emailType = "email"
 
+// defaultEmailProfile is used when node has no profile.
+const defaultEmailProfile = "email-1"
+
 type inputHolderEmail struct {
 	value []byte
 	exist bool
@@ -45,7 +52,20 @@ type Email struct {
 	lockFeedBack       context.Context
 	lockFeedBackCancel context.CancelFunc
 	feedbackWait       bool
+	profile            string
 	values             map[string]string
+	body               string
+	bodyTemplate       string
+	text               string
+	headers            string
+	priority           string
+	inline             string
+	attachments        string
+	attachmentsTmpl    string
+	attachInput        bool
+	attachName         string
+	attachType         string
+	attachMaxSize      string
 	client             email.Client
 	inputs             []flow.Inputs
 	inputHolder        inputHolderEmail
@@ -154,46 +174,266 @@ func (n *Email) Run(ctx context.Context, _ *sync.WaitGroup, reg *registry.Regist
 		}
 	}
 
-	if err := n.client.Send(value.GetBinaryData(), headers, nil); err != nil {
+	msg, err := n.message(reg, requestValues, value.GetBinaryData(), headers)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := n.client.SendMessage(msg); err != nil {
 		return nil, fmt.Errorf("failed to send email: values %v, err %w", headers, err)
 	}
 
 	return &EmailRet{output: value.GetBinaryData()}, nil
 }
 
+// emailAttachment is one item of the rendered attachments list.
+type emailAttachment struct {
+	FileName    string `json:"filename"     yaml:"filename"`
+	ContentType string `json:"content_type" yaml:"content_type"`
+	Content     string `json:"content"      yaml:"content"`
+	// Encoding of the content, base64 (default) or text.
+	Encoding string `json:"encoding" yaml:"encoding"`
+}
+
+// attach returns attachments and inline files of the rendered lists and the input, total size is limited.
+func (n *Email) attach(reg *registry.Registry, data interface{}, input []byte) ([]email.Attach, []email.Attach, error) {
+	maxSize := email.AttachmentsMaxSize
+
+	rendered, err := renderValue(reg, n.attachMaxSize, data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("email attachments max size %w", err)
+	}
+
+	if rendered = strings.TrimSpace(rendered); rendered != "" {
+		if maxSize, err = strconv.ParseInt(rendered, 10, 64); err != nil {
+			return nil, nil, fmt.Errorf("email attachments max size %q cannot parse: %w", rendered, err)
+		}
+	}
+
+	var (
+		attachments []email.Attach
+		inline      []email.Attach
+		size        int64
+	)
+
+	add := func(files *[]email.Attach) func(name, contentType string, content []byte) error {
+		return func(name, contentType string, content []byte) error {
+			if size += int64(len(content)); size > maxSize {
+				return fmt.Errorf("email attachments exceed max size %d bytes", maxSize)
+			}
+
+			*files = append(*files, email.Attach{
+				FileName:    name,
+				ContentType: contentType,
+				Content:     bytes.NewReader(content),
+			})
+
+			return nil
+		}
+	}
+
+	if err := emailFiles(reg, n.attachments, data, "attachment", add(&attachments)); err != nil {
+		return nil, nil, err
+	}
+
+	if err := emailFiles(reg, n.inline, data, "inline", add(&inline)); err != nil {
+		return nil, nil, err
+	}
+
+	if n.attachInput {
+		name, err := renderValue(reg, n.attachName, data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("email attachment filename %w", err)
+		}
+
+		if name = strings.TrimSpace(name); name == "" {
+			name = "attachment"
+		}
+
+		contentType, err := renderValue(reg, n.attachType, data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("email attachment content type %w", err)
+		}
+
+		contentType = strings.TrimSpace(contentType)
+		if contentType == "" && filepath.Ext(name) == "" {
+			contentType = http.DetectContentType(input)
+		}
+
+		if err := add(&attachments)(name, contentType, input); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return attachments, inline, nil
+}
+
+// emailFiles renders list of files and decodes content of each one.
+func emailFiles(reg *registry.Registry, content string, data interface{}, kind string, add func(name, contentType string, content []byte) error) error {
+	rendered, err := renderValue(reg, content, data)
+	if err != nil {
+		return fmt.Errorf("email %s %w", kind, err)
+	}
+
+	var items []emailAttachment
+	if err := yaml.Unmarshal([]byte(rendered), &items); err != nil {
+		return fmt.Errorf("email %s cannot unmarshal: %w", kind, err)
+	}
+
+	for i, item := range items {
+		if item.FileName == "" {
+			return fmt.Errorf("email %s %d filename is empty", kind, i)
+		}
+
+		var content []byte
+
+		switch item.Encoding {
+		case "", "base64":
+			if content, err = base64.StdEncoding.DecodeString(strings.TrimSpace(item.Content)); err != nil {
+				return fmt.Errorf("email %s %s cannot decode: %w", kind, item.FileName, err)
+			}
+		case "text":
+			content = []byte(item.Content)
+		default:
+			return fmt.Errorf("email %s %s encoding %q not supported", kind, item.FileName, item.Encoding)
+		}
+
+		if err := add(item.FileName, item.ContentType, content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// emailPriority is X-Priority and Importance headers of the priority.
+var emailPriority = map[string][2]string{
+	"high":   {"1", "high"},
+	"normal": {"3", "normal"},
+	"low":    {"5", "low"},
+}
+
+// message renders bodies and headers, input is the HTML body when body is empty.
+func (n *Email) message(reg *registry.Registry, data interface{}, input []byte, headers map[string][]string) (email.Message, error) {
+	msg := email.Message{Headers: headers}
+
+	inputData := transfer.BytesToData(input)
+
+	if n.body != "" {
+		rendered, err := renderValue(reg, n.body, inputData)
+		if err != nil {
+			return msg, fmt.Errorf("email body %w", err)
+		}
+
+		msg.HTML = []byte(rendered)
+	} else if !n.attachInput {
+		// input is the file, not the body
+		msg.HTML = input
+	}
+
+	text, err := renderValue(reg, n.text, inputData)
+	if err != nil {
+		return msg, fmt.Errorf("email text %w", err)
+	}
+
+	msg.Text = []byte(text)
+
+	if p, ok := emailPriority[strings.ToLower(n.priority)]; ok {
+		headers["X-Priority"] = []string{p[0]}
+		headers["Importance"] = []string{p[1]}
+	}
+
+	rendered, err := renderValue(reg, n.headers, data)
+	if err != nil {
+		return msg, fmt.Errorf("email headers %w", err)
+	}
+
+	var custom map[string]string
+	if err := yaml.Unmarshal([]byte(rendered), &custom); err != nil {
+		return msg, fmt.Errorf("email headers cannot unmarshal: %w", err)
+	}
+
+	for k, v := range custom {
+		headers[k] = []string{v}
+	}
+
+	if data == nil {
+		// attachments could be rendered with input
+		data = inputData
+	}
+
+	if msg.Attachments, msg.Inline, err = n.attach(reg, data, input); err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
+
 func (n *Email) GetType() string {
 	return emailType
 }
 
 func (n *Email) Fetch(ctx context.Context, db *gorm.DB) error {
+	for _, tmpl := range []struct {
+		kind    string
+		name    string
+		content *string
+	}{
+		{kind: "body", name: n.bodyTemplate, content: &n.body},
+		{kind: "attachments", name: n.attachmentsTmpl, content: &n.attachments},
+	} {
+		if tmpl.name == "" {
+			continue
+		}
+
+		templateData := models.TemplatePure{}
+
+		query := db.WithContext(ctx).Model(&models.Template{}).Where("name = ?", tmpl.name)
+		if result := query.First(&templateData); result.Error != nil {
+			return fmt.Errorf("email fetch %s template %s failed: %w", tmpl.kind, tmpl.name, result.Error)
+		}
+
+		content, err := base64.StdEncoding.DecodeString(templateData.Content)
+		if err != nil {
+			return fmt.Errorf("email fetch %s template %s failed: %w", tmpl.kind, tmpl.name, err)
+		}
+
+		*tmpl.content = string(content)
+	}
+
 	getData := map[string]interface{}{}
 
-	query := db.WithContext(ctx).Model(&models.Settings{}).Select("data").Where("namespace = ?", "email").Where("name = ?", "email-1")
+	query := db.WithContext(ctx).Model(&models.Settings{}).Select("data").Where("namespace = ?", "email").Where("name = ?", n.profile)
 	result := query.First(&getData)
 
 	if result.Error != nil {
-		return fmt.Errorf("email fetch failed: %w", result.Error)
+		return fmt.Errorf("email fetch profile %s failed: %w", n.profile, result.Error)
 	}
 
-	// log.Debug().Msgf("%v", getData)
-
 	dataInner, _ := getData["data"].(string)
 	emailModel := models.Email{}
 	if err := json.Unmarshal([]byte(dataInner), &emailModel); err != nil {
-		return fmt.Errorf("request fetch failed: %w", err)
+		return fmt.Errorf("email fetch profile %s failed: %w", n.profile, err)
 	}
 
-	port, err := strconv.ParseInt(emailModel.Port, 10, 32)
+	cfg, err := email.ParseSettings(emailModel)
 	if err != nil {
-		return fmt.Errorf("request fetch failed: %w", err)
+		return fmt.Errorf("email profile %s: %w", n.profile, err)
 	}
 
-	n.client = email.NewClient(emailModel.Host, int(port), emailModel.NoAuth, emailModel.Email, emailModel.Password)
+	n.client, err = email.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf("email profile %s: %w", n.profile, err)
+	}
 
 	if n.values["From"] == "" {
 		n.values["From"] = emailModel.Email
 	}
 
+	if n.values["Reply-To"] == "" {
+		n.values["Reply-To"] = emailModel.ReplyTo
+	}
+
 	n.fetched = true
 
 	return nil
@@ -208,6 +448,10 @@ func (n *Email) IsRespond() bool {
 }
 
 func (n *Email) Validate(ctx context.Context) error {
+	if _, ok := emailPriority[strings.ToLower(n.priority)]; !ok && n.priority != "" {
+		return fmt.Errorf("email priority %q not supported, use high, normal or low", n.priority)
+	}
+
 	n.stuckContext = n.reg.GetStuctCancel(ctx)
 
 	return nil
@@ -273,15 +517,50 @@ func NewEmail(_ context.Context, reg *flow.NodesReg, data flow.NodeData, nodeID
 	values["Cc"], _ = data.Data["cc"].(string)
 	values["Bcc"], _ = data.Data["bcc"].(string)
 	values["Subject"], _ = data.Data["subject"].(string)
+	values["Reply-To"], _ = data.Data["reply_to"].(string)
+
+	profile, _ := data.Data["profile"].(string)
+	if profile = strings.TrimSpace(profile); profile == "" {
+		profile = defaultEmailProfile
+	}
+
+	body, _ := data.Data["body"].(string)
+	bodyTemplate, _ := data.Data["body_template"].(string)
+	text, _ := data.Data["text"].(string)
+	headers, _ := data.Data["headers"].(string)
+	priority, _ := data.Data["priority"].(string)
+	inline, _ := data.Data["inline"].(string)
+	attachments, _ := data.Data["attachments"].(string)
+	attachmentsTmpl, _ := data.Data["attachments_template"].(string)
+	attachName, _ := data.Data["attach_filename"].(string)
+	attachType, _ := data.Data["attach_content_type"].(string)
+
+	var attachMaxSize string
+	if v := data.Data["attachments_max_size"]; v != nil {
+		attachMaxSize = fmt.Sprint(v)
+	}
 
 	tags := convert.GetList(data.Data["tags"])
 
 	return &Email{
-		reg:    reg,
-		values: values,
-		inputs: inputs,
-		nodeID: nodeID,
-		tags:   tags,
+		reg:             reg,
+		profile:         profile,
+		values:          values,
+		body:            body,
+		bodyTemplate:    bodyTemplate,
+		text:            text,
+		headers:         headers,
+		priority:        priority,
+		inline:          inline,
+		attachments:     attachments,
+		attachmentsTmpl: attachmentsTmpl,
+		attachInput:     convert.GetBoolean(data.Data["attach_input"]),
+		attachName:      attachName,
+		attachType:      attachType,
+		attachMaxSize:   attachMaxSize,
+		inputs:          inputs,
+		nodeID:          nodeID,
+		tags:            tags,
 	}, nil
 }
 
@@ -0,0 +1,146 @@
+package nodes
+
+import (
+	"io"
+	"testing"
+
+	"github.com/rytsh/mugo/pkg/templatex"
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+func TestEmail_attach(t *testing.T) {
+	reg := &registry.Registry{Template: templatex.New()}
+
+	type file struct {
+		name        string
+		contentType string
+		content     string
+	}
+
+	tests := []struct {
+		name    string
+		node    *Email
+		data    interface{}
+		input   string
+		want    []file
+		wantErr bool
+	}{
+		{
+			name: "rendered list",
+			node: &Email{attachments: `
+- filename: report.csv
+  content_type: text/csv
+  encoding: text
+  content: "id,name\n{{ .id }},{{ .name }}"
+- filename: "{{ .id }}.txt"
+  content: aGVsbG8=
+`},
+			data: map[string]interface{}{"id": "1", "name": "chore"},
+			want: []file{
+				{name: "report.csv", contentType: "text/csv", content: "id,name\n1,chore"},
+				{name: "1.txt", content: "hello"},
+			},
+		},
+		{
+			name:  "input as file",
+			node:  &Email{attachInput: true, attachName: "{{ .id }}.pdf", attachType: "application/pdf"},
+			data:  map[string]interface{}{"id": "doc"},
+			input: "%PDF-1.4",
+			want: []file{
+				{name: "doc.pdf", contentType: "application/pdf", content: "%PDF-1.4"},
+			},
+		},
+		{
+			name:  "input detected type",
+			node:  &Email{attachInput: true},
+			input: "plain",
+			want: []file{
+				{name: "attachment", contentType: "text/plain; charset=utf-8", content: "plain"},
+			},
+		},
+		{
+			name:    "max size",
+			node:    &Email{attachInput: true, attachMaxSize: "4"},
+			input:   "too large",
+			wantErr: true,
+		},
+		{
+			name:    "not base64",
+			node:    &Email{attachments: `[{"filename": "a.bin", "content": "-"}]`},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := tt.node.attach(reg, tt.data, []byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Email.attach() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Email.attach() = %d attachments, want %d", len(got), len(tt.want))
+			}
+
+			for i, w := range tt.want {
+				content, _ := io.ReadAll(got[i].Content)
+				if got[i].FileName != w.name || got[i].ContentType != w.contentType || string(content) != w.content {
+					t.Errorf("Email.attach()[%d] = %s %s %q, want %s %s %q", i,
+						got[i].FileName, got[i].ContentType, content, w.name, w.contentType, w.content)
+				}
+			}
+		})
+	}
+}
+
+func TestEmail_message(t *testing.T) {
+	reg := &registry.Registry{Template: templatex.New()}
+
+	n := &Email{
+		body:     `<p>Hello {{ .name }}</p><img src="cid:logo.png">`,
+		text:     "Hello {{ .name }}",
+		priority: "high",
+		headers:  `List-Unsubscribe: "<mailto:{{ .list }}>"`,
+		inline:   `[{"filename": "logo.png", "content_type": "image/png", "content": "cG5n"}]`,
+	}
+
+	headers := map[string][]string{"Subject": {"hi"}}
+
+	msg, err := n.message(reg, map[string]interface{}{"list": "off@example.com"}, []byte(`{"name": "chore"}`), headers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := string(msg.HTML); got != `<p>Hello chore</p><img src="cid:logo.png">` {
+		t.Errorf("HTML = %s", got)
+	}
+
+	if got := string(msg.Text); got != "Hello chore" {
+		t.Errorf("Text = %s", got)
+	}
+
+	for k, want := range map[string]string{
+		"Subject":          "hi",
+		"X-Priority":       "1",
+		"Importance":       "high",
+		"List-Unsubscribe": "<mailto:off@example.com>",
+	} {
+		if got := msg.Headers[k]; len(got) != 1 || got[0] != want {
+			t.Errorf("header %s = %v, want %s", k, got, want)
+		}
+	}
+
+	if len(msg.Inline) != 1 || msg.Inline[0].FileName != "logo.png" || len(msg.Attachments) != 0 {
+		t.Errorf("inline = %v, attachments = %v", msg.Inline, msg.Attachments)
+	}
+
+	// input is the body without template
+	msg, err = (&Email{}).message(reg, nil, []byte("<p>raw</p>"), map[string][]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(msg.HTML) != "<p>raw</p>" || len(msg.Text) != 0 {
+		t.Errorf("HTML = %s, Text = %s", msg.HTML, msg.Text)
+	}
+}
@@ -0,0 +1,463 @@
+package nodes
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/worldline-go/chore/pkg/flow"
+	"github.com/worldline-go/chore/pkg/flow/convert"
+	"github.com/worldline-go/chore/pkg/mailbox"
+	"github.com/worldline-go/chore/pkg/models"
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+var emailTriggerType = "emailTrigger"
+
+// EmailTriggerMethod is method of the flows started with email trigger.
+const EmailTriggerMethod = "EMAIL"
+
+var (
+	// EmailTriggerCheckInterval is period to search email triggers to poll.
+	EmailTriggerCheckInterval = 15 * time.Second
+	// EmailTriggerLock is lease of a mailbox poll, other instances poll after expired.
+	EmailTriggerLock = 5 * time.Minute
+)
+
+var ErrEmailTriggerLeaseLost = errors.New("email trigger lease lost")
+
+// Email trigger actions for processed messages.
+const (
+	emailTriggerSeen = "seen"
+	emailTriggerMove = "move"
+)
+
+// emailTriggerConfig is poll settings of an emailTrigger node.
+type emailTriggerConfig struct {
+	nodeID   string
+	endpoint string
+	profile  string
+	mailbox  string
+	action   string
+	folder   string
+	interval time.Duration
+	limit    int
+}
+
+func newEmailTriggerConfig(data flow.NodeData, nodeID string) (emailTriggerConfig, error) {
+	cfg := emailTriggerConfig{
+		nodeID:   nodeID,
+		interval: time.Minute,
+		limit:    10, //nolint:gomnd // default batch
+	}
+
+	cfg.endpoint, _ = data.Data["endpoint"].(string)
+	cfg.profile, _ = data.Data["profile"].(string)
+	cfg.mailbox, _ = data.Data["mailbox"].(string)
+	cfg.action, _ = data.Data["action"].(string)
+	cfg.folder, _ = data.Data["folder"].(string)
+
+	cfg.endpoint = strings.TrimSpace(cfg.endpoint)
+	if cfg.endpoint == "" {
+		cfg.endpoint = "email-" + nodeID
+	}
+
+	if cfg.profile = strings.TrimSpace(cfg.profile); cfg.profile == "" {
+		return cfg, fmt.Errorf("email trigger %s profile is empty", cfg.endpoint)
+	}
+
+	if cfg.mailbox = strings.TrimSpace(cfg.mailbox); cfg.mailbox == "" {
+		cfg.mailbox = "INBOX"
+	}
+
+	cfg.folder = strings.TrimSpace(cfg.folder)
+
+	switch cfg.action = strings.ToLower(strings.TrimSpace(cfg.action)); cfg.action {
+	case "":
+		cfg.action = emailTriggerSeen
+	case emailTriggerSeen:
+	case emailTriggerMove:
+		if cfg.folder == "" {
+			return cfg, fmt.Errorf("email trigger %s folder is empty to move", cfg.endpoint)
+		}
+	default:
+		return cfg, fmt.Errorf("email trigger %s action %q not supported, use seen or move", cfg.endpoint, cfg.action)
+	}
+
+	if v, _ := data.Data["interval"].(string); strings.TrimSpace(v) != "" {
+		interval, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			return cfg, fmt.Errorf("email trigger %s interval %q cannot parse: %w", cfg.endpoint, v, err)
+		}
+
+		if interval < EmailTriggerCheckInterval {
+			interval = EmailTriggerCheckInterval
+		}
+
+		cfg.interval = interval
+	}
+
+	if v := data.Data["limit"]; v != nil && strings.TrimSpace(fmt.Sprint(v)) != "" {
+		limit, err := strconv.Atoi(strings.TrimSpace(fmt.Sprint(v)))
+		if err != nil || limit <= 0 {
+			return cfg, fmt.Errorf("email trigger %s limit %q is not valid", cfg.endpoint, fmt.Sprint(v))
+		}
+
+		cfg.limit = limit
+	}
+
+	return cfg, nil
+}
+
+// EmailTrigger node has one output, starts the flow for each new message of the mailbox.
+type EmailTrigger struct {
+	config   emailTriggerConfig
+	err      error
+	outputs  [][]flow.Connection
+	checked  bool
+	disabled bool
+	nodeID   string
+	tags     []string
+}
+
+var _ flow.NoderEndpoint = (*EmailTrigger)(nil)
+
+func (n *EmailTrigger) Run(_ context.Context, _ *sync.WaitGroup, _ *registry.Registry, value flow.NodeRet, _ string) (flow.NodeRet, error) {
+	return &EndpointRet{output: value.GetBinaryData()}, nil
+}
+
+func (n *EmailTrigger) GetType() string {
+	return emailTriggerType
+}
+
+func (n *EmailTrigger) Fetch(_ context.Context, _ *gorm.DB) error {
+	return nil
+}
+
+func (n *EmailTrigger) IsFetched() bool {
+	return true
+}
+
+func (n *EmailTrigger) IsRespond() bool {
+	return false
+}
+
+func (n *EmailTrigger) Validate(_ context.Context) error {
+	return n.err
+}
+
+func (n *EmailTrigger) Next(i int) []flow.Connection {
+	return n.outputs[i]
+}
+
+func (n *EmailTrigger) NextCount() int {
+	return len(n.outputs)
+}
+
+func (n *EmailTrigger) Check() {
+	n.checked = true
+}
+
+func (n *EmailTrigger) IsChecked() bool {
+	return n.checked
+}
+
+func (n *EmailTrigger) IsDisabled() bool {
+	return n.disabled
+}
+
+func (n *EmailTrigger) ActiveInput(string, map[string]struct{}) {}
+
+func (n *EmailTrigger) Endpoint() string {
+	return n.config.endpoint
+}
+
+func (n *EmailTrigger) Methods() []string {
+	return []string{EmailTriggerMethod}
+}
+
+func (n *EmailTrigger) Tags() []string {
+	return n.tags
+}
+
+func (n *EmailTrigger) NodeID() string {
+	return n.nodeID
+}
+
+func NewEmailTrigger(_ context.Context, _ *flow.NodesReg, data flow.NodeData, nodeID string) (flow.Noder, error) {
+	cfg, err := newEmailTriggerConfig(data, nodeID)
+
+	return &EmailTrigger{
+		config:  cfg,
+		err:     err,
+		outputs: flow.PrepareOutputs(data.Outputs),
+		nodeID:  nodeID,
+		tags:    convert.GetList(data.Data["tags"]),
+	}, nil
+}
+
+// StartEmailTriggers polls mailboxes of the emailTrigger nodes in all controls periodically.
+// Each trigger is claimed in database so only one instance polls a mailbox at a time.
+func StartEmailTriggers(ctx context.Context, wg *sync.WaitGroup, appStore *registry.Registry) {
+	logTrigger := log.With().Str("component", emailTriggerType).Logger()
+	ctx = logTrigger.WithContext(ctx)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(EmailTriggerCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			pollEmailTriggers(ctx, wg, appStore)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func pollEmailTriggers(ctx context.Context, wg *sync.WaitGroup, appStore *registry.Registry) {
+	var controls []models.Control
+
+	result := appStore.DB.WithContext(ctx).Model(&models.Control{}).Select("name", "content").Find(&controls)
+	if result.Error != nil {
+		log.Ctx(ctx).Error().Err(result.Error).Msg("controls cannot get")
+
+		return
+	}
+
+	for i := range controls {
+		content, err := base64.StdEncoding.DecodeString(controls[i].Content)
+		if err != nil || !bytes.Contains(content, []byte(`"`+emailTriggerType+`"`)) {
+			continue
+		}
+
+		nodesData, err := flow.ParseData(content)
+		if err != nil {
+			continue
+		}
+
+		for nodeID, data := range nodesData {
+			if data.Name != emailTriggerType {
+				continue
+			}
+
+			cfg, err := newEmailTriggerConfig(data, nodeID)
+			if err != nil {
+				log.Ctx(ctx).Warn().Err(err).Str("control", controls[i].Name).Msg("email trigger skipped")
+
+				continue
+			}
+
+			key := controls[i].Name + "/" + nodeID
+
+			if !claimEmailTrigger(ctx, appStore.DB, key, controls[i].Name, cfg) {
+				continue
+			}
+
+			wg.Add(1)
+			go func(controlName string) {
+				defer wg.Done()
+
+				logCtx := log.Ctx(ctx).With().Str("control", controlName).Str("endpoint", cfg.endpoint).Logger()
+				ctxPoll := logCtx.WithContext(ctx)
+
+				err := pollEmailTrigger(ctxPoll, wg, appStore, key, controlName, content, cfg)
+				if err != nil {
+					logCtx.Error().Err(err).Msg("email trigger poll failed")
+				}
+
+				releaseEmailTrigger(ctxPoll, appStore.DB, key, err)
+			}(controls[i].Name)
+		}
+	}
+}
+
+// claimEmailTrigger reports this instance got the lease of the trigger and its poll time came.
+func claimEmailTrigger(ctx context.Context, db *gorm.DB, key, controlName string, cfg emailTriggerConfig) bool {
+	now := time.Now()
+
+	result := db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&models.TriggerPoll{
+		Key:      key,
+		Control:  controlName,
+		Node:     cfg.nodeID,
+		NextPoll: now,
+	})
+	if result.Error != nil {
+		log.Ctx(ctx).Error().Err(result.Error).Str("trigger", key).Msg("email trigger cannot record")
+
+		return false
+	}
+
+	lockedUntil := now.Add(EmailTriggerLock)
+
+	// other instances can try at same time
+	result = db.WithContext(ctx).Model(&models.TriggerPoll{}).
+		Where("key = ?", key).
+		Where("next_poll <= ?", now).
+		Where("locked_until IS NULL OR locked_until < ?", now).
+		Updates(map[string]interface{}{
+			"owner":        flow.InstanceID,
+			"locked_until": &lockedUntil,
+			"next_poll":    now.Add(cfg.interval),
+		})
+	if result.Error != nil {
+		log.Ctx(ctx).Error().Err(result.Error).Str("trigger", key).Msg("email trigger cannot claim")
+
+		return false
+	}
+
+	return result.RowsAffected == 1
+}
+
+// renewEmailTrigger extends the lease while polling, error if the lease is lost.
+func renewEmailTrigger(ctx context.Context, db *gorm.DB, key string) error {
+	lockedUntil := time.Now().Add(EmailTriggerLock)
+
+	result := db.WithContext(ctx).Model(&models.TriggerPoll{}).
+		Where("key = ?", key).Where("owner = ?", flow.InstanceID).
+		Update("locked_until", &lockedUntil)
+	if result.Error != nil {
+		return fmt.Errorf("email trigger lease cannot extend: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrEmailTriggerLeaseLost
+	}
+
+	return nil
+}
+
+func releaseEmailTrigger(ctx context.Context, db *gorm.DB, key string, err error) {
+	var lastError string
+	if err != nil {
+		lastError = err.Error()
+	}
+
+	result := db.WithContext(ctx).Model(&models.TriggerPoll{}).
+		Where("key = ?", key).Where("owner = ?", flow.InstanceID).
+		Updates(map[string]interface{}{
+			"owner":        "",
+			"locked_until": nil,
+			"last_error":   lastError,
+		})
+	if result.Error != nil {
+		log.Ctx(ctx).Warn().Err(result.Error).Str("trigger", key).Msg("email trigger cannot release")
+	}
+}
+
+// pollEmailTrigger starts the flow for each unseen message, started messages are marked seen or moved.
+// Lease is extended before each message, poll stops if other instance took it.
+func pollEmailTrigger(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	appStore *registry.Registry,
+	key, controlName string,
+	content []byte,
+	cfg emailTriggerConfig,
+) error {
+	imapConfig, err := fetchIMAP(ctx, appStore.DB, cfg.profile)
+	if err != nil {
+		return err
+	}
+
+	client, err := mailbox.Dial(imapConfig)
+	if err != nil {
+		return err //nolint:wrapcheck // clear error
+	}
+
+	defer client.Close()
+
+	messages, err := client.Unseen(cfg.mailbox, cfg.limit)
+	if err != nil {
+		if len(messages) == 0 {
+			return err //nolint:wrapcheck // clear error
+		}
+
+		log.Ctx(ctx).Warn().Err(err).Msg("email trigger message partially parsed")
+	}
+
+	var errs []error
+
+	for _, msg := range messages {
+		if err := renewEmailTrigger(ctx, appStore.DB, key); err != nil {
+			// remaining messages stay unseen for the next poll
+			errs = append(errs, err)
+
+			break
+		}
+
+		value, err := json.Marshal(msg.Mail)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("message %d cannot marshal: %w", msg.UID, err))
+
+			continue
+		}
+
+		if _, err := flow.StartFlow(ctx, wg, controlName, cfg.endpoint, EmailTriggerMethod, content, appStore, value); err != nil {
+			// message stays unseen to try again
+			errs = append(errs, fmt.Errorf("message %d cannot start flow: %w", msg.UID, err))
+
+			continue
+		}
+
+		log.Ctx(ctx).Info().Str("message_id", msg.Mail.MessageID).Msg("new email")
+
+		switch cfg.action {
+		case emailTriggerMove:
+			err = client.Move(msg.UID, cfg.folder)
+		default:
+			err = client.MarkSeen(msg.UID)
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func fetchIMAP(ctx context.Context, db *gorm.DB, name string) (mailbox.Config, error) {
+	data := map[string]interface{}{}
+
+	query := db.WithContext(ctx).Model(&models.Settings{}).Select("data").Where("namespace = ?", "imap").Where("name = ?", name)
+	if result := query.First(&data); result.Error != nil {
+		return mailbox.Config{}, fmt.Errorf("fetch imap %s failed: %w", name, result.Error)
+	}
+
+	dataInner, _ := data["data"].(string)
+
+	imapModel := models.IMAP{}
+	if err := json.Unmarshal([]byte(dataInner), &imapModel); err != nil {
+		return mailbox.Config{}, fmt.Errorf("fetch imap %s failed: %w", name, err)
+	}
+
+	cfg, err := mailbox.ParseSettings(imapModel)
+	if err != nil {
+		return cfg, fmt.Errorf("imap profile %s: %w", name, err)
+	}
+
+	return cfg, nil
+}
+
+//nolint:gochecknoinits // moduler nodes
+func init() {
+	flow.NodeTypes[emailTriggerType] = NewEmailTrigger
+}
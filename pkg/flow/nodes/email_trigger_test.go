@@ -0,0 +1,116 @@
+package nodes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/worldline-go/chore/pkg/flow"
+)
+
+func TestNewEmailTriggerConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]interface{}
+		want    emailTriggerConfig
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			data: map[string]interface{}{"profile": "invoices"},
+			want: emailTriggerConfig{
+				nodeID: "3", endpoint: "email-3", profile: "invoices", mailbox: "INBOX",
+				action: emailTriggerSeen, interval: time.Minute, limit: 10,
+			},
+		},
+		{
+			name: "move",
+			data: map[string]interface{}{
+				"endpoint": "invoice", "profile": "invoices", "mailbox": "Suppliers",
+				"action": "move", "folder": "Processed", "interval": "5m", "limit": "20",
+			},
+			want: emailTriggerConfig{
+				nodeID: "3", endpoint: "invoice", profile: "invoices", mailbox: "Suppliers",
+				action: emailTriggerMove, folder: "Processed", interval: 5 * time.Minute, limit: 20,
+			},
+		},
+		{
+			name: "minimum interval",
+			data: map[string]interface{}{"profile": "invoices", "interval": "1s"},
+			want: emailTriggerConfig{
+				nodeID: "3", endpoint: "email-3", profile: "invoices", mailbox: "INBOX",
+				action: emailTriggerSeen, interval: EmailTriggerCheckInterval, limit: 10,
+			},
+		},
+		{
+			name:    "move without folder",
+			data:    map[string]interface{}{"profile": "invoices", "action": "move"},
+			wantErr: true,
+		},
+		{
+			name:    "without profile",
+			data:    map[string]interface{}{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newEmailTriggerConfig(flow.NodeData{Data: tt.data}, "3")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newEmailTriggerConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("newEmailTriggerConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenewEmailTrigger(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRenew := func(rows int64) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "trigger_polls" SET "locked_until"=\$1,"updated_at"=\$2 WHERE key = \$3 AND owner = \$4`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "mails/3", flow.InstanceID).
+			WillReturnResult(sqlmock.NewResult(0, rows))
+		mock.ExpectCommit()
+	}
+
+	expectRenew(1)
+
+	if err := renewEmailTrigger(context.Background(), db, "mails/3"); err != nil {
+		t.Errorf("renewEmailTrigger() error = %v", err)
+	}
+
+	// other instance took the trigger after expired lease
+	expectRenew(0)
+
+	if err := renewEmailTrigger(context.Background(), db, "mails/3"); !errors.Is(err, ErrEmailTriggerLeaseLost) {
+		t.Errorf("renewEmailTrigger() error = %v, want %v", err, ErrEmailTriggerLeaseLost)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
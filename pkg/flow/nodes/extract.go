@@ -0,0 +1,154 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"github.com/worldline-go/chore/pkg/extract"
+	"github.com/worldline-go/chore/pkg/flow"
+	"github.com/worldline-go/chore/pkg/flow/convert"
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+var extractType = "extract"
+
+// ExtractRet keeps respond data of the previous node.
+type ExtractRet struct {
+	output  []byte
+	respond *flow.Respond
+}
+
+func (r *ExtractRet) GetBinaryData() []byte {
+	return r.output
+}
+
+// ExtractRespondRet passes status and headers of previous node.
+type ExtractRespondRet struct {
+	ExtractRet
+}
+
+func (r *ExtractRespondRet) GetRespondData() flow.Respond {
+	return *r.respond
+}
+
+var _ flow.NodeRetRespondData = (*ExtractRespondRet)(nil)
+
+// Extract node has one input and one output.
+// Transforms value with jq or JSONPath expression.
+type Extract struct {
+	extractType string
+	expression  string
+	extractor   *extract.Extractor
+	outputs     [][]flow.Connection
+	checked     bool
+	disabled    bool
+	nodeID      string
+	tags        []string
+}
+
+func (n *Extract) Run(_ context.Context, _ *sync.WaitGroup, _ *registry.Registry, value flow.NodeRet, _ string) (flow.NodeRet, error) {
+	output, err := n.extractor.Bytes(value.GetBinaryData())
+	if err != nil {
+		return nil, err //nolint:wrapcheck // clear error
+	}
+
+	if v, ok := value.(flow.NodeRetRespondData); ok {
+		respond := v.GetRespondData()
+
+		return &ExtractRespondRet{ExtractRet{output: output, respond: &respond}}, nil
+	}
+
+	return &ExtractRet{output: output}, nil
+}
+
+func (n *Extract) GetType() string {
+	return extractType
+}
+
+func (n *Extract) Fetch(_ context.Context, _ *gorm.DB) error {
+	return nil
+}
+
+func (n *Extract) IsFetched() bool {
+	return true
+}
+
+func (n *Extract) IsRespond() bool {
+	return false
+}
+
+func (n *Extract) Validate(_ context.Context) error {
+	if n.expression == "" {
+		return fmt.Errorf("expression is empty")
+	}
+
+	extractor, err := extract.New(n.extractType, n.expression)
+	if err != nil {
+		return err //nolint:wrapcheck // clear error
+	}
+
+	n.extractor = extractor
+
+	return nil
+}
+
+func (n *Extract) Next(i int) []flow.Connection {
+	return n.outputs[i]
+}
+
+func (n *Extract) NextCount() int {
+	return len(n.outputs)
+}
+
+func (n *Extract) IsDisabled() bool {
+	return n.disabled
+}
+
+func (n *Extract) ActiveInput(_ string, tags map[string]struct{}) {
+	if !convert.IsTagsEnabled(n.tags, tags) {
+		n.disabled = true
+
+		return
+	}
+}
+
+func (n *Extract) Check() {
+	n.checked = true
+}
+
+func (n *Extract) IsChecked() bool {
+	return n.checked
+}
+
+func (n *Extract) NodeID() string {
+	return n.nodeID
+}
+
+func (n *Extract) Tags() []string {
+	return n.tags
+}
+
+func NewExtract(_ context.Context, _ *flow.NodesReg, data flow.NodeData, nodeID string) (flow.Noder, error) {
+	outputs := flow.PrepareOutputs(data.Outputs)
+
+	extractTypeValue, _ := data.Data["type"].(string)
+	expression, _ := data.Data["expression"].(string)
+
+	tags := convert.GetList(data.Data["tags"])
+
+	return &Extract{
+		extractType: extractTypeValue,
+		expression:  expression,
+		outputs:     outputs,
+		nodeID:      nodeID,
+		tags:        tags,
+	}, nil
+}
+
+//nolint:gochecknoinits // moduler nodes
+func init() {
+	flow.NodeTypes[extractType] = NewExtract
+}
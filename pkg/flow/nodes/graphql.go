@@ -0,0 +1,168 @@
+package nodes
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/worldline-go/chore/pkg/flow"
+	"github.com/worldline-go/chore/pkg/models"
+	"github.com/worldline-go/chore/pkg/registry"
+	"github.com/worldline-go/chore/pkg/request"
+	"github.com/worldline-go/chore/pkg/transfer"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+var graphqlType = "graphql"
+
+type graphqlBody struct {
+	Query         string      `json:"query"`
+	Variables     interface{} `json:"variables,omitempty"`
+	OperationName string      `json:"operationName,omitempty"`
+}
+
+// GraphQL node sends query document with variables, transport is same as request node.
+type GraphQL struct {
+	*Request
+	query         string
+	queryTemplate string
+	variables     string
+	operationName string
+}
+
+// body renders variables and operation name with input data.
+// Empty variables uses input object as variables.
+func (n *GraphQL) body(reg *registry.Registry, payload []byte, _ map[string]interface{}) (request.Body, error) {
+	data := transfer.BytesToData(payload)
+
+	var variables interface{}
+
+	if strings.TrimSpace(n.variables) == "" {
+		if v, ok := data.(map[string]interface{}); ok {
+			variables = v
+		}
+	} else {
+		rendered, err := renderValue(reg, n.variables, data)
+		if err != nil {
+			return request.Body{}, fmt.Errorf("graphql variables %w", err)
+		}
+
+		if err := yaml.Unmarshal([]byte(rendered), &variables); err != nil {
+			return request.Body{}, fmt.Errorf("graphql variables cannot unmarshal: %w", err)
+		}
+	}
+
+	operationName, err := renderValue(reg, n.operationName, data)
+	if err != nil {
+		return request.Body{}, fmt.Errorf("graphql operation name %w", err)
+	}
+
+	raw, err := json.Marshal(graphqlBody{
+		Query:         n.query,
+		Variables:     variables,
+		OperationName: strings.TrimSpace(operationName),
+	})
+	if err != nil {
+		return request.Body{}, fmt.Errorf("graphql body cannot marshal: %w", err)
+	}
+
+	return request.Body{
+		Mode:        request.BodyRaw,
+		Raw:         raw,
+		ContentType: "application/json",
+	}, nil
+}
+
+// graphqlErrors returns reason when response has errors.
+func graphqlErrors(ret *RequestRet) string {
+	var response struct {
+		Errors []json.RawMessage `json:"errors"`
+	}
+
+	if err := json.Unmarshal(ret.respond.Data, &response); err != nil || len(response.Errors) == 0 {
+		return ""
+	}
+
+	return "graphql_errors"
+}
+
+func (n *GraphQL) GetType() string {
+	return graphqlType
+}
+
+func (n *GraphQL) Fetch(ctx context.Context, db *gorm.DB) error {
+	if n.queryTemplate != "" {
+		getData := models.TemplatePure{}
+
+		query := db.WithContext(ctx).Model(&models.Template{}).Where("name = ?", n.queryTemplate)
+		result := query.First(&getData)
+
+		if result.Error != nil {
+			return fmt.Errorf("graphql fetch template %s failed: %w", n.queryTemplate, result.Error)
+		}
+
+		content, err := base64.StdEncoding.DecodeString(getData.Content)
+		if err != nil {
+			return fmt.Errorf("graphql fetch template %s failed: %w", n.queryTemplate, err)
+		}
+
+		n.query = string(content)
+	}
+
+	return n.Request.Fetch(ctx, db)
+}
+
+func (n *GraphQL) Validate(ctx context.Context) error {
+	if strings.TrimSpace(n.query) == "" && n.queryTemplate == "" {
+		return fmt.Errorf("graphql query is empty")
+	}
+
+	n.Request.method = http.MethodPost
+
+	return n.Request.Validate(ctx)
+}
+
+func NewGraphQL(ctx context.Context, reg *flow.NodesReg, data flow.NodeData, nodeID string) (flow.Noder, error) {
+	noder, err := NewRequest(ctx, reg, data, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	req, _ := noder.(*Request)
+
+	query, _ := data.Data["query"].(string)
+	queryTemplate, _ := data.Data["template"].(string)
+	variables, _ := data.Data["variables"].(string)
+	operationName, _ := data.Data["operation_name"].(string)
+
+	n := &GraphQL{
+		Request:       req,
+		query:         query,
+		queryTemplate: queryTemplate,
+		variables:     variables,
+		operationName: operationName,
+	}
+
+	l := log.Ctx(ctx).With().Str("component", graphqlType).Logger()
+
+	// body always comes from query, pagination not used
+	req.log = &l
+	req.bodyFn = n.body
+	req.responseFn = graphqlErrors
+	req.payloadNil = false
+	req.bodyMode = request.BodyRaw
+	req.pagination = pagination{}
+
+	return n, nil
+}
+
+//nolint:gochecknoinits // moduler nodes
+func init() {
+	flow.NodeTypes[graphqlType] = NewGraphQL
+}
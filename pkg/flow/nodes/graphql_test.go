@@ -0,0 +1,104 @@
+package nodes
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rytsh/mugo/pkg/templatex"
+	"github.com/worldline-go/chore/pkg/registry"
+	"github.com/worldline-go/chore/pkg/request"
+)
+
+func TestGraphQL_body(t *testing.T) {
+	reg := &registry.Registry{Template: templatex.New()}
+
+	tests := []struct {
+		name    string
+		node    GraphQL
+		payload string
+		want    string
+	}{
+		{
+			name:    "input as variables",
+			node:    GraphQL{query: `query { user(id: $id) { name } }`},
+			payload: `{"id": "1"}`,
+			want:    `{"query":"query { user(id: $id) { name } }","variables":{"id":"1"}}`,
+		},
+		{
+			name: "rendered variables",
+			node: GraphQL{
+				query:         `query Get($ids: [ID!]) { users(ids: $ids) { name } }`,
+				variables:     `ids: ["{{ .id }}"]`,
+				operationName: "Get",
+			},
+			payload: `{"id": "a"}`,
+			want:    `{"query":"query Get($ids: [ID!]) { users(ids: $ids) { name } }","variables":{"ids":["a"]},"operationName":"Get"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := tt.node.body(reg, []byte(tt.payload), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := string(body.Raw); got != tt.want {
+				t.Errorf("GraphQL.body() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraphQL_errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %s", r.Header.Get("Content-Type"))
+		}
+
+		var body graphqlBody
+
+		payload, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(payload, &body); err != nil {
+			t.Fatal(err)
+		}
+
+		if body.OperationName == "Fail" {
+			_, _ = w.Write([]byte(`{"data":null,"errors":[{"message":"not found"}]}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data":{"ok":true},"errors":[]}`))
+	}))
+	defer srv.Close()
+
+	client, err := request.NewClient(request.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for operationName, want := range map[string]string{"Ok": "", "Fail": "graphql_errors"} {
+		n := &GraphQL{query: "query { ok }", operationName: operationName}
+
+		body, err := n.body(&registry.Registry{Template: templatex.New()}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req := &Request{client: client}
+
+		response, failRet := req.call(context.Background(), srv.URL, http.MethodPost, nil, body, request.CallOptions{})
+		if failRet != nil {
+			t.Fatalf("call failed: %s", failRet.respond.Data)
+		}
+
+		if got := graphqlErrors(responseRet(response, response.Body)); got != want {
+			t.Errorf("%s graphqlErrors() = %q, want %q", operationName, got, want)
+		}
+	}
+}
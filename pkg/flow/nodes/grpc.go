@@ -0,0 +1,299 @@
+package nodes
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/worldline-go/chore/pkg/flow"
+	"github.com/worldline-go/chore/pkg/flow/convert"
+	"github.com/worldline-go/chore/pkg/grpccall"
+	"github.com/worldline-go/chore/pkg/models"
+	"github.com/worldline-go/chore/pkg/registry"
+	"github.com/worldline-go/chore/pkg/request"
+	"github.com/worldline-go/chore/pkg/transfer"
+
+	"google.golang.org/grpc/codes"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+var grpcType = "grpc"
+
+// grpcHTTPStatus maps grpc codes to http status for respond data.
+var grpcHTTPStatus = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           499, //nolint:gomnd // client closed request
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+}
+
+// GRPC node has one input and three outputs, failure, success and always.
+// Calls unary method with JSON payload.
+type GRPC struct {
+	reg         *flow.NodesReg
+	target      string
+	method      string
+	protoName   string
+	metadataRaw string
+	tlsName     string
+	tls         *request.TLSConfig
+	plaintext   bool
+	skipVerify  bool
+	timeout     string
+	client      *grpccall.Client
+	outputs     [][]flow.Connection
+	fetched     bool
+	checked     bool
+	disabled    bool
+	nodeID      string
+	tags        []string
+}
+
+func (n *GRPC) Run(ctx context.Context, _ *sync.WaitGroup, reg *registry.Registry, value flow.NodeRet, _ string) (flow.NodeRet, error) {
+	payload := value.GetBinaryData()
+	data := transfer.BytesToData(payload)
+
+	method, err := renderValue(reg, n.method, data)
+	if err != nil {
+		return nil, fmt.Errorf("grpc method %w", err)
+	}
+
+	metadataRendered, err := renderValue(reg, n.metadataRaw, data)
+	if err != nil {
+		return nil, fmt.Errorf("grpc metadata %w", err)
+	}
+
+	var md map[string]string
+	if err := yaml.Unmarshal([]byte(metadataRendered), &md); err != nil {
+		return nil, fmt.Errorf("grpc metadata cannot unmarshal: %w", err)
+	}
+
+	if v, _ := ctx.Value("request_id").(string); v != "" {
+		if md == nil {
+			md = make(map[string]string, 1)
+		}
+
+		md["x-request-id"] = v
+	}
+
+	timeout, err := renderValue(reg, n.timeout, data)
+	if err != nil {
+		return nil, fmt.Errorf("grpc timeout %w", err)
+	}
+
+	if timeout = strings.TrimSpace(timeout); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			return nil, fmt.Errorf("grpc timeout %q cannot parse: %w", timeout, err)
+		}
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+
+		defer cancel()
+	}
+
+	response, err := n.client.Call(ctx, strings.TrimSpace(method), md, payload)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // clear error
+	}
+
+	return grpcRet(response), nil
+}
+
+// grpcRet selects output with status code, metadata and status added to headers.
+func grpcRet(response *grpccall.Response) *RequestRet {
+	header := make(map[string]interface{}, len(response.Header)+2) //nolint:gomnd // status and message
+	for k, v := range response.Header {
+		if len(v) > 0 {
+			header[k] = v[0]
+		}
+	}
+
+	code := codes.Code(response.Code)
+
+	header["Grpc-Status"] = strconv.Itoa(int(response.Code))
+	if response.Message != "" {
+		header["Grpc-Message"] = response.Message
+	}
+
+	status, ok := grpcHTTPStatus[code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	selection := []int{0, 2}
+	if code == codes.OK {
+		selection = []int{1, 2}
+	}
+
+	return &RequestRet{
+		respond: flow.Respond{
+			Header: header,
+			Data:   response.Body,
+			Status: status,
+		},
+		selection: selection,
+	}
+}
+
+func (n *GRPC) GetType() string {
+	return grpcType
+}
+
+func (n *GRPC) Fetch(ctx context.Context, db *gorm.DB) error {
+	var descriptors []byte
+
+	if n.protoName != "" {
+		getData := models.ProtoPure{}
+
+		query := db.WithContext(ctx).Model(&models.Proto{}).Where("name = ?", n.protoName)
+		result := query.First(&getData)
+
+		if result.Error != nil {
+			return fmt.Errorf("grpc fetch proto %s failed: %w", n.protoName, result.Error)
+		}
+
+		var err error
+
+		descriptors, err = base64.StdEncoding.DecodeString(getData.Content)
+		if err != nil {
+			return fmt.Errorf("grpc fetch proto %s failed: %w", n.protoName, err)
+		}
+	}
+
+	if n.tlsName != "" {
+		tlsConfig, err := fetchTLS(ctx, db, n.tlsName)
+		if err != nil {
+			return fmt.Errorf("grpc %w", err)
+		}
+
+		n.tls = tlsConfig
+	}
+
+	client, err := grpccall.NewClient(grpccall.Config{
+		Target:      n.target,
+		Plaintext:   n.plaintext,
+		SkipVerify:  n.skipVerify,
+		TLS:         n.tls,
+		Descriptors: descriptors,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create grpc client: %w", err)
+	}
+
+	n.client = client
+	n.reg.AddCleanup(func() { _ = client.Close() })
+
+	n.fetched = true
+
+	return nil
+}
+
+func (n *GRPC) IsFetched() bool {
+	return n.fetched
+}
+
+func (n *GRPC) IsRespond() bool {
+	return false
+}
+
+func (n *GRPC) Validate(_ context.Context) error {
+	if n.target == "" {
+		return fmt.Errorf("grpc target is empty")
+	}
+
+	if n.method == "" {
+		return fmt.Errorf("grpc method is empty")
+	}
+
+	return nil
+}
+
+func (n *GRPC) Next(i int) []flow.Connection {
+	return n.outputs[i]
+}
+
+func (n *GRPC) NextCount() int {
+	return len(n.outputs)
+}
+
+func (n *GRPC) IsDisabled() bool {
+	return n.disabled
+}
+
+func (n *GRPC) ActiveInput(_ string, tags map[string]struct{}) {
+	if !convert.IsTagsEnabled(n.tags, tags) {
+		n.disabled = true
+
+		return
+	}
+}
+
+func (n *GRPC) Check() {
+	n.checked = true
+}
+
+func (n *GRPC) IsChecked() bool {
+	return n.checked
+}
+
+func (n *GRPC) NodeID() string {
+	return n.nodeID
+}
+
+func (n *GRPC) Tags() []string {
+	return n.tags
+}
+
+func NewGRPC(_ context.Context, reg *flow.NodesReg, data flow.NodeData, nodeID string) (flow.Noder, error) {
+	outputs := flow.PrepareOutputs(data.Outputs)
+
+	target, _ := data.Data["target"].(string)
+	method, _ := data.Data["method"].(string)
+	protoName, _ := data.Data["proto"].(string)
+	metadataRaw, _ := data.Data["metadata"].(string)
+	tlsName, _ := data.Data["tls"].(string)
+	timeout, _ := data.Data["timeout"].(string)
+
+	tags := convert.GetList(data.Data["tags"])
+
+	return &GRPC{
+		reg:         reg,
+		target:      strings.TrimSpace(target),
+		method:      method,
+		protoName:   protoName,
+		metadataRaw: metadataRaw,
+		tlsName:     tlsName,
+		plaintext:   convert.GetBoolean(data.Data["plaintext"]),
+		skipVerify:  convert.GetBoolean(data.Data["skip_verify"]),
+		timeout:     timeout,
+		outputs:     outputs,
+		nodeID:      nodeID,
+		tags:        tags,
+	}, nil
+}
+
+//nolint:gochecknoinits // moduler nodes
+func init() {
+	flow.NodeTypes[grpcType] = NewGRPC
+}
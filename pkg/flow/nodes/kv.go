@@ -0,0 +1,302 @@
+package nodes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/worldline-go/chore/pkg/flow"
+	"github.com/worldline-go/chore/pkg/flow/convert"
+	"github.com/worldline-go/chore/pkg/kv"
+	"github.com/worldline-go/chore/pkg/registry"
+	"github.com/worldline-go/chore/pkg/transfer"
+)
+
+var (
+	kvGetType            = "kvGet"
+	kvSetType            = "kvSet"
+	kvDeleteType         = "kvDelete"
+	kvIncrementType      = "kvIncrement"
+	kvCompareAndSwapType = "kvCompareAndSwap"
+)
+
+type KVRet struct {
+	output    []byte
+	selection []int
+}
+
+func (r *KVRet) GetBinaryData() []byte {
+	return r.output
+}
+
+func (r *KVRet) GetSelection() []int {
+	return r.selection
+}
+
+var _ flow.NodeRetSelection = (*KVRet)(nil)
+
+// KV node has one input, works on a key of the key-value store.
+// kvGet has not found and found outputs, kvCompareAndSwap has not swapped and swapped outputs,
+// others have one output.
+type KV struct {
+	typeName  string
+	namespace string
+	key       string
+	value     string
+	expected  string
+	delta     string
+	ttl       string
+	outputs   [][]flow.Connection
+	checked   bool
+	disabled  bool
+	nodeID    string
+	tags      []string
+}
+
+type kvArgs struct {
+	namespace string
+	key       string
+	value     string
+	expected  *string
+	delta     int64
+	ttl       time.Duration
+}
+
+// Run renders the fields with input data, store errors stop the flow.
+func (n *KV) Run(ctx context.Context, _ *sync.WaitGroup, reg *registry.Registry, value flow.NodeRet, _ string) (flow.NodeRet, error) {
+	if reg.DB == nil {
+		return nil, fmt.Errorf("%s database not available", n.typeName)
+	}
+
+	payload := value.GetBinaryData()
+
+	args, err := n.args(reg, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.typeName {
+	case kvGetType:
+		record, err := kv.Get(ctx, reg.DB, args.namespace, args.key)
+		if errors.Is(err, kv.ErrNotFound) {
+			return &KVRet{output: payload, selection: []int{0}}, nil
+		}
+
+		if err != nil {
+			return nil, err //nolint:wrapcheck // already wrapped
+		}
+
+		return &KVRet{output: []byte(record.Value), selection: []int{1}}, nil
+	case kvSetType:
+		if err := kv.Set(ctx, reg.DB, args.namespace, args.key, args.value, args.ttl); err != nil {
+			return nil, err //nolint:wrapcheck // already wrapped
+		}
+	case kvDeleteType:
+		if _, err := kv.Delete(ctx, reg.DB, args.namespace, args.key); err != nil {
+			return nil, err //nolint:wrapcheck // already wrapped
+		}
+	case kvIncrementType:
+		v, err := kv.Increment(ctx, reg.DB, args.namespace, args.key, args.delta, args.ttl)
+		if err != nil {
+			return nil, err //nolint:wrapcheck // already wrapped
+		}
+
+		return &KVRet{output: []byte(strconv.FormatInt(v, 10)), selection: []int{0}}, nil
+	case kvCompareAndSwapType:
+		swapped, err := kv.CompareAndSwap(ctx, reg.DB, args.namespace, args.key, args.expected, args.value, args.ttl)
+		if err != nil {
+			return nil, err //nolint:wrapcheck // already wrapped
+		}
+
+		if !swapped {
+			return &KVRet{output: payload, selection: []int{0}}, nil
+		}
+
+		return &KVRet{output: payload, selection: []int{1}}, nil
+	}
+
+	return &KVRet{output: payload, selection: []int{0}}, nil
+}
+
+// args renders the fields, empty value uses the input and empty expected means key should not exist.
+func (n *KV) args(reg *registry.Registry, payload []byte) (kvArgs, error) {
+	data := transfer.BytesToData(payload)
+
+	render := func(name, tmpl string) (string, error) {
+		v, err := renderValue(reg, tmpl, data)
+		if err != nil {
+			return "", fmt.Errorf("%s %s %w", n.typeName, name, err)
+		}
+
+		return v, nil
+	}
+
+	var (
+		args kvArgs
+		err  error
+	)
+
+	if args.namespace, err = render("namespace", n.namespace); err != nil {
+		return args, err
+	}
+
+	if args.key, err = render("key", n.key); err != nil {
+		return args, err
+	}
+
+	args.namespace = strings.TrimSpace(args.namespace)
+	args.key = strings.TrimSpace(args.key)
+
+	if args.namespace == "" || args.key == "" {
+		return args, fmt.Errorf("%s namespace and key should not be empty", n.typeName)
+	}
+
+	if args.value, err = render("value", n.value); err != nil {
+		return args, err
+	}
+
+	if args.value == "" {
+		args.value = string(payload)
+	}
+
+	expected, err := render("expected", n.expected)
+	if err != nil {
+		return args, err
+	}
+
+	if expected != "" {
+		args.expected = &expected
+	}
+
+	delta, err := render("delta", n.delta)
+	if err != nil {
+		return args, err
+	}
+
+	args.delta = 1
+	if delta = strings.TrimSpace(delta); delta != "" {
+		if args.delta, err = strconv.ParseInt(delta, 10, 64); err != nil {
+			return args, fmt.Errorf("%s delta %q cannot parse: %w", n.typeName, delta, err)
+		}
+	}
+
+	ttl, err := render("ttl", n.ttl)
+	if err != nil {
+		return args, err
+	}
+
+	if ttl = strings.TrimSpace(ttl); ttl != "" {
+		if args.ttl, err = time.ParseDuration(ttl); err != nil {
+			return args, fmt.Errorf("%s ttl %q cannot parse: %w", n.typeName, ttl, err)
+		}
+	}
+
+	return args, nil
+}
+
+func (n *KV) GetType() string {
+	return n.typeName
+}
+
+func (n *KV) Fetch(_ context.Context, _ *gorm.DB) error {
+	return nil
+}
+
+func (n *KV) IsFetched() bool {
+	return true
+}
+
+func (n *KV) IsRespond() bool {
+	return false
+}
+
+func (n *KV) Validate(_ context.Context) error {
+	if n.namespace == "" {
+		return fmt.Errorf("%s namespace is empty", n.typeName)
+	}
+
+	if n.key == "" {
+		return fmt.Errorf("%s key is empty", n.typeName)
+	}
+
+	return nil
+}
+
+func (n *KV) Next(i int) []flow.Connection {
+	return n.outputs[i]
+}
+
+func (n *KV) NextCount() int {
+	return len(n.outputs)
+}
+
+func (n *KV) IsDisabled() bool {
+	return n.disabled
+}
+
+func (n *KV) ActiveInput(_ string, tags map[string]struct{}) {
+	if !convert.IsTagsEnabled(n.tags, tags) {
+		n.disabled = true
+
+		return
+	}
+}
+
+func (n *KV) Check() {
+	n.checked = true
+}
+
+func (n *KV) IsChecked() bool {
+	return n.checked
+}
+
+func (n *KV) NodeID() string {
+	return n.nodeID
+}
+
+func (n *KV) Tags() []string {
+	return n.tags
+}
+
+func newKV(typeName string) func(context.Context, *flow.NodesReg, flow.NodeData, string) (flow.Noder, error) {
+	return func(_ context.Context, _ *flow.NodesReg, data flow.NodeData, nodeID string) (flow.Noder, error) {
+		outputs := flow.PrepareOutputs(data.Outputs)
+
+		namespace, _ := data.Data["namespace"].(string)
+		key, _ := data.Data["key"].(string)
+		value, _ := data.Data["value"].(string)
+		expected, _ := data.Data["expected"].(string)
+		delta, _ := data.Data["delta"].(string)
+		ttl, _ := data.Data["ttl"].(string)
+
+		tags := convert.GetList(data.Data["tags"])
+
+		return &KV{
+			typeName:  typeName,
+			namespace: strings.TrimSpace(namespace),
+			key:       strings.TrimSpace(key),
+			value:     value,
+			expected:  expected,
+			delta:     delta,
+			ttl:       ttl,
+			outputs:   outputs,
+			nodeID:    nodeID,
+			tags:      tags,
+		}, nil
+	}
+}
+
+//nolint:gochecknoinits // moduler nodes
+func init() {
+	flow.NodeTypes[kvGetType] = newKV(kvGetType)
+	flow.NodeTypes[kvSetType] = newKV(kvSetType)
+	flow.NodeTypes[kvDeleteType] = newKV(kvDeleteType)
+	flow.NodeTypes[kvIncrementType] = newKV(kvIncrementType)
+	flow.NodeTypes[kvCompareAndSwapType] = newKV(kvCompareAndSwapType)
+}
@@ -0,0 +1,91 @@
+package nodes
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/rytsh/mugo/pkg/templatex"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+func TestKV_Run(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reg := &registry.Registry{Template: templatex.New(), DB: db}
+	input := &EndpointRet{output: []byte(`{"id":"42"}`)}
+
+	// first message is swapped, duplicate is not
+	n := &KV{
+		typeName:  kvCompareAndSwapType,
+		namespace: "seen",
+		key:       "order-{{ .id }}",
+		value:     "1",
+		ttl:       "1h",
+	}
+
+	for _, created := range []int64{1, 0} {
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO "kvs"`).
+			WithArgs("seen", "order-42", "1", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, created))
+		mock.ExpectCommit()
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "kvs"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	for _, want := range [][]int{{1}, {0}} {
+		ret, err := n.Run(context.Background(), nil, reg, input, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := ret.(*KVRet); !reflect.DeepEqual(got.selection, want) || string(got.output) != `{"id":"42"}` {
+			t.Errorf("KV.Run() selection = %v, output = %s", got.selection, got.output)
+		}
+	}
+
+	// missing key goes to first output with input
+	n = &KV{typeName: kvGetType, namespace: "seen", key: "order-{{ .id }}"}
+
+	mock.ExpectQuery(`SELECT \* FROM "kvs"`).
+		WithArgs("seen", "order-42", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"namespace", "key", "value"}))
+
+	ret, err := n.Run(context.Background(), nil, reg, input, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ret.(*KVRet); !reflect.DeepEqual(got.selection, []int{0}) || string(got.output) != `{"id":"42"}` {
+		t.Errorf("KV.Run() selection = %v, output = %s", got.selection, got.output)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+
+	n = &KV{typeName: kvIncrementType, namespace: "counters", key: "orders", delta: "many"}
+	if _, err := n.Run(context.Background(), nil, reg, input, ""); err == nil {
+		t.Error("KV.Run() expected delta parse error")
+	}
+}
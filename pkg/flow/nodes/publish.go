@@ -0,0 +1,267 @@
+package nodes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+
+	"github.com/worldline-go/chore/pkg/broker"
+	"github.com/worldline-go/chore/pkg/flow"
+	"github.com/worldline-go/chore/pkg/flow/convert"
+	"github.com/worldline-go/chore/pkg/models"
+	"github.com/worldline-go/chore/pkg/registry"
+	"github.com/worldline-go/chore/pkg/transfer"
+)
+
+var publishType = "publish"
+
+type PublishRet struct {
+	output    []byte
+	selection []int
+}
+
+func (r *PublishRet) GetBinaryData() []byte {
+	return r.output
+}
+
+func (r *PublishRet) GetSelection() []int {
+	return r.selection
+}
+
+var _ flow.NodeRetSelection = (*PublishRet)(nil)
+
+// Publish node has one input and two outputs, failure and success.
+// Sends input value to kafka, nats or amqp and waits the acknowledgement.
+type Publish struct {
+	reg       *flow.NodesReg
+	profile   string
+	topic     string
+	key       string
+	headers   string
+	timeout   string
+	publisher broker.Publisher
+	outputs   [][]flow.Connection
+	fetched   bool
+	checked   bool
+	disabled  bool
+	nodeID    string
+	tags      []string
+}
+
+// Run publishes the input, rendering errors stop the flow and delivery errors go to failure output.
+func (n *Publish) Run(ctx context.Context, _ *sync.WaitGroup, reg *registry.Registry, value flow.NodeRet, _ string) (flow.NodeRet, error) {
+	payload := value.GetBinaryData()
+
+	msg, timeout, err := n.message(reg, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+
+		defer cancel()
+	}
+
+	if err := n.publisher.Publish(ctx, msg); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("topic", msg.Topic).Msg("publish failed")
+
+		output, _ := json.Marshal(map[string]string{
+			"error":   err.Error(),
+			"profile": n.profile,
+			"topic":   msg.Topic,
+		})
+
+		return &PublishRet{output: output, selection: []int{0}}, nil
+	}
+
+	return &PublishRet{output: payload, selection: []int{1}}, nil
+}
+
+// message renders topic, key and headers with input data.
+func (n *Publish) message(reg *registry.Registry, payload []byte) (broker.Message, time.Duration, error) {
+	data := transfer.BytesToData(payload)
+
+	topic, err := renderValue(reg, n.topic, data)
+	if err != nil {
+		return broker.Message{}, 0, fmt.Errorf("publish topic %w", err)
+	}
+
+	topic = strings.TrimSpace(topic)
+	if topic == "" {
+		return broker.Message{}, 0, fmt.Errorf("publish topic rendered empty")
+	}
+
+	key, err := renderValue(reg, n.key, data)
+	if err != nil {
+		return broker.Message{}, 0, fmt.Errorf("publish key %w", err)
+	}
+
+	headersRendered, err := renderValue(reg, n.headers, data)
+	if err != nil {
+		return broker.Message{}, 0, fmt.Errorf("publish headers %w", err)
+	}
+
+	var headers map[string]string
+	if err := yaml.Unmarshal([]byte(headersRendered), &headers); err != nil {
+		return broker.Message{}, 0, fmt.Errorf("publish headers cannot unmarshal: %w", err)
+	}
+
+	timeoutRendered, err := renderValue(reg, n.timeout, data)
+	if err != nil {
+		return broker.Message{}, 0, fmt.Errorf("publish timeout %w", err)
+	}
+
+	var timeout time.Duration
+	if timeoutRendered = strings.TrimSpace(timeoutRendered); timeoutRendered != "" {
+		if timeout, err = time.ParseDuration(timeoutRendered); err != nil {
+			return broker.Message{}, 0, fmt.Errorf("publish timeout %q cannot parse: %w", timeoutRendered, err)
+		}
+	}
+
+	return broker.Message{
+		Topic:   topic,
+		Key:     strings.TrimSpace(key),
+		Headers: headers,
+		Value:   payload,
+	}, timeout, nil
+}
+
+func (n *Publish) GetType() string {
+	return publishType
+}
+
+func (n *Publish) Fetch(ctx context.Context, db *gorm.DB) error {
+	cfg, err := fetchBroker(ctx, db, n.profile)
+	if err != nil {
+		return err
+	}
+
+	publisher, release, err := broker.Acquire(n.profile, cfg)
+	if err != nil {
+		return fmt.Errorf("publish profile %s: %w", n.profile, err)
+	}
+
+	n.publisher = publisher
+	n.reg.AddCleanup(release)
+
+	n.fetched = true
+
+	return nil
+}
+
+func fetchBroker(ctx context.Context, db *gorm.DB, name string) (broker.Config, error) {
+	data := map[string]interface{}{}
+
+	query := db.WithContext(ctx).Model(&models.Settings{}).Select("data").Where("namespace = ?", "broker").Where("name = ?", name)
+	if result := query.First(&data); result.Error != nil {
+		return broker.Config{}, fmt.Errorf("fetch broker %s failed: %w", name, result.Error)
+	}
+
+	dataInner, _ := data["data"].(string)
+
+	brokerModel := models.Broker{}
+	if err := json.Unmarshal([]byte(dataInner), &brokerModel); err != nil {
+		return broker.Config{}, fmt.Errorf("fetch broker %s failed: %w", name, err)
+	}
+
+	cfg, err := broker.ParseSettings(brokerModel)
+	if err != nil {
+		return cfg, fmt.Errorf("broker profile %s: %w", name, err)
+	}
+
+	return cfg, nil
+}
+
+func (n *Publish) IsFetched() bool {
+	return n.fetched
+}
+
+func (n *Publish) IsRespond() bool {
+	return false
+}
+
+func (n *Publish) Validate(_ context.Context) error {
+	if n.profile == "" {
+		return fmt.Errorf("publish profile is empty")
+	}
+
+	if n.topic == "" {
+		return fmt.Errorf("publish topic is empty")
+	}
+
+	return nil
+}
+
+func (n *Publish) Next(i int) []flow.Connection {
+	return n.outputs[i]
+}
+
+func (n *Publish) NextCount() int {
+	return len(n.outputs)
+}
+
+func (n *Publish) IsDisabled() bool {
+	return n.disabled
+}
+
+func (n *Publish) ActiveInput(_ string, tags map[string]struct{}) {
+	if !convert.IsTagsEnabled(n.tags, tags) {
+		n.disabled = true
+
+		return
+	}
+}
+
+func (n *Publish) Check() {
+	n.checked = true
+}
+
+func (n *Publish) IsChecked() bool {
+	return n.checked
+}
+
+func (n *Publish) NodeID() string {
+	return n.nodeID
+}
+
+func (n *Publish) Tags() []string {
+	return n.tags
+}
+
+func NewPublish(_ context.Context, reg *flow.NodesReg, data flow.NodeData, nodeID string) (flow.Noder, error) {
+	outputs := flow.PrepareOutputs(data.Outputs)
+
+	profile, _ := data.Data["profile"].(string)
+	topic, _ := data.Data["topic"].(string)
+	key, _ := data.Data["key"].(string)
+	headers, _ := data.Data["headers"].(string)
+	timeout, _ := data.Data["timeout"].(string)
+
+	tags := convert.GetList(data.Data["tags"])
+
+	return &Publish{
+		reg:     reg,
+		profile: strings.TrimSpace(profile),
+		topic:   topic,
+		key:     key,
+		headers: headers,
+		timeout: timeout,
+		outputs: outputs,
+		nodeID:  nodeID,
+		tags:    tags,
+	}, nil
+}
+
+//nolint:gochecknoinits // moduler nodes
+func init() {
+	flow.NodeTypes[publishType] = NewPublish
+}
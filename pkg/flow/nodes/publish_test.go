@@ -0,0 +1,79 @@
+package nodes
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/rytsh/mugo/pkg/templatex"
+	"github.com/worldline-go/chore/pkg/broker"
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+type fakePublisher struct {
+	messages []broker.Message
+	err      error
+}
+
+func (p *fakePublisher) Publish(_ context.Context, msg broker.Message) error {
+	p.messages = append(p.messages, msg)
+
+	return p.err
+}
+
+func (p *fakePublisher) Close() error {
+	return nil
+}
+
+func TestPublish_Run(t *testing.T) {
+	reg := &registry.Registry{Template: templatex.New()}
+	publisher := &fakePublisher{}
+
+	n := &Publish{
+		profile:   "events",
+		topic:     "orders.{{ .status }}",
+		key:       "{{ .id }}",
+		headers:   "X-Source: chore\nX-Customer: '{{ .customer }}'",
+		publisher: publisher,
+	}
+
+	payload := []byte(`{"id":"42","status":"created","customer":"ingenico"}`)
+
+	ret, err := n.Run(context.Background(), nil, reg, &EndpointRet{output: payload}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := broker.Message{
+		Topic:   "orders.created",
+		Key:     "42",
+		Headers: map[string]string{"X-Source": "chore", "X-Customer": "ingenico"},
+		Value:   payload,
+	}
+
+	if len(publisher.messages) != 1 || !reflect.DeepEqual(publisher.messages[0], want) {
+		t.Errorf("Publish.Run() messages = %+v, want %+v", publisher.messages, want)
+	}
+
+	if got := ret.(*PublishRet); !reflect.DeepEqual(got.selection, []int{1}) || string(got.output) != string(payload) {
+		t.Errorf("Publish.Run() selection = %v, output = %s", got.selection, got.output)
+	}
+
+	publisher.err = errors.New("broker down")
+
+	ret, err = n.Run(context.Background(), nil, reg, &EndpointRet{output: payload}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ret.(*PublishRet); !reflect.DeepEqual(got.selection, []int{0}) ||
+		string(got.output) != `{"error":"broker down","profile":"events","topic":"orders.created"}` {
+		t.Errorf("Publish.Run() selection = %v, output = %s", got.selection, got.output)
+	}
+
+	n.topic = "{{ if .missing }}orders{{ end }}"
+	if _, err := n.Run(context.Background(), nil, reg, &EndpointRet{output: payload}, ""); err == nil {
+		t.Error("Publish.Run() empty topic should fail")
+	}
+}
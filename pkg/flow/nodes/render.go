@@ -0,0 +1,23 @@
+package nodes
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/rytsh/mugo/pkg/templatex"
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+// renderValue renders content as go template with data.
+func renderValue(reg *registry.Registry, content string, data interface{}) (string, error) {
+	if content == "" {
+		return "", nil
+	}
+
+	var buf bytes.Buffer
+	if err := reg.Template.Execute(templatex.WithIO(&buf), templatex.WithData(data), templatex.WithContent(content)); err != nil {
+		return "", fmt.Errorf("template cannot render: %w", err)
+	}
+
+	return buf.String(), nil
+}
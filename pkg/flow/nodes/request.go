@@ -3,14 +3,18 @@ package nodes
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/rytsh/mugo/pkg/templatex"
+	"github.com/worldline-go/chore/pkg/extract"
 	"github.com/worldline-go/chore/pkg/flow"
 	"github.com/worldline-go/chore/pkg/flow/convert"
 	"github.com/worldline-go/chore/pkg/models"
@@ -26,6 +30,12 @@ import (
 
 var requestType = "request"
 
+// RequestCacheTTL is default time to keep cached responses.
+var RequestCacheTTL = 5 * time.Minute
+
+// RequestErrorHeader is set on failure output when a limit exceeded or circuit breaker open.
+var RequestErrorHeader = "X-Chore-Error"
+
 type inputHolderRequest struct {
 	value []byte
 	exist bool
@@ -34,9 +44,16 @@ type inputHolderRequest struct {
 type RequestRet struct {
 	selection []int
 	respond   flow.Respond
+	// output is extracted body, respond keeps original body
+	output    []byte
+	extracted bool
 }
 
 func (r *RequestRet) GetBinaryData() []byte {
+	if r.extracted {
+		return r.output
+	}
+
 	return r.respond.Data
 }
 
@@ -58,6 +75,22 @@ type retryRaw struct {
 	DeCodes string
 }
 
+// multipartSpec is rendered multipart configuration.
+type multipartSpec struct {
+	Fields map[string]interface{} `yaml:"fields"`
+	Files  []multipartFile        `yaml:"files"`
+}
+
+type multipartFile struct {
+	Field       string `yaml:"field"`
+	FileName    string `yaml:"filename"`
+	ContentType string `yaml:"content_type"`
+	// Content of the file, empty means input value.
+	Content string `yaml:"content"`
+	// Encoding of the content, base64 or binary.
+	Encoding string `yaml:"encoding"`
+}
+
 type renderedValues struct {
 	url           string
 	addHeadersRaw string
@@ -79,7 +112,17 @@ type Request struct {
 	url                string
 	addHeadersRaw      string
 	method             string
+	bodyMode           string
+	multipartRaw       string
+	timeout            string
+	connectTimeout     string
+	maxResponseBytes   string
+	pagination         pagination
+	extractType        string
+	extractExpression  string
+	extractor          *extract.Extractor
 	auth               string
+	authProfile        request.AuthProfile
 	outputs            [][]flow.Connection
 	inputs             []flow.Inputs
 	inputHolder        inputHolderRequest
@@ -91,11 +134,21 @@ type Request struct {
 	skipVerify         bool
 	retryDisabled      bool
 	oauth2Name         string
+	tlsName            string
+	tls                *request.TLSConfig
 	proxy              string
+	breaker            bool
+	breakerName        string
+	cache              request.CacheConfig
+	cacheTTL           string
 	stuckContext       context.Context
 	log                *zerolog.Logger
 	client             *request.Client
 	tags               []string
+	// bodyFn replaces body mode and can add headers, used by nodes built on request.
+	bodyFn func(reg *registry.Registry, payload []byte, headers map[string]interface{}) (request.Body, error)
+	// responseFn can change output of the response, returned reason moves response to failure output.
+	responseFn func(ret *RequestRet) string
 }
 
 // Run get values from active input nodes and it will not run until last input comes.
@@ -214,34 +267,98 @@ func (n *Request) Run(ctx context.Context, _ *sync.WaitGroup, reg *registry.Regi
 		headers[k] = addHeaders[k]
 	}
 
-	var payload []byte
+	var body request.Body
 	if !n.payloadNil {
-		payload = value.GetBinaryData()
+		var err error
+		if n.bodyFn != nil {
+			body, err = n.bodyFn(reg, value.GetBinaryData(), headers)
+		} else {
+			body, err = n.buildBody(reg, value.GetBinaryData())
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	callOptions, err := n.callOptions(reg, requestValues)
+	if err != nil {
+		return nil, err
 	}
 
 	if n.client == nil {
 		return nil, fmt.Errorf("http client not set")
 	}
 
-	response, err := n.client.Call(
-		ctx,
-		rendered.url,
-		rendered.method,
-		headers,
-		payload,
-	)
+	if n.pagination.style != "" {
+		return n.paginate(ctx, rendered.url, rendered.method, headers, body, callOptions)
+	}
+
+	response, failRet := n.call(ctx, rendered.url, rendered.method, headers, body, callOptions)
+	if failRet != nil {
+		return failRet, nil
+	}
+
+	ret := responseRet(response, response.Body)
+	if n.responseFn != nil {
+		if reason := n.responseFn(ret); reason != "" {
+			ret.respond.Header[RequestErrorHeader] = reason
+			ret.selection = []int{0, 2}
+		}
+	}
+
+	return n.shape(ret)
+}
+
+// shape extracts success output with the extract expression.
+func (n *Request) shape(ret *RequestRet) (*RequestRet, error) {
+	if n.extractor == nil || len(ret.selection) == 0 || ret.selection[0] != 1 {
+		return ret, nil
+	}
+
+	output, err := n.extractor.Bytes(ret.GetBinaryData())
+	if err != nil {
+		return nil, fmt.Errorf("extract response: %w", err)
+	}
+
+	ret.output = output
+	ret.extracted = true
+
+	return ret, nil
+}
+
+// call sends the request, returns failure result when request cannot complete.
+func (n *Request) call(
+	ctx context.Context,
+	url, method string,
+	headers map[string]interface{},
+	body request.Body,
+	callOptions request.CallOptions,
+) (*request.ClientResponse, *RequestRet) {
+	response, err := n.client.CallBody(ctx, url, method, headers, body, callOptions)
 	if err != nil {
+		if limitRet := requestLimitRet(err); limitRet != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("request limit exceeded or circuit open")
+
+			return nil, limitRet
+		}
+
 		// return nil, fmt.Errorf("failed to send request: %w", err)
-		return &RequestRet{
+		return nil, &RequestRet{
 			respond: flow.Respond{
 				Header: nil,
 				Data:   []byte(fmt.Sprint(err)),
 				Status: http.StatusServiceUnavailable,
 			},
 			selection: []int{0, 2},
-		}, nil
+		}
 	}
 
+	return response, nil
+}
+
+// responseRet selects output with status code.
+func responseRet(response *request.ClientResponse, data []byte) *RequestRet {
 	header := make(map[string]interface{})
 	for k, v := range response.Header {
 		header[k] = v[0]
@@ -251,21 +368,163 @@ func (n *Request) Run(ctx context.Context, _ *sync.WaitGroup, reg *registry.Regi
 		return &RequestRet{
 			respond: flow.Respond{
 				Header: header,
-				Data:   response.Body,
+				Data:   data,
 				Status: response.StatusCode,
 			},
 			selection: []int{1, 2},
-		}, nil
+		}
 	}
 
 	return &RequestRet{
 		respond: flow.Respond{
 			Header: header,
-			Data:   response.Body,
+			Data:   data,
 			Status: response.StatusCode,
 		},
 		selection: []int{0, 2},
-	}, nil
+	}
+}
+
+// callOptions renders timeouts and response limit.
+func (n *Request) callOptions(reg *registry.Registry, data interface{}) (request.CallOptions, error) {
+	opts := request.CallOptions{}
+
+	durations := []struct {
+		name  string
+		raw   string
+		value *time.Duration
+	}{
+		{"timeout", n.timeout, &opts.Timeout},
+		{"connect timeout", n.connectTimeout, &opts.ConnectTimeout},
+	}
+
+	for _, d := range durations {
+		rendered, err := renderValue(reg, d.raw, data)
+		if err != nil {
+			return opts, fmt.Errorf("%s %w", d.name, err)
+		}
+
+		if rendered = strings.TrimSpace(rendered); rendered == "" {
+			continue
+		}
+
+		if *d.value, err = time.ParseDuration(rendered); err != nil {
+			return opts, fmt.Errorf("%s %q cannot parse: %w", d.name, rendered, err)
+		}
+	}
+
+	rendered, err := renderValue(reg, n.maxResponseBytes, data)
+	if err != nil {
+		return opts, fmt.Errorf("max response bytes %w", err)
+	}
+
+	if rendered = strings.TrimSpace(rendered); rendered != "" {
+		if opts.MaxResponseBytes, err = strconv.ParseInt(rendered, 10, 64); err != nil {
+			return opts, fmt.Errorf("max response bytes %q cannot parse: %w", rendered, err)
+		}
+	}
+
+	return opts, nil
+}
+
+// requestLimitRet returns failure result for exceeded limits and open breaker, nil for other errors.
+func requestLimitRet(err error) *RequestRet {
+	var status int
+
+	var reason string
+
+	switch {
+	case errors.Is(err, request.ErrResponseTooLarge):
+		// upstream response is wrong, not the request of the caller
+		status = http.StatusBadGateway
+		reason = "response_too_large"
+	case errors.Is(err, request.ErrBreakerOpen):
+		status = http.StatusServiceUnavailable
+		reason = "circuit_open"
+	case request.IsTimeout(err):
+		status = http.StatusGatewayTimeout
+		reason = "timeout"
+	default:
+		return nil
+	}
+
+	return &RequestRet{
+		respond: flow.Respond{
+			Header: map[string]interface{}{RequestErrorHeader: reason},
+			Data:   []byte(err.Error()),
+			Status: status,
+		},
+		selection: []int{0, 2},
+	}
+}
+
+// buildBody prepares body with the body mode, form and multipart fields come from payload object.
+func (n *Request) buildBody(reg *registry.Registry, payload []byte) (request.Body, error) {
+	body := request.Body{Mode: n.bodyMode}
+
+	switch n.bodyMode {
+	case request.BodyForm:
+		fields, ok := transfer.BytesToData(payload).(map[string]interface{})
+		if !ok && len(payload) != 0 {
+			return body, fmt.Errorf("form body needs an object")
+		}
+
+		body.Fields = fields
+	case request.BodyMultipart:
+		data := transfer.BytesToData(payload)
+
+		if strings.TrimSpace(n.multipartRaw) == "" {
+			fields, ok := data.(map[string]interface{})
+			if !ok && len(payload) != 0 {
+				return body, fmt.Errorf("multipart body needs an object")
+			}
+
+			body.Fields = fields
+
+			return body, nil
+		}
+
+		rendered, err := renderValue(reg, n.multipartRaw, data)
+		if err != nil {
+			return body, fmt.Errorf("multipart %w", err)
+		}
+
+		var spec multipartSpec
+		if err := yaml.Unmarshal([]byte(rendered), &spec); err != nil {
+			return body, fmt.Errorf("multipart cannot unmarshal: %w", err)
+		}
+
+		body.Fields = spec.Fields
+
+		for _, f := range spec.Files {
+			content := payload
+
+			if f.Content != "" {
+				switch f.Encoding {
+				case "base64":
+					content, err = base64.StdEncoding.DecodeString(strings.TrimSpace(f.Content))
+					if err != nil {
+						return body, fmt.Errorf("multipart file %s cannot decode: %w", f.Field, err)
+					}
+				case "", "binary":
+					content = []byte(f.Content)
+				default:
+					return body, fmt.Errorf("multipart file %s encoding %q not supported", f.Field, f.Encoding)
+				}
+			}
+
+			body.Files = append(body.Files, request.File{
+				Field:       f.Field,
+				FileName:    f.FileName,
+				ContentType: f.ContentType,
+				Content:     content,
+			})
+		}
+	default:
+		body.Raw = payload
+	}
+
+	return body, nil
 }
 
 func (n *Request) GetType() string {
@@ -284,12 +543,28 @@ func (n *Request) Fetch(ctx context.Context, db *gorm.DB) error {
 		}
 
 		n.headers = getData.Headers
+
+		if getData.Type != "" && getData.Type != request.AuthHeader {
+			config, err := json.Marshal(getData.Config)
+			if err != nil {
+				return fmt.Errorf("request fetch auth %s failed: %w", n.auth, err)
+			}
+
+			n.authProfile = request.AuthProfile{}
+			if err := json.Unmarshal(config, &n.authProfile); err != nil {
+				return fmt.Errorf("request fetch auth %s failed: %w", n.auth, err)
+			}
+
+			n.authProfile.Name = n.auth
+			n.authProfile.Type = getData.Type
+		}
 	}
 
 	// get oauth2 specs
 	if n.oauth2Name != "" {
 		authConfig := request.AuthConfig{
 			Enabled: true,
+			Name:    n.oauth2Name,
 		}
 
 		data := map[string]interface{}{}
@@ -308,6 +583,16 @@ func (n *Request) Fetch(ctx context.Context, db *gorm.DB) error {
 		n.oauth2 = authConfig
 	}
 
+	// get tls profile
+	if n.tlsName != "" {
+		tlsConfig, err := fetchTLS(ctx, db, n.tlsName)
+		if err != nil {
+			return fmt.Errorf("request %w", err)
+		}
+
+		n.tls = tlsConfig
+	}
+
 	// fill retry values
 	retryCodes, err := getCodes(n.retryRaw.Codes)
 	if err != nil {
@@ -321,14 +606,21 @@ func (n *Request) Fetch(ctx context.Context, db *gorm.DB) error {
 
 	n.client, err = request.NewClient(request.Config{ //nolint:contextcheck // application context using
 		SkipVerify: n.skipVerify,
+		TLS:        n.tls,
 		Log:        n.log,
 		Retry: request.Retry{
 			Enabled:             true,
 			EnabledStatusCodes:  retryCodes,
 			DisabledStatusCodes: retryDeCodes,
 		},
-		Auth:  n.oauth2,
-		Proxy: n.proxy,
+		Auth:    n.oauth2,
+		Profile: n.authProfile,
+		Proxy:   n.proxy,
+		Breaker: request.BreakerConfig{
+			Enabled: n.breaker,
+			Name:    n.breakerName,
+		},
+		Cache: n.cache,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create http client: %w", err)
@@ -339,6 +631,26 @@ func (n *Request) Fetch(ctx context.Context, db *gorm.DB) error {
 	return nil
 }
 
+// fetchTLS returns tls settings profile with name.
+func fetchTLS(ctx context.Context, db *gorm.DB, name string) (*request.TLSConfig, error) {
+	tlsConfig := request.TLSConfig{}
+
+	data := map[string]interface{}{}
+	query := db.WithContext(ctx).Model(&models.Settings{}).Where("namespace = ?", "tls").Where("name = ?", name)
+	result := query.First(&data)
+	if result.Error != nil {
+		return nil, fmt.Errorf("fetch tls %s failed: %w", name, result.Error)
+	}
+
+	dataInner, _ := data["data"].(string)
+
+	if err := json.Unmarshal([]byte(dataInner), &tlsConfig); err != nil {
+		return nil, fmt.Errorf("fetch tls %s failed: %w", name, err)
+	}
+
+	return &tlsConfig, nil
+}
+
 func (n *Request) IsFetched() bool {
 	return n.fetched
 }
@@ -357,6 +669,34 @@ func (n *Request) Validate(ctx context.Context) error {
 		return fmt.Errorf("url is empty")
 	}
 
+	if n.extractExpression != "" {
+		extractor, err := extract.New(n.extractType, n.extractExpression)
+		if err != nil {
+			return err //nolint:wrapcheck // clear error
+		}
+
+		n.extractor = extractor
+	}
+
+	if n.cache.Enabled {
+		n.cache.TTL = RequestCacheTTL
+
+		if n.cacheTTL != "" {
+			ttl, err := time.ParseDuration(n.cacheTTL)
+			if err != nil {
+				return fmt.Errorf("cache ttl %q cannot parse: %w", n.cacheTTL, err)
+			}
+
+			n.cache.TTL = ttl
+		}
+	}
+
+	switch n.bodyMode {
+	case "", request.BodyRaw, request.BodyForm, request.BodyMultipart:
+	default:
+		return fmt.Errorf("body mode %q not supported", n.bodyMode)
+	}
+
 	n.stuckContext = n.reg.GetStuctCancel(ctx)
 
 	return nil
@@ -423,6 +763,15 @@ func NewRequest(ctx context.Context, reg *flow.NodesReg, data flow.NodeData, nod
 	method, _ := data.Data["method"].(string)
 	url, _ := data.Data["url"].(string)
 	addHeadersRaw, _ := data.Data["headers"].(string)
+	bodyMode, _ := data.Data["body_mode"].(string)
+	multipartRaw, _ := data.Data["multipart"].(string)
+	timeout, _ := data.Data["timeout"].(string)
+	connectTimeout, _ := data.Data["connect_timeout"].(string)
+	// number or template
+	var maxResponseBytes string
+	if v := data.Data["max_response_bytes"]; v != nil {
+		maxResponseBytes = fmt.Sprint(v)
+	}
 
 	retryCodes, _ := data.Data["retry_codes"].(string)
 	retryDeCodes, _ := data.Data["retry_decodes"].(string)
@@ -433,19 +782,42 @@ func NewRequest(ctx context.Context, reg *flow.NodesReg, data flow.NodeData, nod
 
 	oauth2Name, _ := data.Data["oauth2"].(string)
 	proxy, _ := data.Data["proxy"].(string)
+	tlsName, _ := data.Data["tls"].(string)
+
+	breaker := convert.GetBoolean(data.Data["breaker"])
+	breakerName, _ := data.Data["breaker_name"].(string)
+
+	cacheTTL, _ := data.Data["cache_ttl"].(string)
+	cacheHeaders, _ := data.Data["cache_headers"].(string)
+
+	extractType, _ := data.Data["extract_type"].(string)
+	extractExpression, _ := data.Data["extract"].(string)
+
+	pagination, err := newPagination(data.Data)
+	if err != nil {
+		return nil, err
+	}
 
 	tags := convert.GetList(data.Data["tags"])
 
 	l := log.Ctx(ctx).With().Str("component", requestType).Logger()
 
 	return &Request{
-		reg:           reg,
-		inputs:        inputs,
-		outputs:       outputs,
-		auth:          auth,
-		method:        method,
-		url:           url,
-		addHeadersRaw: addHeadersRaw,
+		reg:               reg,
+		inputs:            inputs,
+		outputs:           outputs,
+		auth:              auth,
+		method:            method,
+		url:               url,
+		addHeadersRaw:     addHeadersRaw,
+		bodyMode:          bodyMode,
+		multipartRaw:      multipartRaw,
+		timeout:           timeout,
+		connectTimeout:    connectTimeout,
+		maxResponseBytes:  maxResponseBytes,
+		pagination:        pagination,
+		extractType:       extractType,
+		extractExpression: extractExpression,
 		retryRaw: retryRaw{
 			Codes:   strings.ReplaceAll(retryCodes, ",", " "),
 			DeCodes: strings.ReplaceAll(retryDeCodes, ",", " "),
@@ -457,7 +829,16 @@ func NewRequest(ctx context.Context, reg *flow.NodesReg, data flow.NodeData, nod
 		nodeID:        nodeID,
 		tags:          tags,
 		oauth2Name:    oauth2Name,
+		tlsName:       tlsName,
 		proxy:         proxy,
+		breaker:       breaker,
+		breakerName:   breakerName,
+		cache: request.CacheConfig{
+			Enabled: convert.GetBoolean(data.Data["cache"]),
+			Headers: strings.Fields(strings.ReplaceAll(cacheHeaders, ",", " ")),
+			Control: convert.GetBoolean(data.Data["cache_control"]),
+		},
+		cacheTTL: cacheTTL,
 	}, nil
 }
 
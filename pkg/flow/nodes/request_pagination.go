@@ -0,0 +1,357 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/worldline-go/chore/pkg/flow"
+	"github.com/worldline-go/chore/pkg/flow/convert"
+	"github.com/worldline-go/chore/pkg/request"
+	"github.com/worldline-go/chore/pkg/script/js"
+	"github.com/worldline-go/chore/pkg/transfer"
+)
+
+// Pagination styles.
+var (
+	PaginationLink   = "link"
+	PaginationCursor = "cursor"
+	PaginationOffset = "offset"
+	PaginationPage   = "page"
+)
+
+// Pagination emit types.
+var (
+	PaginationEmitAggregate = "aggregate"
+	PaginationEmitPage      = "page"
+)
+
+// PaginationMaxPages is default safety limit of pages.
+var PaginationMaxPages = 100
+
+type pagination struct {
+	style string
+	// items is js expression to get items from data, empty means body.
+	items string
+	// next is js expression to get next cursor from data.
+	next       string
+	param      string
+	limitParam string
+	limit      int
+	start      int
+	maxPages   int
+	emit       string
+}
+
+func newPagination(data map[string]interface{}) (pagination, error) {
+	p := pagination{}
+
+	p.style, _ = data["pagination"].(string)
+	if p.style == "" {
+		return p, nil
+	}
+
+	p.items, _ = data["pagination_items"].(string)
+	p.next, _ = data["pagination_next"].(string)
+	p.param, _ = data["pagination_param"].(string)
+	p.limitParam, _ = data["pagination_limit_param"].(string)
+	p.emit, _ = data["pagination_emit"].(string)
+
+	var err error
+
+	if p.limit, err = convert.GetInt(data["pagination_limit"]); err != nil {
+		return p, fmt.Errorf("pagination limit: %w", err)
+	}
+
+	if p.start, err = convert.GetInt(data["pagination_start"]); err != nil {
+		return p, fmt.Errorf("pagination start: %w", err)
+	}
+
+	if p.maxPages, err = convert.GetInt(data["pagination_max_pages"]); err != nil {
+		return p, fmt.Errorf("pagination max pages: %w", err)
+	}
+
+	if p.maxPages <= 0 {
+		p.maxPages = PaginationMaxPages
+	}
+
+	switch p.emit {
+	case "":
+		p.emit = PaginationEmitAggregate
+	case PaginationEmitAggregate, PaginationEmitPage:
+	default:
+		return p, fmt.Errorf("pagination emit %q not supported", p.emit)
+	}
+
+	switch p.style {
+	case PaginationLink:
+	case PaginationCursor:
+		if p.next == "" {
+			return p, fmt.Errorf("pagination cursor needs next expression")
+		}
+
+		if p.param == "" {
+			p.param = "cursor"
+		}
+	case PaginationOffset:
+		if p.param == "" {
+			p.param = "offset"
+		}
+
+		if p.limitParam == "" {
+			p.limitParam = "limit"
+		}
+	case PaginationPage:
+		if p.param == "" {
+			p.param = "page"
+		}
+
+		if p.start == 0 {
+			p.start = 1
+		}
+	default:
+		return p, fmt.Errorf("pagination %q not supported", p.style)
+	}
+
+	return p, nil
+}
+
+// paginate calls pages until there is no next page or max pages reached.
+func (n *Request) paginate(
+	ctx context.Context,
+	startURL, method string,
+	headers map[string]interface{},
+	body request.Body,
+	callOptions request.CallOptions,
+) (flow.NodeRet, error) {
+	p := n.pagination
+
+	pageURL, err := p.firstURL(startURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		items    []interface{}
+		response *request.ClientResponse
+		pages    int
+		count    int
+	)
+
+	position := p.start
+
+	for page := 1; ; page++ {
+		var failRet *RequestRet
+
+		response, failRet = n.call(ctx, pageURL, method, headers, body, callOptions)
+		if failRet != nil {
+			return failRet, nil
+		}
+
+		if response.StatusCode < 100 || response.StatusCode >= 400 {
+			return responseRet(response, response.Body), nil
+		}
+
+		data := transfer.BytesToData(response.Body)
+
+		pageItems, err := p.extractItems(data)
+		if err != nil {
+			return nil, err
+		}
+
+		pages = page
+		count += len(pageItems)
+
+		if p.emit == PaginationEmitPage {
+			pageRet, err := n.shape(responseRet(response, transfer.DataToBytes(pageItems)))
+			if err != nil {
+				return nil, err
+			}
+
+			n.reg.Branch(ctx, n.Next(1), pageRet)
+		} else {
+			items = append(items, pageItems...)
+		}
+
+		nextURL, err := p.nextURL(pageURL, response, data, len(pageItems), &position)
+		if err != nil {
+			return nil, err
+		}
+
+		if nextURL == "" {
+			break
+		}
+
+		if page >= p.maxPages {
+			log.Ctx(ctx).Warn().Msgf("pagination stopped at max pages %d", p.maxPages)
+
+			break
+		}
+
+		pageURL = nextURL
+	}
+
+	if p.emit == PaginationEmitPage {
+		// pages already sent, always output reports pagination finished
+		ret := responseRet(response, transfer.DataToBytes(map[string]interface{}{"pages": pages, "items": count}))
+		ret.selection = []int{2}
+
+		return ret, nil
+	}
+
+	if items == nil {
+		items = []interface{}{}
+	}
+
+	return n.shape(responseRet(response, transfer.DataToBytes(items)))
+}
+
+// firstURL sets start position to the url.
+func (p pagination) firstURL(rawURL string) (string, error) {
+	switch p.style {
+	case PaginationOffset:
+		query := map[string]string{p.param: strconv.Itoa(p.start)}
+		if p.limit > 0 {
+			query[p.limitParam] = strconv.Itoa(p.limit)
+		}
+
+		return setQuery(rawURL, query)
+	case PaginationPage:
+		return setQuery(rawURL, map[string]string{p.param: strconv.Itoa(p.start)})
+	}
+
+	return rawURL, nil
+}
+
+// nextURL returns empty string when there is no next page.
+func (p pagination) nextURL(current string, response *request.ClientResponse, data interface{}, count int, position *int) (string, error) {
+	switch p.style {
+	case PaginationLink:
+		next := linkNext(response.Header.Values("Link"))
+		if next == "" {
+			return "", nil
+		}
+
+		return resolveURL(current, next)
+	case PaginationCursor:
+		cursor, err := p.runExpression(p.next, data)
+		if err != nil {
+			return "", fmt.Errorf("pagination next: %w", err)
+		}
+
+		if cursor == nil || fmt.Sprint(cursor) == "" {
+			return "", nil
+		}
+
+		return setQuery(current, map[string]string{p.param: fmt.Sprint(cursor)})
+	}
+
+	// offset and page styles stop with empty or not full page
+	if count == 0 || (p.limit > 0 && count < p.limit) {
+		return "", nil
+	}
+
+	if p.style == PaginationOffset {
+		*position += count
+	} else {
+		*position++
+	}
+
+	return setQuery(current, map[string]string{p.param: strconv.Itoa(*position)})
+}
+
+func (p pagination) extractItems(data interface{}) ([]interface{}, error) {
+	if p.items != "" {
+		v, err := p.runExpression(p.items, data)
+		if err != nil {
+			return nil, fmt.Errorf("pagination items: %w", err)
+		}
+
+		data = v
+	}
+
+	switch v := data.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		return v, nil
+	}
+
+	return []interface{}{data}, nil
+}
+
+func (p pagination) runExpression(expression string, data interface{}) (interface{}, error) {
+	runner := js.NewGoja()
+
+	if err := runner.SetData(data); err != nil {
+		return nil, fmt.Errorf("cannot set data in script: %w", err)
+	}
+
+	v, err := runner.RunString(expression)
+	if err != nil {
+		return nil, fmt.Errorf("cannot run expression: %w", err)
+	}
+
+	return v.Export(), nil
+}
+
+// linkNext returns url of rel="next" in RFC 5988 link headers.
+func linkNext(links []string) string {
+	for _, header := range links {
+		for _, link := range strings.Split(header, ",") {
+			parts := strings.Split(link, ";")
+			if len(parts) < 2 { //nolint:gomnd // url and params
+				continue
+			}
+
+			for _, param := range parts[1:] {
+				key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(key, "rel") {
+					continue
+				}
+
+				for _, rel := range strings.Fields(strings.Trim(value, `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return strings.Trim(strings.TrimSpace(parts[0]), "<>")
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+func resolveURL(current, ref string) (string, error) {
+	base, err := url.Parse(current)
+	if err != nil {
+		return "", fmt.Errorf("url %s cannot parse: %w", current, err)
+	}
+
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("next url %s cannot parse: %w", ref, err)
+	}
+
+	return base.ResolveReference(refURL).String(), nil
+}
+
+func setQuery(rawURL string, values map[string]string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("url %s cannot parse: %w", rawURL, err)
+	}
+
+	query := u.Query()
+	for k, v := range values {
+		query.Set(k, v)
+	}
+
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
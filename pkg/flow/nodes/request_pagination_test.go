@@ -0,0 +1,155 @@
+package nodes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/worldline-go/chore/pkg/flow"
+	"github.com/worldline-go/chore/pkg/request"
+)
+
+func TestLinkNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		links []string
+		want  string
+	}{
+		{
+			name:  "github style",
+			links: []string{`<https://api.github.com/repos?page=2>; rel="next", <https://api.github.com/repos?page=5>; rel="last"`},
+			want:  "https://api.github.com/repos?page=2",
+		},
+		{
+			name:  "last page",
+			links: []string{`<https://api.github.com/repos?page=1>; rel="prev"`},
+			want:  "",
+		},
+		{
+			name:  "multiple rel",
+			links: []string{`</items?cursor=abc>; rel="next last"`},
+			want:  "/items?cursor=abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := linkNext(tt.links); got != tt.want {
+				t.Errorf("linkNext() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequest_paginate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/link":
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			if page < 2 {
+				w.Header().Set("Link", fmt.Sprintf(`</link?page=%d>; rel="next"`, page+1))
+			}
+
+			fmt.Fprintf(w, `[%d]`, page)
+		case "/offset":
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			items := []int{1, 2, 3, 4, 5}[min(offset, 5):min(offset+2, 5)]
+
+			values, _ := json.Marshal(items)
+
+			fmt.Fprintf(w, `{"values": %s}`, values)
+		case "/cursor":
+			if r.URL.Query().Get("cursor") == "" {
+				fmt.Fprint(w, `{"items": ["a"], "next": "x"}`)
+			} else {
+				fmt.Fprint(w, `{"items": ["b"]}`)
+			}
+		}
+	}))
+	defer srv.Close()
+
+	client, err := request.NewClient(request.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		data map[string]interface{}
+		want string
+	}{
+		{
+			name: "link",
+			path: "/link",
+			data: map[string]interface{}{"pagination": "link"},
+			want: "[0,1,2]",
+		},
+		{
+			name: "offset",
+			path: "/offset",
+			data: map[string]interface{}{
+				"pagination":       "offset",
+				"pagination_limit": "2",
+				"pagination_items": "data.values",
+			},
+			want: "[1,2,3,4,5]",
+		},
+		{
+			name: "cursor",
+			path: "/cursor",
+			data: map[string]interface{}{
+				"pagination":       "cursor",
+				"pagination_items": "data.items",
+				"pagination_next":  "data.next",
+			},
+			want: `["a","b"]`,
+		},
+		{
+			name: "max pages",
+			path: "/link",
+			data: map[string]interface{}{"pagination": "link", "pagination_max_pages": 2},
+			want: "[0,1]",
+		},
+		{
+			name: "emit page",
+			path: "/offset",
+			data: map[string]interface{}{
+				"pagination":       "offset",
+				"pagination_limit": "2",
+				"pagination_items": "data.values",
+				"pagination_emit":  "page",
+			},
+			want: `{"items":5,"pages":3}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := newPagination(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			n := &Request{
+				client:     client,
+				pagination: p,
+				reg:        flow.NewNodesReg("test", "test", "GET", nil),
+				outputs:    make([][]flow.Connection, 3),
+			}
+
+			ret, err := n.paginate(context.Background(), srv.URL+tt.path, http.MethodGet, nil, request.Body{}, request.CallOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := string(ret.GetBinaryData()); got != tt.want {
+				t.Errorf("Request.paginate() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package nodes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/worldline-go/chore/pkg/request"
+)
+
+func TestRequestLimitRet(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		reason string
+	}{
+		{
+			name:   "response too large",
+			err:    fmt.Errorf("%w; limit 5 bytes", request.ErrResponseTooLarge),
+			status: http.StatusBadGateway,
+			reason: "response_too_large",
+		},
+		{
+			name:   "breaker open",
+			err:    request.ErrBreakerOpen,
+			status: http.StatusServiceUnavailable,
+			reason: "circuit_open",
+		},
+		{
+			name:   "timeout",
+			err:    fmt.Errorf("call failed: %w", context.DeadlineExceeded),
+			status: http.StatusGatewayTimeout,
+			reason: "timeout",
+		},
+		{
+			name: "other error",
+			err:  errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := requestLimitRet(tt.err)
+			if tt.status == 0 {
+				if got != nil {
+					t.Errorf("requestLimitRet() = %v, want nil", got)
+				}
+
+				return
+			}
+
+			if got == nil {
+				t.Fatal("requestLimitRet() = nil")
+			}
+
+			if got.respond.Status != tt.status {
+				t.Errorf("requestLimitRet() status = %d, want %d", got.respond.Status, tt.status)
+			}
+
+			if got.respond.Header[RequestErrorHeader] != tt.reason {
+				t.Errorf("requestLimitRet() reason = %v, want %s", got.respond.Header[RequestErrorHeader], tt.reason)
+			}
+		})
+	}
+}
@@ -62,7 +62,7 @@ type Script struct {
 // selection 0 is false.
 //
 //nolint:lll // false positive
-func (n *Script) Run(ctx context.Context, _ *sync.WaitGroup, _ *registry.Registry, value flow.NodeRet, input string) (flow.NodeRet, error) {
+func (n *Script) Run(ctx context.Context, _ *sync.WaitGroup, reg *registry.Registry, value flow.NodeRet, input string) (flow.NodeRet, error) {
 	var transferValue interface{}
 	if value.GetBinaryData() != nil {
 		transferValue = transfer.BytesToData(value.GetBinaryData())
@@ -107,6 +107,9 @@ func (n *Script) Run(ctx context.Context, _ *sync.WaitGroup, _ *registry.Registr
 
 	// create script runner
 	runner := js.NewGoja()
+	if reg != nil {
+		runner.SetDB(reg.DB)
+	}
 
 	var valueToPass interface{}
 	// value for some nodes
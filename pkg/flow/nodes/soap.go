@@ -0,0 +1,228 @@
+package nodes
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/worldline-go/chore/pkg/flow"
+	"github.com/worldline-go/chore/pkg/models"
+	"github.com/worldline-go/chore/pkg/registry"
+	"github.com/worldline-go/chore/pkg/request"
+	"github.com/worldline-go/chore/pkg/transfer"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+var soapType = "soap"
+
+// SOAP envelope namespaces.
+const (
+	SOAP11Namespace = "http://schemas.xmlsoap.org/soap/envelope/"
+	SOAP12Namespace = "http://www.w3.org/2003/05/soap-envelope"
+)
+
+// SOAP node sends rendered envelope, transport is same as request node.
+// Response body is converted to JSON and faults go to failure output.
+type SOAP struct {
+	*Request
+	envelope         string
+	envelopeTemplate string
+	action           string
+	version          string
+}
+
+// body renders envelope with input data, content without Envelope root wrapped in Body.
+// SOAP 1.1 action is set to SOAPAction header, 1.2 action is in content type.
+func (n *SOAP) body(reg *registry.Registry, payload []byte, headers map[string]interface{}) (request.Body, error) {
+	data := transfer.XMLBytesToData(payload)
+
+	rendered, err := renderValue(reg, n.envelope, data)
+	if err != nil {
+		return request.Body{}, fmt.Errorf("soap envelope %w", err)
+	}
+
+	action, err := renderValue(reg, n.action, data)
+	if err != nil {
+		return request.Body{}, fmt.Errorf("soap action %w", err)
+	}
+
+	action = strings.TrimSpace(action)
+
+	namespace := SOAP11Namespace
+	contentType := "text/xml; charset=utf-8"
+
+	if n.version == "1.2" {
+		namespace = SOAP12Namespace
+		contentType = "application/soap+xml; charset=utf-8"
+
+		if action != "" {
+			contentType += `; action="` + action + `"`
+		}
+	} else if _, ok := headers["SOAPAction"]; !ok && headers != nil {
+		headers["SOAPAction"] = `"` + action + `"`
+	}
+
+	raw := []byte(rendered)
+	if xmlRootLocal([]byte(rendered)) != "Envelope" {
+		raw = []byte(`<soap:Envelope xmlns:soap="` + namespace + `"><soap:Body>` + rendered + `</soap:Body></soap:Envelope>`)
+	}
+
+	return request.Body{
+		Mode:        request.BodyRaw,
+		Raw:         raw,
+		ContentType: contentType,
+	}, nil
+}
+
+// xmlRootLocal returns local name of the root element.
+func xmlRootLocal(data []byte) string {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		token, err := decoder.RawToken()
+		if err != nil {
+			return ""
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local
+		}
+	}
+}
+
+// soapResponse sets output to JSON of the body content, returns reason for fault.
+func soapResponse(ret *RequestRet) string {
+	if !transfer.IsXML(ret.respond.Data) {
+		return ""
+	}
+
+	data, err := transfer.XMLToData(ret.respond.Data)
+	if err != nil {
+		return ""
+	}
+
+	envelope, _ := findLocal(data, "Envelope").(map[string]interface{})
+	if envelope == nil {
+		return ""
+	}
+
+	body := findLocal(envelope, "Body")
+
+	output, err := json.Marshal(body)
+	if err != nil {
+		return ""
+	}
+
+	ret.output = output
+	ret.extracted = true
+
+	if bodyMap, ok := body.(map[string]interface{}); ok && findLocal(bodyMap, "Fault") != nil {
+		return "soap_fault"
+	}
+
+	return ""
+}
+
+// findLocal returns value with local name, prefix of the key is ignored.
+func findLocal(data interface{}, local string) interface{} {
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	for k, v := range m {
+		if k == local || strings.HasSuffix(k, ":"+local) {
+			return v
+		}
+	}
+
+	return nil
+}
+
+func (n *SOAP) GetType() string {
+	return soapType
+}
+
+func (n *SOAP) Fetch(ctx context.Context, db *gorm.DB) error {
+	if n.envelopeTemplate != "" {
+		getData := models.TemplatePure{}
+
+		query := db.WithContext(ctx).Model(&models.Template{}).Where("name = ?", n.envelopeTemplate)
+		result := query.First(&getData)
+
+		if result.Error != nil {
+			return fmt.Errorf("soap fetch template %s failed: %w", n.envelopeTemplate, result.Error)
+		}
+
+		content, err := base64.StdEncoding.DecodeString(getData.Content)
+		if err != nil {
+			return fmt.Errorf("soap fetch template %s failed: %w", n.envelopeTemplate, err)
+		}
+
+		n.envelope = string(content)
+	}
+
+	return n.Request.Fetch(ctx, db)
+}
+
+func (n *SOAP) Validate(ctx context.Context) error {
+	if strings.TrimSpace(n.envelope) == "" && n.envelopeTemplate == "" {
+		return fmt.Errorf("soap envelope is empty")
+	}
+
+	switch n.version {
+	case "", "1.1", "1.2":
+	default:
+		return fmt.Errorf("soap version %q not supported", n.version)
+	}
+
+	n.Request.method = http.MethodPost
+
+	return n.Request.Validate(ctx)
+}
+
+func NewSOAP(ctx context.Context, reg *flow.NodesReg, data flow.NodeData, nodeID string) (flow.Noder, error) {
+	noder, err := NewRequest(ctx, reg, data, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	req, _ := noder.(*Request)
+
+	envelope, _ := data.Data["envelope"].(string)
+	envelopeTemplate, _ := data.Data["template"].(string)
+	action, _ := data.Data["soap_action"].(string)
+	version, _ := data.Data["soap_version"].(string)
+
+	n := &SOAP{
+		Request:          req,
+		envelope:         envelope,
+		envelopeTemplate: envelopeTemplate,
+		action:           action,
+		version:          version,
+	}
+
+	l := log.Ctx(ctx).With().Str("component", soapType).Logger()
+
+	// body always comes from envelope, pagination not used
+	req.log = &l
+	req.bodyFn = n.body
+	req.responseFn = soapResponse
+	req.payloadNil = false
+	req.bodyMode = request.BodyRaw
+	req.pagination = pagination{}
+
+	return n, nil
+}
+
+//nolint:gochecknoinits // moduler nodes
+func init() {
+	flow.NodeTypes[soapType] = NewSOAP
+}
@@ -0,0 +1,93 @@
+package nodes
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/rytsh/mugo/pkg/templatex"
+	"github.com/worldline-go/chore/pkg/flow"
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+func TestSOAP_body(t *testing.T) {
+	reg := &registry.Registry{Template: templatex.New()}
+
+	n := &SOAP{
+		envelope: `<m:GetPrice xmlns:m="https://example.com/prices"><m:Item>{{ .req.item }}</m:Item></m:GetPrice>`,
+		action:   "https://example.com/GetPrice",
+	}
+
+	headers := map[string]interface{}{}
+
+	body, err := n.body(reg, []byte(`<req><item>apple</item></req>`), headers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>` +
+		`<m:GetPrice xmlns:m="https://example.com/prices"><m:Item>apple</m:Item></m:GetPrice></soap:Body></soap:Envelope>`
+
+	if got := string(body.Raw); got != want {
+		t.Errorf("SOAP.body() = %s, want %s", got, want)
+	}
+
+	if headers["SOAPAction"] != `"https://example.com/GetPrice"` || body.ContentType != "text/xml; charset=utf-8" {
+		t.Errorf("SOAP.body() action = %v, content type = %s", headers["SOAPAction"], body.ContentType)
+	}
+
+	n.envelope = `<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope"><env:Body>{{ .req.item }}</env:Body></env:Envelope>`
+	n.version = "1.2"
+
+	body, err = n.body(reg, []byte(`<req><item>apple</item></req>`), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := string(body.Raw); got != `<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope"><env:Body>apple</env:Body></env:Envelope>` {
+		t.Errorf("SOAP.body() = %s", got)
+	}
+
+	if body.ContentType != `application/soap+xml; charset=utf-8; action="https://example.com/GetPrice"` {
+		t.Errorf("SOAP.body() content type = %s", body.ContentType)
+	}
+}
+
+func TestSOAP_response(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		reason string
+		output string
+	}{
+		{
+			name:   "response",
+			data:   `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><Price currency="EUR">1.90</Price></s:Body></s:Envelope>`,
+			output: `{"Price":{"#text":"1.90","@currency":"EUR"}}`,
+		},
+		{
+			name:   "fault",
+			data:   `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><s:Fault><faultcode>s:Client</faultcode><faultstring>bad item</faultstring></s:Fault></s:Body></s:Envelope>`,
+			reason: "soap_fault",
+			output: `{"s:Fault":{"faultcode":"s:Client","faultstring":"bad item"}}`,
+		},
+		{
+			name:   "not xml",
+			data:   `service unavailable`,
+			output: `service unavailable`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ret := &RequestRet{respond: flow.Respond{Data: []byte(tt.data), Status: http.StatusOK}}
+
+			if reason := soapResponse(ret); reason != tt.reason {
+				t.Errorf("soapResponse() = %q, want %q", reason, tt.reason)
+			}
+
+			if got := string(ret.GetBinaryData()); got != tt.output {
+				t.Errorf("soapResponse() output = %s, want %s", got, tt.output)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package nodes
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+
+	"github.com/worldline-go/chore/pkg/database"
+	"github.com/worldline-go/chore/pkg/flow"
+	"github.com/worldline-go/chore/pkg/flow/convert"
+	"github.com/worldline-go/chore/pkg/models"
+	"github.com/worldline-go/chore/pkg/registry"
+	"github.com/worldline-go/chore/pkg/transfer"
+)
+
+var sqlType = "sql"
+
+type SQLRet struct {
+	output    []byte
+	selection []int
+}
+
+func (r *SQLRet) GetBinaryData() []byte {
+	return r.output
+}
+
+func (r *SQLRet) GetSelection() []int {
+	return r.selection
+}
+
+var _ flow.NodeRetSelection = (*SQLRet)(nil)
+
+// SQL node has one input and two outputs, failure and success.
+// Runs parameterised query on a database profile, parameters are bound from input data.
+type SQL struct {
+	profile     string
+	query       string
+	mode        string
+	params      map[string]string
+	transaction bool
+	readOnly    bool
+	timeout     string
+	db          *sql.DB
+	reg         *flow.NodesReg
+	outputs     [][]flow.Connection
+	fetched     bool
+	checked     bool
+	disabled    bool
+	nodeID      string
+	tags        []string
+}
+
+// Run executes query, database errors go to failure output.
+func (n *SQL) Run(ctx context.Context, _ *sync.WaitGroup, reg *registry.Registry, value flow.NodeRet, _ string) (flow.NodeRet, error) {
+	data := transfer.BytesToData(value.GetBinaryData())
+
+	timeout, err := renderValue(reg, n.timeout, data)
+	if err != nil {
+		return nil, fmt.Errorf("sql timeout %w", err)
+	}
+
+	if timeout = strings.TrimSpace(timeout); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			return nil, fmt.Errorf("sql timeout %q cannot parse: %w", timeout, err)
+		}
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+
+		defer cancel()
+	}
+
+	results, err := database.Run(ctx, n.db, n.query, data, database.Options{
+		Mode:        n.mode,
+		Transaction: n.transaction,
+		ReadOnly:    n.readOnly,
+		Params:      n.params,
+	})
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("profile", n.profile).Msg("sql failed")
+
+		output, _ := json.Marshal(map[string]string{
+			"error":   err.Error(),
+			"profile": n.profile,
+		})
+
+		return &SQLRet{output: output, selection: []int{0}}, nil
+	}
+
+	var output []byte
+	if len(results) == 1 {
+		output, err = json.Marshal(results[0])
+	} else {
+		output, err = json.Marshal(results)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("sql result cannot marshal: %w", err)
+	}
+
+	return &SQLRet{output: output, selection: []int{1}}, nil
+}
+
+func (n *SQL) GetType() string {
+	return sqlType
+}
+
+func (n *SQL) Fetch(ctx context.Context, db *gorm.DB) error {
+	getData := map[string]interface{}{}
+
+	query := db.WithContext(ctx).Model(&models.Settings{}).Select("data").Where("namespace = ?", "database").Where("name = ?", n.profile)
+	if result := query.First(&getData); result.Error != nil {
+		return fmt.Errorf("fetch database %s failed: %w", n.profile, result.Error)
+	}
+
+	dataInner, _ := getData["data"].(string)
+
+	databaseModel := models.Database{}
+	if err := json.Unmarshal([]byte(dataInner), &databaseModel); err != nil {
+		return fmt.Errorf("fetch database %s failed: %w", n.profile, err)
+	}
+
+	cfg, err := database.ParseSettings(databaseModel)
+	if err != nil {
+		return fmt.Errorf("database profile %s: %w", n.profile, err)
+	}
+
+	// profile can enforce read only for all nodes
+	n.readOnly = n.readOnly || cfg.ReadOnly
+
+	sqlDB, release, err := database.Acquire(n.profile, cfg)
+	if err != nil {
+		return err //nolint:wrapcheck // clear error
+	}
+
+	n.db = sqlDB
+	n.reg.AddCleanup(release)
+
+	n.fetched = true
+
+	return nil
+}
+
+func (n *SQL) IsFetched() bool {
+	return n.fetched
+}
+
+func (n *SQL) IsRespond() bool {
+	return false
+}
+
+func (n *SQL) Validate(_ context.Context) error {
+	if n.profile == "" {
+		return fmt.Errorf("sql profile is empty")
+	}
+
+	if strings.TrimSpace(n.query) == "" {
+		return fmt.Errorf("sql query is empty")
+	}
+
+	switch n.mode {
+	case database.ModeAuto, database.ModeQuery, database.ModeExec:
+	default:
+		return fmt.Errorf("sql mode %q not supported, use query or exec", n.mode)
+	}
+
+	return nil
+}
+
+func (n *SQL) Next(i int) []flow.Connection {
+	return n.outputs[i]
+}
+
+func (n *SQL) NextCount() int {
+	return len(n.outputs)
+}
+
+func (n *SQL) IsDisabled() bool {
+	return n.disabled
+}
+
+func (n *SQL) ActiveInput(_ string, tags map[string]struct{}) {
+	if !convert.IsTagsEnabled(n.tags, tags) {
+		n.disabled = true
+
+		return
+	}
+}
+
+func (n *SQL) Check() {
+	n.checked = true
+}
+
+func (n *SQL) IsChecked() bool {
+	return n.checked
+}
+
+func (n *SQL) NodeID() string {
+	return n.nodeID
+}
+
+func (n *SQL) Tags() []string {
+	return n.tags
+}
+
+func NewSQL(_ context.Context, reg *flow.NodesReg, data flow.NodeData, nodeID string) (flow.Noder, error) {
+	outputs := flow.PrepareOutputs(data.Outputs)
+
+	profile, _ := data.Data["profile"].(string)
+	query, _ := data.Data["query"].(string)
+	mode, _ := data.Data["mode"].(string)
+	paramsRaw, _ := data.Data["params"].(string)
+	timeout, _ := data.Data["timeout"].(string)
+
+	var params map[string]string
+	if err := yaml.Unmarshal([]byte(paramsRaw), &params); err != nil {
+		return nil, fmt.Errorf("sql params cannot unmarshal: %w", err)
+	}
+
+	tags := convert.GetList(data.Data["tags"])
+
+	return &SQL{
+		profile:     strings.TrimSpace(profile),
+		query:       query,
+		mode:        strings.ToLower(strings.TrimSpace(mode)),
+		params:      params,
+		transaction: convert.GetBoolean(data.Data["transaction"]),
+		readOnly:    convert.GetBoolean(data.Data["read_only"]),
+		timeout:     timeout,
+		reg:         reg,
+		outputs:     outputs,
+		nodeID:      nodeID,
+		tags:        tags,
+	}, nil
+}
+
+//nolint:gochecknoinits // moduler nodes
+func init() {
+	flow.NodeTypes[sqlType] = NewSQL
+}
@@ -0,0 +1,56 @@
+package nodes
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/rytsh/mugo/pkg/templatex"
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+func TestSQL_Run(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	reg := &registry.Registry{Template: templatex.New()}
+
+	n := &SQL{
+		profile: "orders",
+		query:   "SELECT status FROM orders WHERE id = @id",
+		db:      db,
+	}
+
+	mock.ExpectQuery(`SELECT status FROM orders WHERE id = \$1`).
+		WithArgs("42").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("paid"))
+
+	ret, err := n.Run(context.Background(), nil, reg, &EndpointRet{output: []byte(`{"id":"42"}`)}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ret.(*SQLRet); !reflect.DeepEqual(got.selection, []int{1}) || string(got.output) != `[{"status":"paid"}]` {
+		t.Errorf("SQL.Run() selection = %v, output = %s", got.selection, got.output)
+	}
+
+	mock.ExpectQuery(`SELECT status FROM orders WHERE id = \$1`).
+		WithArgs("42").
+		WillReturnError(errors.New("relation does not exist"))
+
+	ret, err = n.Run(context.Background(), nil, reg, &EndpointRet{output: []byte(`{"id":"42"}`)}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ret.(*SQLRet); !reflect.DeepEqual(got.selection, []int{0}) ||
+		string(got.output) != `{"error":"statement 1: relation does not exist","profile":"orders"}` {
+		t.Errorf("SQL.Run() selection = %v, output = %s", got.selection, got.output)
+	}
+}
@@ -111,9 +111,17 @@ func validateFetch(ctx context.Context, current string, outputs []Connection, re
 }
 
 func GoAndRun(ctx context.Context, wg *sync.WaitGroup, reg *NodesReg, firstValue []byte) {
-	defer wg.Done()
+	goAndRun(ctx, wg, reg, func() {
+		for _, start := range reg.GetStarts() {
+			branch(ctx, []Connection{start.Connection}, reg, &nodeRetOutput{firstValue})
+		}
+	})
+}
 
-	starts := reg.GetStarts()
+// goAndRun calls dispatch to start nodes and waits all of them.
+func goAndRun(ctx context.Context, wg *sync.WaitGroup, reg *NodesReg, dispatch func()) {
+	defer wg.Done()
+	defer close(reg.done)
 
 	// stuct count check
 
@@ -143,9 +151,7 @@ func GoAndRun(ctx context.Context, wg *sync.WaitGroup, reg *NodesReg, firstValue
 	}()
 
 	// change waitgroup to check all job is finished
-	for _, start := range starts {
-		branch(ctx, []Connection{start.Connection}, reg, &nodeRetOutput{firstValue})
-	}
+	dispatch()
 
 	// wait to finish that control flow
 	reg.wgx.Wait()
@@ -153,6 +159,12 @@ func GoAndRun(ctx context.Context, wg *sync.WaitGroup, reg *NodesReg, firstValue
 	// cancel stuck check
 	stuckCheckCtxCancel()
 
+	reg.runCleanup()
+
+	if reg.durable != nil {
+		reg.durable.finish(ctx, reg.errors)
+	}
+
 	if reg.respondChan != nil {
 		if reg.respondChanActive {
 			// send error to channel
@@ -183,23 +195,45 @@ func GoAndRun(ctx context.Context, wg *sync.WaitGroup, reg *NodesReg, firstValue
 	log.Ctx(ctx).Info().Msgf("completed control flow")
 }
 
+// Branch sends value to the next connections while the node still running.
+func (r *NodesReg) Branch(ctx context.Context, nexts []Connection, value NodeRet) {
+	branch(ctx, nexts, r, value)
+}
+
 // branch values already designed to same length of nexts.
 func branch(ctx context.Context, nexts []Connection, reg *NodesReg, value NodeRet) {
 	for _, next := range nexts {
 		// going goroutine to prevent too much recursive call
+		var taskID string
+
+		if reg.durable != nil {
+			var err error
+			if taskID, err = reg.durable.addTask(ctx, next, value); err != nil {
+				log.Ctx(ctx).Warn().Err(err).Msg("checkpoint cannot save")
+			}
+		}
+
 		reg.wgx.Add(1)
 		reg.UpdateStuck(CountTotalIncrease, false)
 
-		go branchRun(ctx, next, reg, value)
+		go branchRun(ctx, next, reg, value, taskID)
 	}
 }
 
-func branchRun(ctx context.Context, start Connection, reg *NodesReg, value NodeRet) {
+func branchRun(ctx context.Context, start Connection, reg *NodesReg, value NodeRet, taskID string) {
+	var runErr error
+
 	defer func() {
 		// check panic
 		if r := recover(); r != nil {
 			log.Ctx(ctx).Error().Msgf("panic: %v\n%v", r, string(debug.Stack()))
 			reg.AddError(fmt.Errorf("panic: %s cannot run: %v\n%v", start.Node, r, string(debug.Stack())))
+
+			runErr = fmt.Errorf("panic: %v", r)
+		}
+
+		if reg.durable != nil && taskID != "" && ctx.Err() == nil {
+			reg.durable.afterRun(ctx, taskID, start.Node, runErr)
 		}
 
 		reg.UpdateStuck(CountTotalDecrease, true)
@@ -225,12 +259,18 @@ func branchRun(ctx context.Context, start Connection, reg *NodesReg, value NodeR
 		return
 	}
 
+	if taskID != "" {
+		ctx = context.WithValue(ctx, CtxTaskID, taskID)
+	}
+
 	// add nodeID to log context
 	ctx = log.Ctx(ctx).With().Str("nodeID", node.NodeID()).Logger().WithContext(ctx)
 	// log debug
 	log.Ctx(ctx).Debug().Msgf("running [%s]", node.GetType())
 
 	outputDatas, err := node.Run(ctx, &reg.wgx, reg.appStore, value, start.Output)
+	runErr = err
+
 	if err != nil {
 		if errors.Is(err, ErrStopGoroutine) {
 			return
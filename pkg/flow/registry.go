@@ -45,6 +45,10 @@ type NodesReg struct {
 	stuckCtxCancels []context.CancelFunc
 	cleanup         []func()
 	stuckChan       chan bool
+	// durable run checkpoints, nil if not enabled
+	durable *durableRun
+	// closed when all nodes finished
+	done chan struct{}
 }
 
 func NewNodesReg(controlName, startName, method string, appStore *registry.Registry) *NodesReg {
@@ -54,10 +58,22 @@ func NewNodesReg(controlName, startName, method string, appStore *registry.Regis
 		method:      method,
 		reg:         make(map[string]Noder),
 		appStore:    appStore,
+		done:        make(chan struct{}),
 	}
 }
 
+func (r *NodesReg) ControlName() string {
+	return r.controlName
+}
+
+func (r *NodesReg) StartName() string {
+	return r.startName
+}
+
 func (r *NodesReg) GetChan() <-chan Respond {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
 	if r.respondChanActive {
 		return r.respondChan
 	}
@@ -65,6 +81,14 @@ func (r *NodesReg) GetChan() <-chan Respond {
 	return nil
 }
 
+// RespondChan returns respond channel even if respond already sent, nil if there is no respond node.
+func (r *NodesReg) RespondChan() <-chan Respond {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.respondChan
+}
+
 // CancelStucks cancel all stuck context of nodes and clear stuck context list.
 func (r *NodesReg) CancelStucks() {
 	r.mutex.Lock()
@@ -109,6 +133,18 @@ func (r *NodesReg) AddCleanup(v func()) {
 	r.cleanup = append(r.cleanup, v)
 }
 
+// runCleanup calls cleanup functions once, nodes add them to close their connections.
+func (r *NodesReg) runCleanup() {
+	r.mutex.Lock()
+	cleanup := r.cleanup
+	r.cleanup = nil
+	r.mutex.Unlock()
+
+	for _, v := range cleanup {
+		v()
+	}
+}
+
 func (r *NodesReg) UpdateStuck(typeCount CountStucker, trigger bool) {
 	r.mutexCount.Lock()
 	defer r.mutexCount.Unlock()
@@ -145,6 +181,19 @@ func (r *NodesReg) AddError(err error) {
 	r.errors = append(r.errors, err)
 }
 
+// Errors returns copy of the collected node errors.
+func (r *NodesReg) Errors() []error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return append([]error(nil), r.errors...)
+}
+
+// Done returns a channel closed when the control flow completed.
+func (r *NodesReg) Done() <-chan struct{} {
+	return r.done
+}
+
 func (r *NodesReg) Get(number string) (Noder, bool) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
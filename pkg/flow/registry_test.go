@@ -0,0 +1,56 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/worldline-go/chore/pkg/registry"
+)
+
+func TestNodesReg_Errors(t *testing.T) {
+	reg := NewNodesReg("test", "test", "POST", &registry.Registry{})
+
+	if errs := reg.Errors(); len(errs) != 0 {
+		t.Fatalf("Errors() = %v, want empty", errs)
+	}
+
+	errFirst := errors.New("first")
+	reg.AddError(errFirst)
+
+	errs := reg.Errors()
+	if len(errs) != 1 || !errors.Is(errs[0], errFirst) {
+		t.Fatalf("Errors() = %v, want [%v]", errs, errFirst)
+	}
+
+	// returned slice is a copy
+	errs[0] = errors.New("changed")
+	if got := reg.Errors(); !errors.Is(got[0], errFirst) {
+		t.Errorf("Errors() = %v, changed by caller", got)
+	}
+}
+
+func TestNodesReg_Done(t *testing.T) {
+	reg := NewNodesReg("test", "test", "POST", &registry.Registry{})
+
+	select {
+	case <-reg.Done():
+		t.Fatal("Done() closed before run")
+	default:
+	}
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+
+	go GoAndRun(context.Background(), wg, reg, nil)
+
+	select {
+	case <-reg.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Done() not closed after run")
+	}
+
+	wg.Wait()
+}
@@ -27,8 +27,22 @@ func StartFlow(
 	endPoint = strings.TrimSpace(endPoint)
 	method = strings.TrimSpace(method)
 
+	chain, err := addCall(GetCallChain(ctx), Call{Control: controlName, Endpoint: endPoint}, Calls)
+	if err != nil {
+		return nil, err
+	}
+
+	// node values of the caller control not belong to this run
+	ctx = context.WithValue(ctx, CtxTaskID, nil)
+	ctx = context.WithValue(ctx, CtxResumedAt, nil)
+
 	// set new logger for reg and set it in ctx
-	ctx = log.Ctx(ctx).With().Str("control", controlName).Str("endpoint", endPoint).Logger().WithContext(ctx)
+	logCtx := log.Ctx(ctx).With().Str("control", controlName).Str("endpoint", endPoint)
+	if len(chain) > 1 {
+		logCtx = logCtx.Str("chain", chain.String())
+	}
+
+	ctx = logCtx.Logger().WithContext(ctx)
 
 	nodesReg, err := DataToNode(ctx, controlName, endPoint, method, nodesData, appStore)
 	if err != nil {
@@ -36,9 +50,34 @@ func StartFlow(
 	}
 
 	if err := VisitAndFetch(ctx, nodesReg); err != nil {
+		nodesReg.runCleanup()
+
 		return nil, err
 	}
 
+	if Durable.Enabled {
+		durable, err := newDurableRun(ctx, appStore.DB, controlName, endPoint, method, chain.ParentRunID())
+		if err != nil {
+			nodesReg.runCleanup()
+
+			return nil, err
+		}
+
+		nodesReg.durable = durable
+		chain[len(chain)-1].RunID = durable.runID.String()
+
+		logCtx := log.Ctx(ctx).With().Str("run", durable.runID.String())
+		if parentRunID := chain.ParentRunID(); parentRunID != "" {
+			logCtx = logCtx.Str("parent_run", parentRunID)
+		}
+
+		ctx = logCtx.Logger().WithContext(ctx)
+
+		durable.heartbeat(ctx, wg)
+	}
+
+	ctx = context.WithValue(ctx, CtxCallChain, chain)
+
 	wg.Add(1)
 	go GoAndRun(ctx, wg, nodesReg, value)
 
@@ -0,0 +1,325 @@
+// Package grpccall calls unary gRPC methods with JSON payloads.
+//
+// Method descriptors come from server reflection or from a FileDescriptorSet.
+package grpccall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/worldline-go/chore/pkg/request"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Config of the client.
+type Config struct {
+	// Target is address of the server like localhost:9090.
+	Target string
+	// Plaintext disables TLS.
+	Plaintext  bool
+	SkipVerify bool
+	TLS        *request.TLSConfig
+	// Descriptors is serialized FileDescriptorSet, empty uses server reflection.
+	Descriptors []byte
+}
+
+// Response of the unary call, Body is JSON encoded response or status.
+type Response struct {
+	Code    uint32
+	Message string
+	Body    []byte
+	Header  metadata.MD
+}
+
+// Client calls methods of one target.
+type Client struct {
+	conn *grpc.ClientConn
+	// files resolved with descriptors or reflection
+	files      *protoregistry.Files
+	reflection bool
+	// reflected files to rebuild files with new services
+	reflected []*descriptorpb.FileDescriptorProto
+	mutex     sync.Mutex
+}
+
+// NewClient returns client, connection is established on first call.
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.Target == "" {
+		return nil, errors.New("grpc target is empty")
+	}
+
+	creds := insecure.NewCredentials()
+
+	if !cfg.Plaintext {
+		profile := request.TLSConfig{}
+		if cfg.TLS != nil {
+			profile = *cfg.TLS
+		}
+
+		tlsConfig, err := profile.Build()
+		if err != nil {
+			return nil, fmt.Errorf("grpc tls: %w", err)
+		}
+
+		tlsConfig.InsecureSkipVerify = cfg.SkipVerify //nolint:gosec // user option
+
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	c := &Client{reflection: len(cfg.Descriptors) == 0}
+
+	if !c.reflection {
+		files, err := ParseDescriptors(cfg.Descriptors)
+		if err != nil {
+			return nil, err
+		}
+
+		c.files = files
+	}
+
+	conn, err := grpc.Dial(cfg.Target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("grpc dial %s: %w", cfg.Target, err)
+	}
+
+	c.conn = conn
+
+	return c, nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close() //nolint:wrapcheck // no need
+}
+
+// ParseDescriptors returns files of the serialized FileDescriptorSet.
+func ParseDescriptors(data []byte) (*protoregistry.Files, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("file descriptor set cannot parse: %w", err)
+	}
+
+	if len(set.GetFile()) == 0 {
+		return nil, errors.New("file descriptor set is empty")
+	}
+
+	files, err := buildFiles(set.GetFile())
+	if err != nil {
+		return nil, fmt.Errorf("file descriptor set: %w", err)
+	}
+
+	return files, nil
+}
+
+// buildFiles registers files with dependency order, missing dependencies resolved from well known types.
+func buildFiles(fds []*descriptorpb.FileDescriptorProto) (*protoregistry.Files, error) {
+	byName := make(map[string]*descriptorpb.FileDescriptorProto, len(fds))
+	for _, fd := range fds {
+		byName[fd.GetName()] = fd
+	}
+
+	files := &protoregistry.Files{}
+	resolver := chainResolver{files}
+
+	var register func(name string) error
+
+	register = func(name string) error {
+		if _, err := files.FindFileByPath(name); err == nil {
+			return nil
+		}
+
+		fd, ok := byName[name]
+		if !ok {
+			if _, err := protoregistry.GlobalFiles.FindFileByPath(name); err == nil {
+				return nil
+			}
+
+			return fmt.Errorf("file %s not found", name)
+		}
+
+		for _, dep := range fd.GetDependency() {
+			if err := register(dep); err != nil {
+				return err
+			}
+		}
+
+		file, err := protodesc.NewFile(fd, resolver)
+		if err != nil {
+			return fmt.Errorf("file %s: %w", name, err)
+		}
+
+		return files.RegisterFile(file) //nolint:wrapcheck // clear error
+	}
+
+	for _, fd := range fds {
+		if err := register(fd.GetName()); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// chainResolver looks own files then global files.
+type chainResolver struct {
+	files *protoregistry.Files
+}
+
+func (r chainResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := r.files.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+
+	return protoregistry.GlobalFiles.FindFileByPath(path) //nolint:wrapcheck // resolver
+}
+
+func (r chainResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if d, err := r.files.FindDescriptorByName(name); err == nil {
+		return d, nil
+	}
+
+	return protoregistry.GlobalFiles.FindDescriptorByName(name) //nolint:wrapcheck // resolver
+}
+
+// splitMethod returns service and method of "package.Service/Method" or "package.Service.Method".
+func splitMethod(method string) (string, string, error) {
+	method = strings.TrimPrefix(strings.TrimSpace(method), "/")
+
+	i := strings.LastIndexAny(method, "/.")
+	if i <= 0 || i == len(method)-1 {
+		return "", "", fmt.Errorf("grpc method %q should be package.Service/Method", method)
+	}
+
+	return method[:i], method[i+1:], nil
+}
+
+// method returns descriptor of the method, files come from reflection when not set.
+func (c *Client) method(ctx context.Context, fullMethod string) (protoreflect.MethodDescriptor, error) {
+	serviceName, methodName, err := splitMethod(fullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.reflection && (c.files == nil || !hasDescriptor(c.files, serviceName)) {
+		fds, err := reflectFiles(ctx, c.conn, serviceName)
+		if err != nil {
+			return nil, err
+		}
+
+		files, err := buildFiles(append(c.reflected, fds...))
+		if err != nil {
+			return nil, fmt.Errorf("grpc reflection: %w", err)
+		}
+
+		c.reflected = append(c.reflected, fds...)
+		c.files = files
+	}
+
+	d, err := c.files.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil, fmt.Errorf("grpc service %s not found: %w", serviceName, err)
+	}
+
+	service, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("grpc %s is not a service", serviceName)
+	}
+
+	method := service.Methods().ByName(protoreflect.Name(methodName))
+	if method == nil {
+		return nil, fmt.Errorf("grpc method %s not found in %s", methodName, serviceName)
+	}
+
+	if method.IsStreamingClient() || method.IsStreamingServer() {
+		return nil, fmt.Errorf("grpc method %s is streaming, only unary supported", methodName)
+	}
+
+	return method, nil
+}
+
+func hasDescriptor(files *protoregistry.Files, name string) bool {
+	_, err := files.FindDescriptorByName(protoreflect.FullName(name))
+
+	return err == nil
+}
+
+// Call invokes unary method with JSON payload, non OK status returned in response.
+func (c *Client) Call(ctx context.Context, fullMethod string, md map[string]string, payload []byte) (*Response, error) {
+	method, err := c.method(ctx, fullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := dynamicpb.NewTypes(c.files)
+
+	in := dynamicpb.NewMessage(method.Input())
+	if len(strings.TrimSpace(string(payload))) > 0 {
+		if err := (protojson.UnmarshalOptions{Resolver: resolver}).Unmarshal(payload, in); err != nil {
+			return nil, fmt.Errorf("grpc request cannot convert to %s: %w", method.Input().FullName(), err)
+		}
+	}
+
+	out := dynamicpb.NewMessage(method.Output())
+
+	if len(md) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(md))
+	}
+
+	var header, trailer metadata.MD
+
+	invokeErr := c.conn.Invoke(
+		ctx,
+		"/"+string(method.Parent().FullName())+"/"+string(method.Name()),
+		in, out,
+		grpc.Header(&header), grpc.Trailer(&trailer),
+	)
+
+	response := &Response{Header: metadata.Join(header, trailer)}
+
+	if invokeErr != nil {
+		st := status.Convert(invokeErr)
+
+		response.Code = uint32(st.Code())
+		response.Message = st.Message()
+
+		body, err := (protojson.MarshalOptions{Resolver: resolver}).Marshal(st.Proto())
+		if err != nil {
+			// details with unknown types
+			stProto := st.Proto()
+			stProto.Details = nil
+
+			body, _ = protojson.Marshal(stProto)
+		}
+
+		response.Body = body
+
+		return response, nil
+	}
+
+	body, err := (protojson.MarshalOptions{Resolver: resolver, EmitUnpopulated: true}).Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("grpc response cannot convert: %w", err)
+	}
+
+	response.Body = body
+
+	return response, nil
+}
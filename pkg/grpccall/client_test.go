@@ -0,0 +1,93 @@
+package grpccall
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestClient_Call(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// echo x-tenant metadata back in header
+	srv := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs("x-tenant", strings.Join(md.Get("x-tenant"), ",")))
+
+		return handler(ctx, req)
+	}))
+
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	reflection.Register(srv)
+
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	descriptors, err := proto.Marshal(&descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{protodesc.ToFileDescriptorProto(healthpb.File_grpc_health_v1_health_proto)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, cfg := range map[string]Config{
+		"reflection":  {Target: lis.Addr().String(), Plaintext: true},
+		"descriptors": {Target: lis.Addr().String(), Plaintext: true, Descriptors: descriptors},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewClient(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+
+			resp, err := c.Call(context.Background(), "grpc.health.v1.Health/Check", map[string]string{"x-tenant": "a"}, []byte(`{"service": ""}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.Code != uint32(codes.OK) || string(resp.Body) != `{"status":"SERVING"}` {
+				t.Errorf("Call() = %d %s", resp.Code, resp.Body)
+			}
+
+			if got := resp.Header.Get("x-tenant"); len(got) != 1 || got[0] != "a" {
+				t.Errorf("header x-tenant = %v", got)
+			}
+
+			resp, err = c.Call(context.Background(), "/grpc.health.v1.Health/Check", nil, []byte(`{"service": "unknown"}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.Code != uint32(codes.NotFound) || !strings.Contains(string(resp.Body), `"code":5`) {
+				t.Errorf("Call() unknown service = %d %s", resp.Code, resp.Body)
+			}
+
+			if _, err := c.Call(context.Background(), "grpc.health.v1.Health/Watch", nil, nil); err == nil {
+				t.Errorf("Call() streaming method should fail")
+			}
+
+			if _, err := c.Call(context.Background(), "grpc.health.v1.Health/Check", nil, []byte(`{"unknown": 1}`)); err == nil {
+				t.Errorf("Call() unknown field should fail")
+			}
+		})
+	}
+
+	if _, err := NewClient(Config{Target: "localhost:1", Descriptors: []byte("x")}); err == nil {
+		t.Errorf("NewClient() invalid descriptors should fail")
+	}
+}
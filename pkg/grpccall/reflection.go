@@ -0,0 +1,103 @@
+package grpccall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// reflectFiles returns file of the service and its dependencies with server reflection.
+// v1alpha is used, it is served by old and new servers.
+func reflectFiles(ctx context.Context, conn *grpc.ClientConn, service string) ([]*descriptorpb.FileDescriptorProto, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("grpc reflection: %w", err)
+	}
+
+	defer stream.CloseSend() //nolint:errcheck // closing
+
+	send := func(req *rpb.ServerReflectionRequest) ([]*descriptorpb.FileDescriptorProto, error) {
+		if err := stream.Send(req); err != nil {
+			return nil, fmt.Errorf("grpc reflection send: %w", err)
+		}
+
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, fmt.Errorf("grpc reflection receive: %w", err)
+		}
+
+		if errResp := resp.GetErrorResponse(); errResp != nil {
+			return nil, fmt.Errorf("grpc reflection: %s", errResp.GetErrorMessage())
+		}
+
+		fdResp := resp.GetFileDescriptorResponse()
+		if fdResp == nil {
+			return nil, errors.New("grpc reflection: unexpected response")
+		}
+
+		fds := make([]*descriptorpb.FileDescriptorProto, 0, len(fdResp.GetFileDescriptorProto()))
+
+		for _, b := range fdResp.GetFileDescriptorProto() {
+			fd := &descriptorpb.FileDescriptorProto{}
+			if err := proto.Unmarshal(b, fd); err != nil {
+				return nil, fmt.Errorf("grpc reflection file cannot parse: %w", err)
+			}
+
+			fds = append(fds, fd)
+		}
+
+		return fds, nil
+	}
+
+	fds, err := send(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(fds))
+	for _, fd := range fds {
+		seen[fd.GetName()] = struct{}{}
+	}
+
+	// servers may skip dependencies, ask missing ones
+	for i := 0; i < len(fds); i++ {
+		for _, dep := range fds[i].GetDependency() {
+			if _, ok := seen[dep]; ok {
+				continue
+			}
+
+			seen[dep] = struct{}{}
+
+			if _, err := protoregistry.GlobalFiles.FindFileByPath(dep); err == nil {
+				continue
+			}
+
+			depFds, err := send(&rpb.ServerReflectionRequest{
+				MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{FileByFilename: dep},
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, fd := range depFds {
+				if _, ok := seen[fd.GetName()]; !ok || fd.GetName() == dep {
+					seen[fd.GetName()] = struct{}{}
+					fds = append(fds, fd)
+				}
+			}
+		}
+	}
+
+	return fds, nil
+}
@@ -0,0 +1,230 @@
+// Package kv is a namespaced key-value store in database shared by the flows.
+package kv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/worldline-go/chore/pkg/models"
+)
+
+var (
+	ErrNotFound   = errors.New("key not found")
+	ErrNotInteger = errors.New("value is not an integer")
+)
+
+var keyColumns = []clause.Column{{Name: "namespace"}, {Name: "key"}}
+
+// Alive filters out expired keys, expired keys stay in table until clean.
+func Alive(now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("expires_at IS NULL OR expires_at > ?", now)
+	}
+}
+
+func expiresAt(now time.Time, ttl time.Duration) *time.Time {
+	if ttl <= 0 {
+		return nil
+	}
+
+	v := now.Add(ttl)
+
+	return &v
+}
+
+func expired(record *models.KV, now time.Time) bool {
+	return record.ExpiresAt != nil && !record.ExpiresAt.After(now)
+}
+
+func byKey(db *gorm.DB, namespace, key string) *gorm.DB {
+	return db.Where("namespace = ? AND key = ?", namespace, key)
+}
+
+// Get returns the key, ErrNotFound returned if key not exist or expired.
+func Get(ctx context.Context, db *gorm.DB, namespace, key string) (*models.KV, error) {
+	record := models.KV{}
+
+	result := byKey(db.WithContext(ctx), namespace, key).Scopes(Alive(time.Now())).Limit(1).Find(&record)
+	if result.Error != nil {
+		return nil, fmt.Errorf("kv get: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, ErrNotFound
+	}
+
+	return &record, nil
+}
+
+// Set creates or replaces the key, zero ttl keeps the key until deleted.
+func Set(ctx context.Context, db *gorm.DB, namespace, key, value string, ttl time.Duration) error {
+	record := models.KV{
+		Namespace: namespace,
+		Key:       key,
+		Value:     value,
+		ExpiresAt: expiresAt(time.Now(), ttl),
+	}
+
+	result := db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   keyColumns,
+		DoUpdates: clause.AssignmentColumns([]string{"value", "expires_at", "updated_at"}),
+	}).Create(&record)
+	if result.Error != nil {
+		return fmt.Errorf("kv set: %w", result.Error)
+	}
+
+	return nil
+}
+
+// Delete removes the key and returns true if a not expired key deleted.
+func Delete(ctx context.Context, db *gorm.DB, namespace, key string) (bool, error) {
+	records := []models.KV{}
+
+	result := byKey(db.WithContext(ctx), namespace, key).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "expires_at"}}}).Delete(&records)
+	if result.Error != nil {
+		return false, fmt.Errorf("kv delete: %w", result.Error)
+	}
+
+	now := time.Now()
+	for i := range records {
+		if !expired(&records[i], now) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Increment adds delta to the integer value of the key in a transaction and returns the new value.
+// Missing or expired key starts from zero, ttl is only set when counter starts so window is not sliding.
+func Increment(ctx context.Context, db *gorm.DB, namespace, key string, delta int64, ttl time.Duration) (int64, error) {
+	var value int64
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// create first, so concurrent increments wait on the same row lock
+		if err := tx.Clauses(clause.OnConflict{Columns: keyColumns, DoNothing: true}).Create(&models.KV{
+			Namespace: namespace,
+			Key:       key,
+			Value:     "0",
+			ExpiresAt: expiresAt(now, ttl),
+		}).Error; err != nil {
+			return err //nolint:wrapcheck // wrapped below
+		}
+
+		record := models.KV{}
+		if err := byKey(tx, namespace, key).Clauses(clause.Locking{Strength: "UPDATE"}).Take(&record).Error; err != nil {
+			return err //nolint:wrapcheck // wrapped below
+		}
+
+		if expired(&record, now) {
+			record.Value = "0"
+			record.ExpiresAt = expiresAt(now, ttl)
+		}
+
+		current, err := strconv.ParseInt(record.Value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: %q", ErrNotInteger, record.Value)
+		}
+
+		value = current + delta
+
+		return byKey(tx.Model(&models.KV{}), namespace, key).Updates(map[string]interface{}{ //nolint:wrapcheck // wrapped below
+			"value":      strconv.FormatInt(value, 10),
+			"expires_at": record.ExpiresAt,
+		}).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("kv increment: %w", err)
+	}
+
+	return value, nil
+}
+
+// CompareAndSwap sets the value only if current value is equal to old.
+// Nil old means key should not exist, so it can be used as set if not exists.
+func CompareAndSwap(ctx context.Context, db *gorm.DB, namespace, key string, old *string, value string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	db = db.WithContext(ctx)
+
+	if old == nil {
+		result := db.Clauses(clause.OnConflict{Columns: keyColumns, DoNothing: true}).Create(&models.KV{
+			Namespace: namespace,
+			Key:       key,
+			Value:     value,
+			ExpiresAt: expiresAt(now, ttl),
+		})
+		if result.Error != nil {
+			return false, fmt.Errorf("kv compare and swap: %w", result.Error)
+		}
+
+		if result.RowsAffected > 0 {
+			return true, nil
+		}
+	}
+
+	query := byKey(db.Model(&models.KV{}), namespace, key)
+	if old == nil {
+		// existing key can be replaced only if expired
+		query = query.Where("expires_at <= ?", now)
+	} else {
+		query = query.Where("value = ?", *old).Scopes(Alive(now))
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"value":      value,
+		"expires_at": expiresAt(now, ttl),
+	})
+	if result.Error != nil {
+		return false, fmt.Errorf("kv compare and swap: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Clean removes expired keys.
+func Clean(ctx context.Context, db *gorm.DB) (int64, error) {
+	result := db.WithContext(ctx).Where("expires_at <= ?", time.Now()).Delete(&models.KV{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("kv clean: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// StartClean removes expired keys periodically.
+func StartClean(ctx context.Context, wg *sync.WaitGroup, db *gorm.DB, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := Clean(ctx, db); err != nil {
+					log.Warn().Err(err).Msg("kv clean failed")
+				}
+			}
+		}
+	}()
+}
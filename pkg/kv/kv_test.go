@@ -0,0 +1,143 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func mockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db, mock
+}
+
+func TestGet(t *testing.T) {
+	db, mock := mockDB(t)
+
+	mock.ExpectQuery(`SELECT \* FROM "kvs" WHERE \(namespace = \$1 AND key = \$2\) AND \(expires_at IS NULL OR expires_at > \$3\) LIMIT 1`).
+		WithArgs("orders", "last", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"namespace", "key", "value"}).AddRow("orders", "last", "42"))
+
+	record, err := Get(context.Background(), db, "orders", "last")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if record.Value != "42" {
+		t.Errorf("Get() value = %q", record.Value)
+	}
+
+	mock.ExpectQuery(`SELECT \* FROM "kvs"`).
+		WillReturnRows(sqlmock.NewRows([]string{"namespace", "key", "value"}))
+
+	if _, err := Get(context.Background(), db, "orders", "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() error = %v, want ErrNotFound", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestIncrement(t *testing.T) {
+	db, mock := mockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "kvs" .* ON CONFLICT \("namespace","key"\) DO NOTHING`).
+		WithArgs("limits", "user-1", "0", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT \* FROM "kvs" WHERE namespace = \$1 AND key = \$2 LIMIT 1 FOR UPDATE`).
+		WithArgs("limits", "user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"namespace", "key", "value"}).AddRow("limits", "user-1", "4"))
+	mock.ExpectExec(`UPDATE "kvs" SET "expires_at"=\$1,"value"=\$2,"updated_at"=\$3 WHERE namespace = \$4 AND key = \$5`).
+		WithArgs(nil, "6", sqlmock.AnyArg(), "limits", "user-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	value, err := Increment(context.Background(), db, "limits", "user-1", 2, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value != 6 {
+		t.Errorf("Increment() = %d, want 6", value)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "kvs"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT \* FROM "kvs"`).
+		WillReturnRows(sqlmock.NewRows([]string{"namespace", "key", "value"}).AddRow("limits", "user-1", "abc"))
+	mock.ExpectRollback()
+
+	if _, err := Increment(context.Background(), db, "limits", "user-1", 1, 0); !errors.Is(err, ErrNotInteger) {
+		t.Errorf("Increment() error = %v, want ErrNotInteger", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	db, mock := mockDB(t)
+
+	old := "pending"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "kvs" SET .* WHERE \(namespace = \$\d AND key = \$\d\) AND value = \$\d AND \(expires_at IS NULL OR expires_at > \$\d\)`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	swapped, err := CompareAndSwap(context.Background(), db, "orders", "42", &old, "paid", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !swapped {
+		t.Error("CompareAndSwap() = false, want true")
+	}
+
+	// key exists and not expired
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "kvs" .* ON CONFLICT \("namespace","key"\) DO NOTHING`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "kvs" SET .* WHERE \(namespace = \$\d AND key = \$\d\) AND expires_at <= \$\d`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	swapped, err = CompareAndSwap(context.Background(), db, "orders", "42", nil, "seen", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if swapped {
+		t.Error("CompareAndSwap() = true, want false")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,213 @@
+// Package mailbox reads messages of an IMAP mailbox.
+package mailbox
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+)
+
+// DefaultTimeout of the connection and commands.
+var DefaultTimeout = time.Minute
+
+// TLS modes of the IMAP connection.
+const (
+	// TLSModeAuto uses SSL on port 993 and STARTTLS when server supports it.
+	TLSModeAuto = ""
+	// TLSModeSSL connects with implicit TLS.
+	TLSModeSSL = "ssl"
+	// TLSModeStartTLS requires STARTTLS.
+	TLSModeStartTLS = "starttls"
+	// TLSModeNone connects without TLS.
+	TLSModeNone = "none"
+)
+
+// Config of the IMAP connection.
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// TLSMode is one of the TLSMode values.
+	TLSMode    string
+	SkipVerify bool
+	// Timeout of the connection and commands, default is DefaultTimeout.
+	Timeout time.Duration
+}
+
+// Message is a mail with its UID in the selected mailbox.
+type Message struct {
+	UID  uint32
+	Mail Mail
+}
+
+// Client is a logged in IMAP connection.
+type Client struct {
+	c *client.Client
+}
+
+// Dial connects and logs in.
+func Dial(cfg Config) (*Client, error) {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	dialer := &net.Dialer{Timeout: timeout}
+	tlsConfig := &tls.Config{ServerName: cfg.Host, InsecureSkipVerify: cfg.SkipVerify} //nolint:gosec // user option
+
+	mode := cfg.TLSMode
+	if mode == TLSModeAuto && cfg.Port == 993 { //nolint:gomnd // imaps port
+		mode = TLSModeSSL
+	}
+
+	var (
+		c   *client.Client
+		err error
+	)
+
+	switch mode {
+	case TLSModeSSL:
+		c, err = client.DialWithDialerTLS(dialer, addr, tlsConfig)
+	case TLSModeAuto, TLSModeStartTLS, TLSModeNone:
+		c, err = client.DialWithDialer(dialer, addr)
+	default:
+		return nil, fmt.Errorf("imap tls mode %q not supported", cfg.TLSMode)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("imap cannot connect %s: %w", addr, err)
+	}
+
+	c.Timeout = timeout
+
+	if mode == TLSModeAuto || mode == TLSModeStartTLS {
+		supported, err := c.SupportStartTLS()
+		if err != nil {
+			_ = c.Logout()
+
+			return nil, fmt.Errorf("imap capability: %w", err)
+		}
+
+		switch {
+		case supported:
+			if err := c.StartTLS(tlsConfig); err != nil {
+				_ = c.Logout()
+
+				return nil, fmt.Errorf("imap starttls: %w", err)
+			}
+		case mode == TLSModeStartTLS:
+			_ = c.Logout()
+
+			return nil, errors.New("imap server not support starttls")
+		}
+	}
+
+	if err := c.Login(cfg.Username, cfg.Password); err != nil {
+		_ = c.Logout()
+
+		return nil, fmt.Errorf("imap login: %w", err)
+	}
+
+	return &Client{c: c}, nil
+}
+
+// Close logs out.
+func (c *Client) Close() error {
+	return c.c.Logout() //nolint:wrapcheck // clear error
+}
+
+// Unseen selects mailbox and returns oldest unseen messages, messages are not marked as seen.
+func (c *Client) Unseen(mailbox string, limit int) ([]Message, error) {
+	if _, err := c.c.Select(mailbox, false); err != nil {
+		return nil, fmt.Errorf("imap select %s: %w", mailbox, err)
+	}
+
+	criteria := imap.NewSearchCriteria()
+	criteria.WithoutFlags = []string{imap.SeenFlag, imap.DeletedFlag}
+
+	uids, err := c.c.UidSearch(criteria)
+	if err != nil {
+		return nil, fmt.Errorf("imap search: %w", err)
+	}
+
+	if len(uids) == 0 {
+		return nil, nil
+	}
+
+	if limit > 0 && len(uids) > limit {
+		uids = uids[:limit]
+	}
+
+	seqSet := new(imap.SeqSet)
+	seqSet.AddNum(uids...)
+
+	section := &imap.BodySectionName{Peek: true}
+	items := []imap.FetchItem{imap.FetchUid, section.FetchItem()}
+
+	ch := make(chan *imap.Message, len(uids))
+	done := make(chan error, 1)
+
+	go func() {
+		done <- c.c.UidFetch(seqSet, items, ch)
+	}()
+
+	messages := make([]Message, 0, len(uids))
+
+	var parseErr error
+
+	for msg := range ch {
+		body := msg.GetBody(section)
+		if body == nil {
+			continue
+		}
+
+		m, err := Parse(body)
+		if err != nil && parseErr == nil {
+			parseErr = fmt.Errorf("imap message %d: %w", msg.Uid, err)
+		}
+
+		messages = append(messages, Message{UID: msg.Uid, Mail: m})
+	}
+
+	if err := <-done; err != nil {
+		return nil, fmt.Errorf("imap fetch: %w", err)
+	}
+
+	return messages, parseErr
+}
+
+// MarkSeen adds seen flag to the message.
+func (c *Client) MarkSeen(uid uint32) error {
+	seqSet := new(imap.SeqSet)
+	seqSet.AddNum(uid)
+
+	if err := c.c.UidStore(seqSet, imap.FormatFlagsOp(imap.AddFlags, true), []interface{}{imap.SeenFlag}, nil); err != nil {
+		return fmt.Errorf("imap mark seen %d: %w", uid, err)
+	}
+
+	return nil
+}
+
+// Move marks message seen and moves it to the folder.
+func (c *Client) Move(uid uint32, folder string) error {
+	if err := c.MarkSeen(uid); err != nil {
+		return err
+	}
+
+	seqSet := new(imap.SeqSet)
+	seqSet.AddNum(uid)
+
+	if err := c.c.UidMove(seqSet, folder); err != nil {
+		return fmt.Errorf("imap move %d to %s: %w", uid, folder, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package mailbox
+
+import (
+	"bytes"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/backend"
+	"github.com/emersion/go-imap/backend/memory"
+	"github.com/emersion/go-imap/server"
+)
+
+// moveBackend adds MOVE extension to memory backend.
+type moveBackend struct {
+	*memory.Backend
+}
+
+type moveUser struct {
+	backend.User
+}
+
+type moveMailbox struct {
+	backend.Mailbox
+}
+
+func (b moveBackend) Login(info *imap.ConnInfo, username, password string) (backend.User, error) {
+	user, err := b.Backend.Login(info, username, password)
+
+	return moveUser{user}, err
+}
+
+func (u moveUser) GetMailbox(name string) (backend.Mailbox, error) {
+	mbox, err := u.User.GetMailbox(name)
+
+	return moveMailbox{mbox}, err
+}
+
+func (m moveMailbox) MoveMessages(uid bool, seqSet *imap.SeqSet, dest string) error {
+	if err := m.CopyMessages(uid, seqSet, dest); err != nil {
+		return err
+	}
+
+	if err := m.UpdateMessagesFlags(uid, seqSet, imap.AddFlags, []string{imap.DeletedFlag}); err != nil {
+		return err
+	}
+
+	return m.Expunge()
+}
+
+func imapServer(t *testing.T) (Config, *memory.Backend) {
+	t.Helper()
+
+	be := memory.New()
+
+	s := server.New(moveBackend{be})
+	s.AllowInsecureAuth = true
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() { _ = s.Serve(l) }()
+
+	t.Cleanup(func() { s.Close() })
+
+	host, portRaw, _ := net.SplitHostPort(l.Addr().String())
+	port, _ := strconv.Atoi(portRaw)
+
+	return Config{
+		Host:     host,
+		Port:     port,
+		Username: "username",
+		Password: "password",
+		TLSMode:  TLSModeNone,
+		Timeout:  5 * time.Second,
+	}, be
+}
+
+func TestClient(t *testing.T) {
+	cfg, be := imapServer(t)
+
+	user, err := be.Login(nil, "username", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := user.CreateMailbox("Processed"); err != nil {
+		t.Fatal(err)
+	}
+
+	inbox, err := user.GetMailbox("INBOX")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, subject := range []string{"invoice 1", "invoice 2"} {
+		body := "From: supplier@example.com\r\nTo: ap@example.com\r\nSubject: " + subject + "\r\n" +
+			"Content-Type: text/plain\r\n\r\nplease pay"
+		if err := inbox.CreateMessage(nil, time.Now(), bytes.NewBufferString(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c, err := Dial(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer c.Close()
+
+	messages, err := c.Unseen("INBOX", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// initial message of the backend is seen
+	if len(messages) != 2 {
+		t.Fatalf("Unseen() = %d messages, want 2", len(messages))
+	}
+
+	if messages[0].Mail.Subject != "invoice 1" || messages[0].Mail.From != "supplier@example.com" || messages[0].Mail.Text != "please pay" {
+		t.Errorf("Unseen()[0] = %+v", messages[0].Mail)
+	}
+
+	if err := c.MarkSeen(messages[0].UID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Move(messages[1].UID, "Processed"); err != nil {
+		t.Fatal(err)
+	}
+
+	messages, err = c.Unseen("INBOX", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(messages) != 0 {
+		t.Errorf("Unseen() after processed = %d messages, want 0", len(messages))
+	}
+
+	processed, err := user.GetMailbox("Processed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(processed.(*memory.Mailbox).Messages); n != 1 {
+		t.Errorf("Processed has %d messages, want 1", n)
+	}
+}
@@ -0,0 +1,161 @@
+package mailbox
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"time"
+
+	// decode non utf-8 charsets of the parts
+	_ "github.com/emersion/go-message/charset"
+	"github.com/emersion/go-message/mail"
+)
+
+// Mail is parsed message used as input of the flow.
+type Mail struct {
+	MessageID   string       `json:"message_id"`
+	From        string       `json:"from"`
+	To          []string     `json:"to"`
+	Cc          []string     `json:"cc"`
+	ReplyTo     []string     `json:"reply_to"`
+	Subject     string       `json:"subject"`
+	Date        time.Time    `json:"date"`
+	Text        string       `json:"text"`
+	HTML        string       `json:"html"`
+	Attachments []Attachment `json:"attachments"`
+}
+
+// Attachment of the mail, content is base64.
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	// ContentID is set for inline files referenced in HTML.
+	ContentID string `json:"content_id,omitempty"`
+	Inline    bool   `json:"inline"`
+	Size      int    `json:"size"`
+	Content   string `json:"content"`
+}
+
+// Parse reads RFC 5322 message with MIME parts.
+func Parse(r io.Reader) (Mail, error) {
+	mr, err := mail.CreateReader(r)
+	if err != nil {
+		return Mail{}, fmt.Errorf("mail cannot read: %w", err)
+	}
+
+	defer mr.Close()
+
+	m := Mail{
+		To:          addresses(mr.Header, "To"),
+		Cc:          addresses(mr.Header, "Cc"),
+		ReplyTo:     addresses(mr.Header, "Reply-To"),
+		Attachments: []Attachment{},
+	}
+
+	if from := addresses(mr.Header, "From"); len(from) > 0 {
+		m.From = from[0]
+	}
+
+	m.Subject, _ = mr.Header.Subject()
+	m.Date, _ = mr.Header.Date()
+	m.MessageID, _ = mr.Header.MessageID()
+
+	for {
+		p, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return m, fmt.Errorf("mail part cannot read: %w", err)
+		}
+
+		body, err := io.ReadAll(p.Body)
+		if err != nil {
+			return m, fmt.Errorf("mail part cannot read: %w", err)
+		}
+
+		contentType, params, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+
+		switch h := p.Header.(type) {
+		case *mail.InlineHeader:
+			switch {
+			case contentType == "text/plain" && m.Text == "":
+				m.Text = string(body)
+
+				continue
+			case contentType == "text/html" && m.HTML == "":
+				m.HTML = string(body)
+
+				continue
+			case contentType == "" && m.Text == "":
+				m.Text = string(body)
+
+				continue
+			}
+
+			_, dispParams, _ := h.ContentDisposition()
+			m.Attachments = append(m.Attachments, attachment(
+				fileName(dispParams["filename"], params["name"]), contentType, h.Get("Content-Id"), true, body,
+			))
+		case *mail.AttachmentHeader:
+			filename, _ := h.Filename()
+			m.Attachments = append(m.Attachments, attachment(
+				fileName(filename, params["name"]), contentType, h.Get("Content-Id"), false, body,
+			))
+		}
+	}
+
+	return m, nil
+}
+
+func attachment(filename, contentType, contentID string, inline bool, body []byte) Attachment {
+	return Attachment{
+		Filename:    filename,
+		ContentType: contentType,
+		ContentID:   strings.Trim(contentID, "<>"),
+		Inline:      inline,
+		Size:        len(body),
+		Content:     base64.StdEncoding.EncodeToString(body),
+	}
+}
+
+func fileName(names ...string) string {
+	decoder := mime.WordDecoder{}
+
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+
+		if decoded, err := decoder.DecodeHeader(name); err == nil {
+			return decoded
+		}
+
+		return name
+	}
+
+	return ""
+}
+
+func addresses(h mail.Header, key string) []string {
+	list, err := h.AddressList(key)
+	if err != nil || len(list) == 0 {
+		// keep raw value of the invalid addresses
+		if v := h.Get(key); v != "" && err != nil {
+			return []string{v}
+		}
+
+		return []string{}
+	}
+
+	ret := make([]string, 0, len(list))
+	for _, a := range list {
+		ret = append(ret, a.Address)
+	}
+
+	return ret
+}
@@ -0,0 +1,80 @@
+package mailbox
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	raw := strings.Join([]string{
+		`From: "Supplier" <supplier@example.com>`,
+		`To: ap@example.com, finance@example.com`,
+		`Subject: =?UTF-8?B?SW52b2ljZSDigqwxMDA=?=`,
+		`Message-ID: <abc@example.com>`,
+		`MIME-Version: 1.0`,
+		`Content-Type: multipart/mixed; boundary="mixed"`,
+		``,
+		`--mixed`,
+		`Content-Type: multipart/related; boundary="related"`,
+		``,
+		`--related`,
+		`Content-Type: multipart/alternative; boundary="alt"`,
+		``,
+		`--alt`,
+		`Content-Type: text/plain; charset=iso-8859-1`,
+		`Content-Transfer-Encoding: quoted-printable`,
+		``,
+		`Caf=E9`,
+		`--alt`,
+		`Content-Type: text/html; charset=utf-8`,
+		``,
+		`<p>Café</p><img src="cid:logo">`,
+		`--alt--`,
+		`--related`,
+		`Content-Type: image/png`,
+		`Content-Disposition: inline; filename="logo.png"`,
+		`Content-ID: <logo>`,
+		`Content-Transfer-Encoding: base64`,
+		``,
+		`cG5n`,
+		`--related--`,
+		`--mixed`,
+		`Content-Type: application/pdf; name="invoice.pdf"`,
+		`Content-Disposition: attachment; filename="invoice.pdf"`,
+		`Content-Transfer-Encoding: base64`,
+		``,
+		base64.StdEncoding.EncodeToString([]byte("%PDF-1.4")),
+		`--mixed--`,
+		``,
+	}, "\r\n")
+
+	m, err := Parse(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.From != "supplier@example.com" || len(m.To) != 2 || m.To[1] != "finance@example.com" {
+		t.Errorf("addresses = %s %v", m.From, m.To)
+	}
+
+	if m.Subject != "Invoice €100" || m.MessageID != "abc@example.com" {
+		t.Errorf("subject = %s, message id = %s", m.Subject, m.MessageID)
+	}
+
+	if m.Text != "Café" || m.HTML != `<p>Café</p><img src="cid:logo">` {
+		t.Errorf("text = %q, html = %q", m.Text, m.HTML)
+	}
+
+	if len(m.Attachments) != 2 {
+		t.Fatalf("attachments = %+v", m.Attachments)
+	}
+
+	if a := m.Attachments[0]; a.Filename != "logo.png" || !a.Inline || a.ContentID != "logo" || a.Content != "cG5n" {
+		t.Errorf("inline = %+v", a)
+	}
+
+	if a := m.Attachments[1]; a.Filename != "invoice.pdf" || a.Inline || a.ContentType != "application/pdf" || a.Size != 8 {
+		t.Errorf("attachment = %+v", a)
+	}
+}
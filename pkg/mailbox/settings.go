@@ -0,0 +1,51 @@
+package mailbox
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/worldline-go/chore/pkg/models"
+)
+
+// ParseSettings checks stored imap profile and returns connection config.
+func ParseSettings(m models.IMAP) (Config, error) {
+	cfg := Config{
+		Host:       strings.TrimSpace(m.Host),
+		Username:   strings.TrimSpace(m.Username),
+		Password:   m.Password,
+		TLSMode:    strings.ToLower(strings.TrimSpace(m.TLSMode)),
+		SkipVerify: m.SkipVerify,
+	}
+
+	if cfg.Host == "" {
+		return cfg, errors.New("imap host is empty")
+	}
+
+	port, err := strconv.Atoi(strings.TrimSpace(m.Port))
+	if err != nil || port <= 0 || port > 65535 {
+		return cfg, fmt.Errorf("imap port %q is not valid", m.Port)
+	}
+
+	cfg.Port = port
+
+	switch cfg.TLSMode {
+	case TLSModeAuto, TLSModeSSL, TLSModeStartTLS, TLSModeNone:
+	default:
+		return cfg, fmt.Errorf("imap tls mode %q not supported, use ssl, starttls or none", m.TLSMode)
+	}
+
+	if cfg.Username == "" {
+		return cfg, errors.New("imap username is empty")
+	}
+
+	if timeout := strings.TrimSpace(m.Timeout); timeout != "" {
+		if cfg.Timeout, err = time.ParseDuration(timeout); err != nil {
+			return cfg, fmt.Errorf("imap timeout %q cannot parse: %w", timeout, err)
+		}
+	}
+
+	return cfg, nil
+}
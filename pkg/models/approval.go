@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/worldline-go/chore/pkg/models/apimodels"
+)
+
+var (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+	ApprovalTimeout  = "timeout"
+)
+
+type ApprovalPure struct {
+	Control   string     `json:"control" example:"deploy"`
+	Endpoint  string     `json:"endpoint" example:"production"`
+	NodeID    string     `json:"node_id" example:"5"`
+	Status    string     `json:"status" gorm:"index;not null" example:"pending"`
+	Value     string     `json:"value" swaggertype:"string" format:"base64" example:"aGVsbG8ge3submFtZX19Cg=="`
+	Comment   string     `json:"comment" example:"looks good"`
+	DecidedBy string     `json:"decided_by" example:"cf8a07d4-077e-402e-a46b-ac0ed50989ec"`
+	DecidedAt *time.Time `json:"decided_at" example:"2021-02-18T21:54:42.123Z"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2021-02-18T21:54:42.123Z"`
+	RunID     string     `json:"run_id" example:"cf8a07d4-077e-402e-a46b-ac0ed50989ec"`
+	TaskID    string     `json:"-" gorm:"index"`
+	apimodels.Groups
+}
+
+type Approval struct {
+	ApprovalPure
+	apimodels.ModelCU
+}
+
+type ApprovalDecision struct {
+	Comment string `json:"comment" example:"looks good"`
+}
@@ -10,6 +10,8 @@ type AuthPure struct {
 	Name    string            `json:"name" gorm:"unique;uniqueIndex;not null" example:"jira-deepcore"`
 	Headers datatypes.JSONMap `json:"headers" swaggertype:"object,string" example:"Content-Type:application/json"`
 	Data    string            `json:"data" example:"any data"`
+	Type    string            `json:"type" example:"basic"`
+	Config  datatypes.JSONMap `json:"config" swaggertype:"object,string" example:"username:chore"`
 	apimodels.Groups
 }
 
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// RequestCache is a cached response of the request node.
+type RequestCache struct {
+	Key          string         `gorm:"primaryKey"`
+	StatusCode   int            `gorm:"not null"`
+	Header       datatypes.JSON `gorm:"type:jsonb"`
+	Body         []byte         `gorm:"type:bytea"`
+	ETag         string
+	LastModified string
+	Revalidate   bool
+	ExpiresAt    time.Time `gorm:"not null"`
+	KeepUntil    time.Time `gorm:"index;not null"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
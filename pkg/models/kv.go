@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// KV is a value of the key-value store, keys are unique in the namespace.
+type KV struct {
+	Namespace string     `json:"namespace" gorm:"primaryKey"`
+	Key       string     `json:"key" gorm:"primaryKey"`
+	Value     string     `json:"value" gorm:"type:text;not null"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" gorm:"index"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
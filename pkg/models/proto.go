@@ -0,0 +1,17 @@
+package models
+
+import (
+	"github.com/worldline-go/chore/pkg/models/apimodels"
+)
+
+// ProtoPure holds a serialized protobuf FileDescriptorSet for grpc nodes.
+type ProtoPure struct {
+	Name    string `json:"name" gorm:"unique;uniqueIndex;not null" example:"payments"`
+	Content string `json:"content" swaggertype:"string" format:"base64" example:"CgtoZWxsby5wcm90bw=="`
+	apimodels.Groups
+}
+
+type Proto struct {
+	ProtoPure
+	apimodels.ModelCU
+}
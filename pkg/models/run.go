@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+
+	"github.com/worldline-go/chore/pkg/models/apimodels"
+)
+
+var (
+	RunRunning   = "running"
+	RunSuspended = "suspended"
+	RunCompleted = "completed"
+	RunFailed    = "failed"
+)
+
+var (
+	TaskPending   = "pending"
+	TaskHeld      = "held"
+	TaskSuspended = "suspended"
+	TaskDone      = "done"
+)
+
+type RunPure struct {
+	Control     string     `json:"control" gorm:"index" example:"deploy"`
+	Endpoint    string     `json:"endpoint" example:"production"`
+	Method      string     `json:"method" example:"POST"`
+	ParentRunID string     `json:"parent_run_id" gorm:"index" example:"cf8a07d4-077e-402e-a46b-ac0ed50989ec"`
+	Status      string     `json:"status" gorm:"index;not null" example:"running"`
+	Errors      string     `json:"errors" example:"request cannot run"`
+	Owner       string     `json:"owner" example:"cf8a07d4-077e-402e-a46b-ac0ed50989ec"`
+	LockedUntil *time.Time `json:"locked_until" example:"2021-02-18T21:54:42.123Z"`
+	WakeAt      *time.Time `json:"wake_at" example:"2021-02-18T21:54:42.123Z"`
+	FinishedAt  *time.Time `json:"finished_at" example:"2021-02-18T21:54:42.123Z"`
+}
+
+// Run is a durable record of a control flow run.
+type Run struct {
+	RunPure
+	apimodels.ModelCU
+}
+
+// RunTask is a checkpoint of a value waiting to run on a node.
+type RunTask struct {
+	RunID     uuid.UUID      `gorm:"type:uuid;index;not null"`
+	Node      string         `gorm:"not null"`
+	Input     string         `gorm:"not null"`
+	Status    string         `gorm:"index;not null"`
+	Data      []byte         `gorm:"type:bytea"`
+	Values    []byte         `gorm:"type:bytea"`
+	Respond   datatypes.JSON `gorm:"type:jsonb"`
+	NotBefore *time.Time
+	apimodels.ModelCU
+}
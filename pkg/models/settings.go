@@ -11,6 +11,61 @@ type Email struct {
 	Port     string `json:"port"`
 	NoAuth   bool   `json:"no_auth"`
 	Password string `json:"password"`
+	// TLSMode is empty for auto, ssl, starttls or none.
+	TLSMode    string `json:"tls_mode"`
+	SkipVerify bool   `json:"skip_verify"`
+	// LocalName is hostname of the HELO command.
+	LocalName string `json:"local_name"`
+	Timeout   string `json:"timeout"`
+	// ReplyTo is default Reply-To header of the emails.
+	ReplyTo string `json:"reply_to"`
+}
+
+// IMAP is a mailbox profile of the email trigger.
+type IMAP struct {
+	Host     string `json:"host"`
+	Port     string `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// TLSMode is empty for auto, ssl, starttls or none.
+	TLSMode    string `json:"tls_mode"`
+	SkipVerify bool   `json:"skip_verify"`
+	Timeout    string `json:"timeout"`
+}
+
+// Broker is a message queue profile of the publish node.
+type Broker struct {
+	// Driver is kafka, nats or amqp.
+	Driver string `json:"driver"`
+	// Brokers is comma separated host:port for kafka, URLs for nats and amqp.
+	Brokers    string `json:"brokers"`
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+	TLS        bool   `json:"tls"`
+	SkipVerify bool   `json:"skip_verify"`
+	// Exchange is used by amqp, empty is default exchange.
+	Exchange string `json:"exchange"`
+	// JetStream waits stream acknowledgement in nats.
+	JetStream bool   `json:"jetstream"`
+	Timeout   string `json:"timeout"`
+}
+
+// Database is a connection profile of the sql node.
+type Database struct {
+	// Driver is postgres, default is postgres.
+	Driver string `json:"driver"`
+	// DSN is used instead of connection fields if not empty.
+	DSN      string `json:"dsn"`
+	Host     string `json:"host"`
+	Port     string `json:"port"`
+	User     string `json:"user"`
+	Password string `json:"password"`
+	DBName   string `json:"db_name"`
+	SSLMode  string `json:"ssl_mode"`
+	// MaxOpenConns limits connections of the profile.
+	MaxOpenConns string `json:"max_open_conns"`
+	// ReadOnly allows only read only queries.
+	ReadOnly bool `json:"read_only"`
 }
 
 type SettingsPure struct {
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// TriggerPoll is poll state of a trigger node, instances claim it before polling.
+type TriggerPoll struct {
+	// Key is control name and node id.
+	Key         string `gorm:"primaryKey"`
+	Control     string `gorm:"index;not null"`
+	Node        string `gorm:"not null"`
+	Owner       string
+	LockedUntil *time.Time
+	NextPoll    time.Time `gorm:"not null"`
+	LastError   string
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,123 @@
+// Package refpool shares values like connections by name between runs and closes them when not used.
+package refpool
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultIdle is waiting time to close a value not used by anyone.
+const DefaultIdle = 5 * time.Minute
+
+// Pool keeps one value for each name, value is opened again when key of the name changed.
+type Pool[T any] struct {
+	// Idle is waiting time to close a value not used by anyone, deleted names are closed with it.
+	Idle time.Duration
+
+	close  func(T)
+	usable func(T) bool
+
+	mutex sync.Mutex
+	m     map[string]*entry[T]
+}
+
+type entry[T any] struct {
+	key   string
+	value T
+	refs  int
+	// stale entry is replaced with changed key, closed after the last release.
+	stale bool
+	idle  *time.Timer
+}
+
+// New returns pool closing values with closeFn, usable reports an opened value still can be shared
+// and nil usable shares values always.
+func New[T any](closeFn func(T), usable func(T) bool) *Pool[T] {
+	return &Pool[T]{
+		Idle:   DefaultIdle,
+		close:  closeFn,
+		usable: usable,
+		m:      make(map[string]*entry[T]),
+	}
+}
+
+// Acquire returns shared value of the name with same key, open called when there is no usable value.
+// Release should be called after using it.
+func (p *Pool[T]) Acquire(name, key string, open func() (T, error)) (T, func(), error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if e, ok := p.m[name]; ok {
+		if e.key == key && (p.usable == nil || p.usable(e.value)) {
+			e.refs++
+
+			if e.idle != nil {
+				e.idle.Stop()
+				e.idle = nil
+			}
+
+			return e.value, p.releaseFunc(name, e), nil
+		}
+
+		// users close it after finished
+		e.stale = true
+		delete(p.m, name)
+
+		if e.refs == 0 {
+			p.closeEntry(e)
+		}
+	}
+
+	value, err := open()
+	if err != nil {
+		var zero T
+
+		return zero, nil, err
+	}
+
+	e := &entry[T]{key: key, value: value, refs: 1}
+	p.m[name] = e
+
+	return value, p.releaseFunc(name, e), nil
+}
+
+func (p *Pool[T]) releaseFunc(name string, e *entry[T]) func() {
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			p.mutex.Lock()
+			defer p.mutex.Unlock()
+
+			e.refs--
+			if e.refs > 0 {
+				return
+			}
+
+			if e.stale {
+				p.closeEntry(e)
+
+				return
+			}
+
+			e.idle = time.AfterFunc(p.Idle, func() {
+				p.mutex.Lock()
+				defer p.mutex.Unlock()
+
+				if e.refs == 0 && p.m[name] == e {
+					delete(p.m, name)
+					p.closeEntry(e)
+				}
+			})
+		})
+	}
+}
+
+func (p *Pool[T]) closeEntry(e *entry[T]) {
+	if e.idle != nil {
+		e.idle.Stop()
+		e.idle = nil
+	}
+
+	p.close(e.value)
+}
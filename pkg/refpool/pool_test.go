@@ -0,0 +1,119 @@
+package refpool
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type value struct {
+	id     int
+	closed bool
+	broken bool
+}
+
+func TestPool(t *testing.T) {
+	var (
+		mutex  sync.Mutex
+		opened int
+	)
+
+	isClosed := func(v *value) bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return v.closed
+	}
+
+	p := New(
+		func(v *value) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			v.closed = true
+		},
+		func(v *value) bool { return !v.broken },
+	)
+	p.Idle = 50 * time.Millisecond
+
+	open := func() (*value, error) {
+		opened++
+
+		return &value{id: opened}, nil
+	}
+
+	first, releaseFirst, err := p.Acquire("orders", "a", open)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, releaseSecond, err := p.Acquire("orders", "a", open)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first != second || opened != 1 {
+		t.Fatalf("Acquire() same key not shared, opened = %d", opened)
+	}
+
+	// changed key opens new value, used one closed after the last release
+	changed, releaseChanged, err := p.Acquire("orders", "b", open)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if changed == first {
+		t.Fatal("Acquire() changed key shared old value")
+	}
+
+	releaseFirst()
+	releaseFirst()
+
+	if isClosed(first) {
+		t.Fatal("value closed while used")
+	}
+
+	releaseSecond()
+
+	if !isClosed(first) {
+		t.Fatal("stale value not closed after release")
+	}
+
+	// not usable value opened again
+	changed.broken = true
+
+	reopened, releaseReopened, err := p.Acquire("orders", "b", open)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reopened == changed {
+		t.Fatal("Acquire() shared not usable value")
+	}
+
+	releaseChanged()
+	releaseReopened()
+
+	// not used value closed after idle and removed
+	deadline := time.Now().Add(2 * time.Second)
+	for !isClosed(reopened) {
+		if time.Now().After(deadline) {
+			t.Fatal("idle value not closed")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	p.mutex.Lock()
+	_, ok := p.m["orders"]
+	p.mutex.Unlock()
+
+	if ok {
+		t.Error("idle value kept in pool")
+	}
+
+	if _, _, err := p.Acquire("users", "a", func() (*value, error) { return nil, errors.New("down") }); err == nil {
+		t.Error("Acquire() open error not returned")
+	}
+}
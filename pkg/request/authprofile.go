@@ -0,0 +1,320 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Auth profile types, header type is static headers of the auth.
+const (
+	AuthHeader      = "header"
+	AuthBasic       = "basic"
+	AuthAPIKeyQuery = "api_key_query"
+	AuthHMAC        = "hmac"
+	AuthAWSSigV4    = "aws_sigv4"
+)
+
+// AuthProfile is config of the auth types applied on each request.
+type AuthProfile struct {
+	// Name of the profile, not part of the config.
+	Name string `json:"-"`
+	Type string `json:"type"`
+
+	// basic
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// api_key_query
+	Param string `json:"param"`
+	Key   string `json:"key"`
+
+	// hmac
+	Secret string `json:"secret"`
+	// Algorithm is sha256 or sha512, default sha256.
+	Algorithm string `json:"algorithm"`
+	// Encoding of the signature is hex or base64, default hex.
+	Encoding string `json:"encoding"`
+	// Header of the signature, default X-Signature.
+	Header string `json:"header"`
+	// TimestampHeader of the unix seconds, default X-Timestamp.
+	TimestampHeader string `json:"timestamp_header"`
+	// Prefix is added to signature like "sha256=".
+	Prefix string `json:"prefix"`
+
+	// aws_sigv4
+	AccessKey    string `json:"access_key"`
+	SecretKey    string `json:"secret_key"`
+	SessionToken string `json:"session_token"`
+	Region       string `json:"region"`
+	Service      string `json:"service"`
+}
+
+// RoundTripper returns wrapper to use in client, nil for header type.
+func (p AuthProfile) RoundTripper() (func(context.Context, http.RoundTripper) (http.RoundTripper, error), error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	if p.Type == "" || p.Type == AuthHeader {
+		return nil, nil
+	}
+
+	return func(_ context.Context, base http.RoundTripper) (http.RoundTripper, error) {
+		return &authTransport{profile: p, base: base}, nil
+	}, nil
+}
+
+// Validate checks required fields of the type.
+func (p AuthProfile) Validate() error {
+	switch p.Type {
+	case "", AuthHeader:
+	case AuthBasic:
+		if p.Username == "" {
+			return fmt.Errorf("basic auth needs username")
+		}
+	case AuthAPIKeyQuery:
+		if p.Param == "" || p.Key == "" {
+			return fmt.Errorf("api key auth needs param and key")
+		}
+	case AuthHMAC:
+		if p.Secret == "" {
+			return fmt.Errorf("hmac auth needs secret")
+		}
+
+		if _, err := p.hmacHash(); err != nil {
+			return err
+		}
+
+		switch p.Encoding {
+		case "", "hex", "base64":
+		default:
+			return fmt.Errorf("hmac encoding %q not supported", p.Encoding)
+		}
+	case AuthAWSSigV4:
+		if p.AccessKey == "" || p.SecretKey == "" || p.Region == "" || p.Service == "" {
+			return fmt.Errorf("aws sigv4 auth needs access_key, secret_key, region and service")
+		}
+	default:
+		return fmt.Errorf("auth type %q not supported", p.Type)
+	}
+
+	return nil
+}
+
+func (p AuthProfile) hmacHash() (func() hash.Hash, error) {
+	switch p.Algorithm {
+	case "", "sha256":
+		return sha256.New, nil
+	case "sha512":
+		return sha512.New, nil
+	}
+
+	return nil, fmt.Errorf("hmac algorithm %q not supported", p.Algorithm)
+}
+
+type authTransport struct {
+	profile AuthProfile
+	base    http.RoundTripper
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+
+	switch t.profile.Type {
+	case AuthBasic:
+		req.SetBasicAuth(t.profile.Username, t.profile.Password)
+	case AuthAPIKeyQuery:
+		query := req.URL.Query()
+		query.Set(t.profile.Param, t.profile.Key)
+		req.URL.RawQuery = query.Encode()
+	case AuthHMAC, AuthAWSSigV4:
+		body, err := readBody(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if t.profile.Type == AuthHMAC {
+			t.profile.signHMAC(req, body, time.Now())
+		} else {
+			t.profile.signAWSV4(req, body, time.Now())
+		}
+	}
+
+	return t.base.RoundTrip(req) //nolint:wrapcheck // transport error
+}
+
+// readBody returns body and sets new reader to the request.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body for signing: %w", err)
+	}
+
+	_ = req.Body.Close()
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+// signHMAC signs "METHOD\nPATH?QUERY\nTIMESTAMP\nhex(sha256(body))".
+func (p AuthProfile) signHMAC(req *http.Request, body []byte, now time.Time) {
+	newHash, _ := p.hmacHash()
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	bodyHash := sha256.Sum256(body)
+
+	mac := hmac.New(newHash, []byte(p.Secret))
+	mac.Write([]byte(req.Method + "\n" + req.URL.RequestURI() + "\n" + timestamp + "\n" + hex.EncodeToString(bodyHash[:])))
+
+	var signature string
+	if p.Encoding == "base64" {
+		signature = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	} else {
+		signature = hex.EncodeToString(mac.Sum(nil))
+	}
+
+	header := p.Header
+	if header == "" {
+		header = "X-Signature"
+	}
+
+	timestampHeader := p.TimestampHeader
+	if timestampHeader == "" {
+		timestampHeader = "X-Timestamp"
+	}
+
+	req.Header.Set(timestampHeader, timestamp)
+	req.Header.Set(header, p.Prefix+signature)
+}
+
+// signAWSV4 adds AWS Signature Version 4 headers.
+func (p AuthProfile) signAWSV4(req *http.Request, body []byte, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	payloadHash := sha256.Sum256(body)
+	payloadHex := hex.EncodeToString(payloadHash[:])
+
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	if p.Service == "s3" {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHex)
+	}
+
+	if p.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", p.SessionToken)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	// signed headers
+	headers := map[string]string{"host": host}
+	for k, v := range req.Header {
+		lower := strings.ToLower(k)
+		if lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.Join(v, ",")
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+
+	sort.Strings(names)
+
+	canonicalHeaders := strings.Builder{}
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + strings.TrimSpace(headers[k]) + "\n")
+	}
+
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		awsCanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHex,
+	}, "\n")
+
+	scope := date + "/" + p.Region + "/" + p.Service + "/aws4_request"
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+p.SecretKey), date)
+	key = hmacSHA256(key, p.Region)
+	key = hmacSHA256(key, p.Service)
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		p.AccessKey, scope, signedHeaders, signature,
+	))
+}
+
+func awsCanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+
+	for _, k := range keys {
+		values := query[k]
+		sort.Strings(values)
+
+		for _, v := range values {
+			parts = append(parts, awsEscape(k)+"="+awsEscape(v))
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+// awsEscape encodes everything except unreserved characters.
+func awsEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
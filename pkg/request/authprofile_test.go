@@ -0,0 +1,95 @@
+package request
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAuthProfile_signAWSV4(t *testing.T) {
+	// get-vanilla of the aws signature v4 test suite
+	p := AuthProfile{
+		Type:      AuthAWSSigV4,
+		AccessKey: "AKIDEXAMPLE",
+		SecretKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:    "us-east-1",
+		Service:   "service",
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.signAWSV4(req, nil, time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization = %s, want %s", got, want)
+	}
+}
+
+func TestClient_AuthProfile(t *testing.T) {
+	var got *http.Request
+
+	var gotBody string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+	}))
+	defer srv.Close()
+
+	call := func(p AuthProfile) {
+		t.Helper()
+
+		c, err := NewClient(Config{Profile: p})
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+
+		if _, err := c.Call(context.TODO(), srv.URL+"/items?a=1", http.MethodPost, nil, []byte(`{"x":1}`)); err != nil {
+			t.Fatalf("Client.Call() error = %v", err)
+		}
+	}
+
+	call(AuthProfile{Type: AuthBasic, Username: "user", Password: "pass"})
+
+	if user, pass, ok := got.BasicAuth(); !ok || user != "user" || pass != "pass" {
+		t.Errorf("basic auth = %s:%s", user, pass)
+	}
+
+	call(AuthProfile{Type: AuthAPIKeyQuery, Param: "api_key", Key: "secret"})
+
+	if v := got.URL.Query().Get("api_key"); v != "secret" || got.URL.Query().Get("a") != "1" {
+		t.Errorf("query = %s", got.URL.RawQuery)
+	}
+
+	call(AuthProfile{Type: AuthHMAC, Secret: "key", Prefix: "sha256="})
+
+	bodyHash := sha256.Sum256([]byte(`{"x":1}`))
+	mac := hmac.New(sha256.New, []byte("key"))
+	mac.Write([]byte("POST\n/items?a=1\n" + got.Header.Get("X-Timestamp") + "\n" + hex.EncodeToString(bodyHash[:])))
+
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); got.Header.Get("X-Signature") != want {
+		t.Errorf("signature = %s, want %s", got.Header.Get("X-Signature"), want)
+	}
+
+	if gotBody != `{"x":1}` {
+		t.Errorf("body = %s after signing", gotBody)
+	}
+
+	if _, err := NewClient(Config{Profile: AuthProfile{Type: AuthAWSSigV4}}); err == nil {
+		t.Errorf("NewClient() missing aws keys should fail")
+	}
+}
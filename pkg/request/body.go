@@ -0,0 +1,180 @@
+package request
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Body modes of the request.
+const (
+	BodyRaw       = "raw"
+	BodyForm      = "form"
+	BodyMultipart = "multipart"
+)
+
+// Body of the request, Raw used in raw mode and Fields, Files used in others.
+type Body struct {
+	Mode   string
+	Raw    []byte
+	Fields map[string]interface{}
+	Files  []File
+	// ContentType of the raw body, set when request has no Content-Type header.
+	ContentType string
+}
+
+// File is a file part of the multipart body.
+type File struct {
+	Field       string
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// Encode returns payload and content type of the body.
+// Content type of raw mode comes from ContentType.
+func (b Body) Encode() ([]byte, string, error) {
+	switch b.Mode {
+	case "", BodyRaw:
+		return b.Raw, b.ContentType, nil
+	case BodyForm:
+		values := url.Values{}
+
+		for _, k := range sortedKeys(b.Fields) {
+			for _, v := range fieldValues(b.Fields[k]) {
+				values.Add(k, v)
+			}
+		}
+
+		return []byte(values.Encode()), "application/x-www-form-urlencoded", nil
+	case BodyMultipart:
+		return b.encodeMultipart()
+	}
+
+	return nil, "", fmt.Errorf("body mode %q not supported", b.Mode)
+}
+
+// Hash returns stable hash of the body, multipart boundary not included.
+func (b Body) Hash() string {
+	h := sha256.New()
+
+	h.Write([]byte(b.Mode + "\n"))
+
+	switch b.Mode {
+	case BodyForm, BodyMultipart:
+		for _, k := range sortedKeys(b.Fields) {
+			for _, v := range fieldValues(b.Fields[k]) {
+				h.Write([]byte(k + "=" + v + "\n"))
+			}
+		}
+
+		for _, f := range b.Files {
+			h.Write([]byte(f.Field + "\n" + f.FileName + "\n" + f.ContentType + "\n"))
+
+			sum := sha256.Sum256(f.Content)
+			h.Write(sum[:])
+		}
+	default:
+		h.Write(b.Raw)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (b Body) encodeMultipart() ([]byte, string, error) {
+	buf := &bytes.Buffer{}
+	writer := multipart.NewWriter(buf)
+
+	for _, k := range sortedKeys(b.Fields) {
+		for _, v := range fieldValues(b.Fields[k]) {
+			if err := writer.WriteField(k, v); err != nil {
+				return nil, "", fmt.Errorf("failed to write field %s: %w", k, err)
+			}
+		}
+	}
+
+	for _, f := range b.Files {
+		if f.Field == "" {
+			return nil, "", fmt.Errorf("file field name is empty")
+		}
+
+		fileName := f.FileName
+		if fileName == "" {
+			fileName = f.Field
+		}
+
+		contentType := f.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition",
+			fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escapeQuotes(f.Field), escapeQuotes(fileName)))
+		header.Set("Content-Type", contentType)
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create file part %s: %w", f.Field, err)
+		}
+
+		if _, err := part.Write(f.Content); err != nil {
+			return nil, "", fmt.Errorf("failed to write file part %s: %w", f.Field, err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to close multipart: %w", err)
+	}
+
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
+
+// fieldValues converts value to form values, lists are repeated and objects are json.
+func fieldValues(v interface{}) []string {
+	switch vType := v.(type) {
+	case nil:
+		return []string{""}
+	case string:
+		return []string{vType}
+	case []byte:
+		return []string{string(vType)}
+	case []interface{}:
+		values := make([]string, 0, len(vType))
+		for _, item := range vType {
+			values = append(values, fieldValues(item)...)
+		}
+
+		return values
+	case map[string]interface{}:
+		b, _ := json.Marshal(vType)
+
+		return []string{string(b)}
+	}
+
+	return []string{fmt.Sprint(v)}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
@@ -0,0 +1,104 @@
+package request
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"testing"
+)
+
+func TestBody_Encode(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            Body
+		wantPayload     string
+		wantContentType string
+		wantErr         bool
+	}{
+		{
+			name:        "raw",
+			body:        Body{Raw: []byte(`{"name":"test"}`)},
+			wantPayload: `{"name":"test"}`,
+		},
+		{
+			name: "form",
+			body: Body{
+				Mode: BodyForm,
+				Fields: map[string]interface{}{
+					"name":  "chore test",
+					"count": 2,
+					"tags":  []interface{}{"a", "b"},
+				},
+			},
+			wantPayload:     "count=2&name=chore+test&tags=a&tags=b",
+			wantContentType: "application/x-www-form-urlencoded",
+		},
+		{
+			name:    "unknown mode",
+			body:    Body{Mode: "xml"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, contentType, err := tt.body.Encode()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Body.Encode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if string(payload) != tt.wantPayload {
+				t.Errorf("Body.Encode() payload = %s, want %s", payload, tt.wantPayload)
+			}
+
+			if contentType != tt.wantContentType {
+				t.Errorf("Body.Encode() content type = %s, want %s", contentType, tt.wantContentType)
+			}
+		})
+	}
+}
+
+func TestBody_EncodeMultipart(t *testing.T) {
+	body := Body{
+		Mode:   BodyMultipart,
+		Fields: map[string]interface{}{"comment": "report"},
+		Files: []File{
+			{Field: "file", FileName: "report.txt", ContentType: "text/plain", Content: []byte("hello")},
+		},
+	}
+
+	payload, contentType, err := body.Encode()
+	if err != nil {
+		t.Fatalf("Body.Encode() error = %v", err)
+	}
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("content type cannot parse: %v", err)
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(payload), params["boundary"])
+
+	part, err := reader.NextPart()
+	if err != nil {
+		t.Fatalf("field part: %v", err)
+	}
+
+	if v, _ := io.ReadAll(part); part.FormName() != "comment" || string(v) != "report" {
+		t.Errorf("field = %s:%s, want comment:report", part.FormName(), v)
+	}
+
+	part, err = reader.NextPart()
+	if err != nil {
+		t.Fatalf("file part: %v", err)
+	}
+
+	if v, _ := io.ReadAll(part); part.FileName() != "report.txt" || string(v) != "hello" {
+		t.Errorf("file = %s:%s, want report.txt:hello", part.FileName(), v)
+	}
+
+	if v := part.Header.Get("Content-Type"); v != "text/plain" {
+		t.Errorf("file content type = %s, want text/plain", v)
+	}
+}
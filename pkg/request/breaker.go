@@ -0,0 +1,339 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var ErrBreakerOpen = errors.New("circuit breaker open")
+
+// Breaker states.
+const (
+	BreakerClosed   = "closed"
+	BreakerHalfOpen = "half-open"
+	BreakerOpen     = "open"
+)
+
+// BreakerSettings are shared thresholds of all breakers.
+type BreakerSettings struct {
+	// FailureRate opens the breaker when failures/requests reach it, between 0 and 1.
+	FailureRate float64
+	// MinRequests in the window before checking failure rate.
+	MinRequests int
+	// Window is period of counting requests in closed state.
+	Window time.Duration
+	// OpenDuration is waiting time before half-open.
+	OpenDuration time.Duration
+	// HalfOpenProbes is number of successful probes to close the breaker.
+	HalfOpenProbes int
+}
+
+var DefaultBreakerSettings = BreakerSettings{
+	FailureRate:    0.5, //nolint:gomnd // half of requests
+	MinRequests:    10,  //nolint:gomnd // enough requests to decide
+	Window:         time.Minute,
+	OpenDuration:   30 * time.Second, //nolint:gomnd // default open duration
+	HalfOpenProbes: 1,
+}
+
+// Validate checks thresholds, breaker cannot close again with zero probes.
+func (s BreakerSettings) Validate() error {
+	if s.FailureRate <= 0 || s.FailureRate > 1 {
+		return fmt.Errorf("breaker failure_rate should be between 0 and 1, got %v", s.FailureRate)
+	}
+
+	if s.MinRequests <= 0 {
+		return fmt.Errorf("breaker min_requests should be greater than zero, got %d", s.MinRequests)
+	}
+
+	if s.Window <= 0 {
+		return fmt.Errorf("breaker window should be greater than zero, got %v", s.Window)
+	}
+
+	if s.OpenDuration <= 0 {
+		return fmt.Errorf("breaker open_duration should be greater than zero, got %v", s.OpenDuration)
+	}
+
+	if s.HalfOpenProbes <= 0 {
+		return fmt.Errorf("breaker half_open_probes should be greater than zero, got %d", s.HalfOpenProbes)
+	}
+
+	return nil
+}
+
+// BreakerState is snapshot of a breaker.
+type BreakerState struct {
+	Name      string     `json:"name"`
+	State     string     `json:"state"`
+	Requests  int        `json:"requests"`
+	Failures  int        `json:"failures"`
+	OpenUntil *time.Time `json:"open_until,omitempty"`
+}
+
+// Breaker is a circuit breaker of one upstream.
+type Breaker struct {
+	name     string
+	settings BreakerSettings
+
+	state       string
+	requests    int
+	failures    int
+	windowStart time.Time
+	openUntil   time.Time
+	probes      int
+	successes   int
+
+	mutex sync.Mutex
+}
+
+// Allow returns ErrBreakerOpen when requests should fail fast.
+func (b *Breaker) Allow() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+
+	switch b.state {
+	case BreakerOpen:
+		if now.Before(b.openUntil) {
+			return fmt.Errorf("%w: %s", ErrBreakerOpen, b.name)
+		}
+
+		b.state = BreakerHalfOpen
+		b.probes = 0
+		b.successes = 0
+
+		log.Info().Str("breaker", b.name).Msg("circuit breaker half-open")
+
+		fallthrough
+	case BreakerHalfOpen:
+		if b.probes >= b.settings.HalfOpenProbes {
+			return fmt.Errorf("%w: %s", ErrBreakerOpen, b.name)
+		}
+
+		b.probes++
+	default:
+		if now.Sub(b.windowStart) > b.settings.Window {
+			b.windowStart = now
+			b.requests = 0
+			b.failures = 0
+		}
+	}
+
+	return nil
+}
+
+// Done records result of an allowed request.
+func (b *Breaker) Done(success bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case BreakerHalfOpen:
+		if !success {
+			b.open()
+
+			return
+		}
+
+		b.successes++
+		if b.successes >= b.settings.HalfOpenProbes {
+			b.reset()
+
+			log.Info().Str("breaker", b.name).Msg("circuit breaker closed")
+		}
+	case BreakerOpen:
+		// result of a request started before open
+	default:
+		b.requests++
+		if !success {
+			b.failures++
+		}
+
+		if b.requests >= b.settings.MinRequests &&
+			float64(b.failures)/float64(b.requests) >= b.settings.FailureRate {
+			b.open()
+		}
+	}
+}
+
+// Cancel releases an allowed request without result, caller gave up before the upstream answered.
+func (b *Breaker) Cancel() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	// let another probe try
+	if b.state == BreakerHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
+
+// Reset closes the breaker.
+func (b *Breaker) Reset() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.reset()
+}
+
+func (b *Breaker) open() {
+	b.state = BreakerOpen
+	b.openUntil = time.Now().Add(b.settings.OpenDuration)
+
+	log.Warn().Str("breaker", b.name).Msgf("circuit breaker open until %s", b.openUntil.Format(time.RFC3339))
+}
+
+func (b *Breaker) reset() {
+	b.state = BreakerClosed
+	b.requests = 0
+	b.failures = 0
+	b.probes = 0
+	b.successes = 0
+	b.windowStart = time.Now()
+	b.openUntil = time.Time{}
+}
+
+// State returns snapshot of the breaker.
+func (b *Breaker) State() BreakerState {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	state := BreakerState{
+		Name:     b.name,
+		State:    b.state,
+		Requests: b.requests,
+		Failures: b.failures,
+	}
+
+	if b.state == BreakerOpen {
+		openUntil := b.openUntil
+		state.OpenUntil = &openUntil
+	}
+
+	return state
+}
+
+// Breakers holds shared breakers with names.
+type Breakers struct {
+	Settings BreakerSettings
+
+	breakers map[string]*Breaker
+	rejected metric.Int64Counter
+	once     sync.Once
+	mutex    sync.RWMutex
+}
+
+var GlobalBreakers = &Breakers{
+	Settings: DefaultBreakerSettings,
+	breakers: map[string]*Breaker{},
+}
+
+// Get returns breaker of the name, creates if not exist.
+func (r *Breakers) Get(name string) *Breaker {
+	r.once.Do(r.registerMetrics)
+
+	r.mutex.RLock()
+	b, ok := r.breakers[name]
+	r.mutex.RUnlock()
+
+	if ok {
+		return b
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if b, ok := r.breakers[name]; ok {
+		return b
+	}
+
+	b = &Breaker{
+		name:        name,
+		settings:    r.Settings,
+		state:       BreakerClosed,
+		windowStart: time.Now(),
+	}
+
+	r.breakers[name] = b
+
+	return b
+}
+
+// Find returns breaker if exist.
+func (r *Breakers) Find(name string) (*Breaker, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	b, ok := r.breakers[name]
+
+	return b, ok
+}
+
+// States returns snapshot of all breakers sorted by name.
+func (r *Breakers) States() []BreakerState {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	states := make([]BreakerState, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		states = append(states, b.State())
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Name < states[j].Name
+	})
+
+	return states
+}
+
+// Rejected counts fail fast requests.
+func (r *Breakers) Rejected(ctx context.Context, name string) {
+	if r.rejected != nil {
+		r.rejected.Add(ctx, 1, metric.WithAttributes(attribute.String("name", name)))
+	}
+}
+
+func (r *Breakers) registerMetrics() {
+	meter := otel.GetMeterProvider().Meter("chore")
+
+	rejected, err := meter.Int64Counter("chore_breaker_rejected_total",
+		metric.WithDescription("requests rejected by open circuit breaker"))
+	if err != nil {
+		log.Warn().Err(err).Msg("breaker metric cannot register")
+	}
+
+	r.rejected = rejected
+
+	if _, err := meter.Int64ObservableGauge("chore_breaker_state",
+		metric.WithDescription("circuit breaker state, 0 closed, 1 half-open, 2 open"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			for _, state := range r.States() {
+				o.Observe(breakerStateValue(state.State), metric.WithAttributes(attribute.String("name", state.Name)))
+			}
+
+			return nil
+		}),
+	); err != nil {
+		log.Warn().Err(err).Msg("breaker metric cannot register")
+	}
+}
+
+func breakerStateValue(state string) int64 {
+	switch state {
+	case BreakerHalfOpen:
+		return 1
+	case BreakerOpen:
+		return 2 //nolint:gomnd // open state
+	}
+
+	return 0
+}
@@ -0,0 +1,106 @@
+package request
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	breakers := &Breakers{
+		Settings: BreakerSettings{
+			FailureRate:    0.5,
+			MinRequests:    4,
+			Window:         time.Minute,
+			OpenDuration:   50 * time.Millisecond,
+			HalfOpenProbes: 1,
+		},
+		breakers: map[string]*Breaker{},
+	}
+
+	b := breakers.Get("example.com")
+
+	for _, success := range []bool{true, false, true, false} {
+		if err := b.Allow(); err != nil {
+			t.Fatalf("Allow() unexpected error = %v", err)
+		}
+
+		b.Done(success)
+	}
+
+	if got := b.State().State; got != BreakerOpen {
+		t.Fatalf("state = %s, want %s", got, BreakerOpen)
+	}
+
+	if err := b.Allow(); !errors.Is(err, ErrBreakerOpen) {
+		t.Fatalf("Allow() error = %v, want %v", err, ErrBreakerOpen)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	// one probe allowed in half-open
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow() probe unexpected error = %v", err)
+	}
+
+	if err := b.Allow(); !errors.Is(err, ErrBreakerOpen) {
+		t.Fatalf("Allow() second probe error = %v, want %v", err, ErrBreakerOpen)
+	}
+
+	// canceled probe not decides, another probe can try
+	b.Cancel()
+
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow() probe after cancel unexpected error = %v", err)
+	}
+
+	// failed probe opens again
+	b.Done(false)
+
+	if got := b.State().State; got != BreakerOpen {
+		t.Fatalf("state = %s, want %s", got, BreakerOpen)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow() probe unexpected error = %v", err)
+	}
+
+	b.Done(true)
+
+	if got := b.State().State; got != BreakerClosed {
+		t.Fatalf("state = %s, want %s", got, BreakerClosed)
+	}
+
+	if got := breakers.States(); len(got) != 1 || got[0].Name != "example.com" {
+		t.Fatalf("States() = %v", got)
+	}
+}
+
+func TestBreakerSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings func(s *BreakerSettings)
+		wantErr  bool
+	}{
+		{name: "default", settings: func(s *BreakerSettings) {}},
+		{name: "zero probes", settings: func(s *BreakerSettings) { s.HalfOpenProbes = 0 }, wantErr: true},
+		{name: "zero window", settings: func(s *BreakerSettings) { s.Window = 0 }, wantErr: true},
+		{name: "zero open duration", settings: func(s *BreakerSettings) { s.OpenDuration = 0 }, wantErr: true},
+		{name: "zero min requests", settings: func(s *BreakerSettings) { s.MinRequests = 0 }, wantErr: true},
+		{name: "zero failure rate", settings: func(s *BreakerSettings) { s.FailureRate = 0 }, wantErr: true},
+		{name: "failure rate above one", settings: func(s *BreakerSettings) { s.FailureRate = 1.5 }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := DefaultBreakerSettings
+			tt.settings(&settings)
+
+			if err := settings.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("BreakerSettings.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,339 @@
+package request
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Cache results for logs and metrics.
+const (
+	CacheHit         = "hit"
+	CacheMiss        = "miss"
+	CacheRevalidated = "revalidated"
+)
+
+// CacheRevalidateKeep is time to keep expired entries which have validators.
+var CacheRevalidateKeep = time.Hour
+
+// CacheMaxEntries is default limit of the memory cache entries.
+var CacheMaxEntries = 10000
+
+// CacheEntry is a stored response.
+type CacheEntry struct {
+	StatusCode   int
+	Header       http.Header
+	Body         []byte
+	ETag         string
+	LastModified string
+	// Revalidate always asks upstream with validators, set by no-cache.
+	Revalidate bool
+	ExpiresAt  time.Time
+	KeepUntil  time.Time
+}
+
+// Fresh returns true if entry usable without asking upstream.
+func (e *CacheEntry) Fresh(now time.Time) bool {
+	return !e.Revalidate && now.Before(e.ExpiresAt)
+}
+
+func (e *CacheEntry) response() *ClientResponse {
+	return &ClientResponse{
+		Body:       e.Body,
+		StatusCode: e.StatusCode,
+		Header:     e.Header.Clone(),
+	}
+}
+
+func (e *CacheEntry) hasValidator() bool {
+	return e.ETag != "" || e.LastModified != ""
+}
+
+// CacheStore keeps responses with keys, Get returns nil entry on miss.
+type CacheStore interface {
+	Get(ctx context.Context, key string) (*CacheEntry, error)
+	Set(ctx context.Context, key string, entry *CacheEntry) error
+	// Clean removes entries after keep time.
+	Clean(ctx context.Context) error
+}
+
+// GlobalCache is the shared store of the request caches.
+var GlobalCache CacheStore = NewMemoryCache(CacheMaxEntries)
+
+// CacheConfig enables response cache of the client.
+type CacheConfig struct {
+	Enabled bool
+	TTL     time.Duration
+	// Headers are request header names added to the key.
+	Headers []string
+	// Control honours Cache-Control and ETag/Last-Modified of the responses.
+	Control bool
+}
+
+// MemoryCache is in-memory cache store, least recently used entries are removed after max entries.
+type MemoryCache struct {
+	entries    map[string]*list.Element
+	order      *list.List
+	maxEntries int
+	mutex      sync.Mutex
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewMemoryCache returns cache store, zero max entries is unlimited.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		maxEntries: maxEntries,
+	}
+}
+
+func (c *MemoryCache) Get(_ context.Context, key string) (*CacheEntry, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, nil
+	}
+
+	item, _ := element.Value.(*memoryCacheItem)
+	if time.Now().After(item.entry.KeepUntil) {
+		c.remove(element)
+
+		return nil, nil
+	}
+
+	c.order.MoveToFront(element)
+
+	return item.entry, nil
+}
+
+func (c *MemoryCache) Set(_ context.Context, key string, entry *CacheEntry) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = &memoryCacheItem{key: key, entry: entry}
+		c.order.MoveToFront(element)
+
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&memoryCacheItem{key: key, entry: entry})
+
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+
+	return nil
+}
+
+func (c *MemoryCache) Clean(_ context.Context) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	for _, element := range c.entries {
+		if item, _ := element.Value.(*memoryCacheItem); now.After(item.entry.KeepUntil) {
+			c.remove(element)
+		}
+	}
+
+	return nil
+}
+
+func (c *MemoryCache) remove(element *list.Element) {
+	item, _ := element.Value.(*memoryCacheItem)
+
+	delete(c.entries, item.key)
+	c.order.Remove(element)
+}
+
+// StartCacheClean removes old entries of the global cache periodically.
+func StartCacheClean(ctx context.Context, wg *sync.WaitGroup, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := GlobalCache.Clean(ctx); err != nil {
+					log.Warn().Err(err).Msg("request cache clean failed")
+				}
+			}
+		}
+	}()
+}
+
+// clientIdentity is hash of the credentials of the client, responses not shared between different callers.
+func clientIdentity(cfg Config) string {
+	h := sha256.New()
+
+	if cfg.Auth.Enabled {
+		h.Write([]byte("oauth2:" + cfg.Auth.key() + "\n"))
+	}
+
+	if cfg.Profile.Type != "" {
+		profile, _ := json.Marshal(cfg.Profile)
+		h.Write([]byte("profile:" + cfg.Profile.Name + ":"))
+		h.Write(profile)
+		h.Write([]byte("\n"))
+	}
+
+	if cfg.TLS != nil && cfg.TLS.Cert != "" {
+		h.Write([]byte("cert:" + cfg.TLS.Cert + "\n"))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheKey is hash of client identity, method, url, authorization, selected headers and body.
+func cacheKey(req *http.Request, identity string, headers []string, body Body) string {
+	h := sha256.New()
+
+	h.Write([]byte(identity + "\n" + req.Method + "\n" + req.URL.String() + "\n"))
+
+	if authorization := req.Header.Get("Authorization"); authorization != "" {
+		h.Write([]byte("Authorization:" + authorization + "\n"))
+	}
+
+	names := make([]string, 0, len(headers))
+	for _, name := range headers {
+		names = append(names, http.CanonicalHeaderKey(strings.TrimSpace(name)))
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		h.Write([]byte(name + ":" + strings.Join(req.Header.Values(name), ",") + "\n"))
+	}
+
+	h.Write([]byte(body.Hash()))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// newCacheEntry returns nil if response should not store.
+func newCacheEntry(cfg CacheConfig, resp *ClientResponse) *CacheEntry {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil
+	}
+
+	now := time.Now()
+
+	entry := &CacheEntry{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       resp.Body,
+		ExpiresAt:  now.Add(cfg.TTL),
+	}
+
+	if cfg.Control {
+		directives := cacheControl(resp.Header.Get("Cache-Control"))
+		if _, ok := directives["no-store"]; ok {
+			return nil
+		}
+
+		if _, ok := directives["no-cache"]; ok {
+			entry.Revalidate = true
+		}
+
+		if v, ok := directives["max-age"]; ok {
+			if seconds, err := strconv.Atoi(v); err == nil {
+				entry.ExpiresAt = now.Add(time.Duration(seconds) * time.Second)
+			}
+		}
+
+		entry.ETag = resp.Header.Get("ETag")
+		entry.LastModified = resp.Header.Get("Last-Modified")
+
+		if entry.Revalidate && !entry.hasValidator() {
+			return nil
+		}
+	}
+
+	entry.KeepUntil = entry.ExpiresAt
+	if entry.hasValidator() {
+		entry.KeepUntil = entry.ExpiresAt.Add(CacheRevalidateKeep)
+	}
+
+	if !entry.KeepUntil.After(now) {
+		return nil
+	}
+
+	return entry
+}
+
+// cacheControl parses directives of the Cache-Control header.
+func cacheControl(header string) map[string]string {
+	directives := make(map[string]string)
+
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if key == "" {
+			continue
+		}
+
+		directives[strings.ToLower(key)] = strings.Trim(value, `"`)
+	}
+
+	return directives
+}
+
+var cacheMetric struct {
+	counter metric.Int64Counter
+	once    sync.Once
+}
+
+// cacheResult logs and counts cache usage.
+func cacheResult(ctx context.Context, result, url string) {
+	cacheMetric.once.Do(func() {
+		counter, err := otel.GetMeterProvider().Meter("chore").Int64Counter("chore_request_cache_total",
+			metric.WithDescription("request cache lookups with result hit, miss or revalidated"))
+		if err != nil {
+			log.Warn().Err(err).Msg("request cache metric cannot register")
+		}
+
+		cacheMetric.counter = counter
+	})
+
+	if cacheMetric.counter != nil {
+		cacheMetric.counter.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+	}
+
+	event := log.Ctx(ctx).Info()
+	if result == CacheMiss {
+		event = log.Ctx(ctx).Debug()
+	}
+
+	event.Str("cache", result).Str("url", url).Msg("request cache")
+}
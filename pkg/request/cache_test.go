@@ -0,0 +1,185 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_CallBodyCache(t *testing.T) {
+	GlobalCache = NewMemoryCache(0)
+
+	var calls, notModified atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		if r.URL.Path == "/etag" {
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				notModified.Add(1)
+				w.WriteHeader(http.StatusNotModified)
+
+				return
+			}
+
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Cache-Control", "no-cache")
+		}
+
+		_, _ = w.Write([]byte(r.Header.Get("X-Tenant")))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{Cache: CacheConfig{Enabled: true, TTL: time.Minute, Headers: []string{"x-tenant"}, Control: true}})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	call := func(path, tenant string) string {
+		t.Helper()
+
+		resp, err := c.CallBody(context.TODO(), srv.URL+path, http.MethodGet, map[string]interface{}{"X-Tenant": tenant}, Body{}, CallOptions{})
+		if err != nil {
+			t.Fatalf("Client.CallBody() error = %v", err)
+		}
+
+		return string(resp.Body)
+	}
+
+	if got := call("/", "a"); got != "a" {
+		t.Errorf("body = %s, want a", got)
+	}
+
+	if got := call("/", "a"); got != "a" {
+		t.Errorf("cached body = %s, want a", got)
+	}
+
+	if got := call("/", "b"); got != "b" {
+		t.Errorf("body with other header = %s, want b", got)
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("upstream calls = %d, want 2", got)
+	}
+
+	// no-cache with etag revalidates every time
+	call("/etag", "c")
+
+	if got := call("/etag", "c"); got != "c" {
+		t.Errorf("revalidated body = %s, want c", got)
+	}
+
+	if got := notModified.Load(); got != 1 {
+		t.Errorf("not modified responses = %d, want 1", got)
+	}
+}
+
+func TestClient_CallBodyCacheIdentity(t *testing.T) {
+	GlobalCache = NewMemoryCache(0)
+
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		username, _, _ := r.BasicAuth()
+		if username == "" {
+			username = r.Header.Get("Authorization")
+		}
+
+		_, _ = w.Write([]byte(username))
+	}))
+	defer srv.Close()
+
+	cache := CacheConfig{Enabled: true, TTL: time.Minute}
+
+	call := func(c *Client, headers map[string]interface{}) string {
+		t.Helper()
+
+		resp, err := c.CallBody(context.TODO(), srv.URL, http.MethodGet, headers, Body{}, CallOptions{})
+		if err != nil {
+			t.Fatalf("Client.CallBody() error = %v", err)
+		}
+
+		return string(resp.Body)
+	}
+
+	newClient := func(profile AuthProfile) *Client {
+		t.Helper()
+
+		c, err := NewClient(Config{Cache: cache, Profile: profile})
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+
+		return c
+	}
+
+	alice := newClient(AuthProfile{Name: "alice", Type: AuthBasic, Username: "alice", Password: "secret"})
+	bob := newClient(AuthProfile{Name: "bob", Type: AuthBasic, Username: "bob", Password: "secret"})
+
+	if got := call(alice, nil); got != "alice" {
+		t.Errorf("body = %s, want alice", got)
+	}
+
+	if got := call(alice, nil); got != "alice" {
+		t.Errorf("cached body = %s, want alice", got)
+	}
+
+	// same request with other credentials not use cache of alice
+	if got := call(bob, nil); got != "bob" {
+		t.Errorf("body of other profile = %s, want bob", got)
+	}
+
+	anonymous := newClient(AuthProfile{})
+
+	if got := call(anonymous, map[string]interface{}{"Authorization": "Bearer one"}); got != "Bearer one" {
+		t.Errorf("body = %s, want Bearer one", got)
+	}
+
+	if got := call(anonymous, map[string]interface{}{"Authorization": "Bearer two"}); got != "Bearer two" {
+		t.Errorf("body of other authorization = %s, want Bearer two", got)
+	}
+
+	if got := calls.Load(); got != 4 {
+		t.Errorf("upstream calls = %d, want 4", got)
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(2)
+
+	keep := &CacheEntry{Body: []byte("x"), KeepUntil: time.Now().Add(time.Hour)}
+
+	_ = c.Set(ctx, "a", keep)
+	_ = c.Set(ctx, "b", keep)
+
+	// a is used recently, b is removed
+	if entry, _ := c.Get(ctx, "a"); entry == nil {
+		t.Fatal("Get() a missing")
+	}
+
+	_ = c.Set(ctx, "c", keep)
+
+	if entry, _ := c.Get(ctx, "b"); entry != nil {
+		t.Error("Get() least recently used b not removed")
+	}
+
+	if c.order.Len() != 2 || len(c.entries) != 2 {
+		t.Errorf("entries = %d, want 2", c.order.Len())
+	}
+
+	_ = c.Set(ctx, "d", &CacheEntry{KeepUntil: time.Now().Add(-time.Second)})
+
+	if err := c.Clean(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := c.entries["d"]; ok || c.order.Len() != 1 {
+		t.Errorf("Clean() expired entry kept, entries = %d", c.order.Len())
+	}
+}
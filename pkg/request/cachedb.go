@@ -0,0 +1,93 @@
+package request
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/worldline-go/chore/pkg/models"
+)
+
+// DBCache stores responses in database, shared between instances.
+type DBCache struct {
+	db *gorm.DB
+}
+
+func NewDBCache(db *gorm.DB) *DBCache {
+	return &DBCache{db: db}
+}
+
+func (c *DBCache) Get(ctx context.Context, key string) (*CacheEntry, error) {
+	record := models.RequestCache{}
+
+	result := c.db.WithContext(ctx).Model(&models.RequestCache{}).
+		Where("key = ?", key).Where("keep_until > ?", time.Now()).First(&record)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("cache get: %w", result.Error)
+	}
+
+	entry := &CacheEntry{
+		StatusCode:   record.StatusCode,
+		Body:         record.Body,
+		ETag:         record.ETag,
+		LastModified: record.LastModified,
+		Revalidate:   record.Revalidate,
+		ExpiresAt:    record.ExpiresAt,
+		KeepUntil:    record.KeepUntil,
+	}
+
+	if len(record.Header) != 0 {
+		if err := json.Unmarshal(record.Header, &entry.Header); err != nil {
+			return nil, fmt.Errorf("cache header cannot unmarshal: %w", err)
+		}
+	}
+
+	return entry, nil
+}
+
+func (c *DBCache) Set(ctx context.Context, key string, entry *CacheEntry) error {
+	header, err := json.Marshal(entry.Header)
+	if err != nil {
+		return fmt.Errorf("cache header cannot marshal: %w", err)
+	}
+
+	record := models.RequestCache{
+		Key:          key,
+		StatusCode:   entry.StatusCode,
+		Header:       header,
+		Body:         entry.Body,
+		ETag:         entry.ETag,
+		LastModified: entry.LastModified,
+		Revalidate:   entry.Revalidate,
+		ExpiresAt:    entry.ExpiresAt,
+		KeepUntil:    entry.KeepUntil,
+	}
+
+	result := c.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		UpdateAll: true,
+	}).Create(&record)
+	if result.Error != nil {
+		return fmt.Errorf("cache set: %w", result.Error)
+	}
+
+	return nil
+}
+
+func (c *DBCache) Clean(ctx context.Context) error {
+	result := c.db.WithContext(ctx).Where("keep_until < ?", time.Now()).Delete(&models.RequestCache{})
+	if result.Error != nil {
+		return fmt.Errorf("cache clean: %w", result.Error)
+	}
+
+	return nil
+}
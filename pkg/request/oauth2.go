@@ -0,0 +1,403 @@
+package request
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+)
+
+// OAuth2 grant types.
+const (
+	GrantClientCredentials = "client_credentials"
+	GrantPassword          = "password"
+	GrantRefreshToken      = "refresh_token"
+	GrantJWTBearer         = "jwt_bearer"
+)
+
+const (
+	grantTypeJWTBearer      = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	clientAssertionTypeJWT  = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	assertionExpireDuration = 5 * time.Minute
+)
+
+// TokenRequestTimeout is timeout of one token request.
+var TokenRequestTimeout = 30 * time.Second
+
+// key returns hash of the full config.
+func (c AuthConfig) key() string {
+	b, _ := json.Marshal(c)
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func (c AuthConfig) grantType() string {
+	if c.GrantType == "" {
+		return GrantClientCredentials
+	}
+
+	return c.GrantType
+}
+
+// scopes combines scopes list and space or comma separated scope.
+func (c AuthConfig) scopes() []string {
+	scopes := append([]string{}, c.Scopes...)
+
+	return append(scopes, strings.Fields(strings.ReplaceAll(c.Scope, ",", " "))...)
+}
+
+func (c AuthConfig) validate() error {
+	if c.ClientID == "" {
+		return ErrClientIDNotFound
+	}
+
+	if c.TokenURL == "" {
+		return fmt.Errorf("oauth2 token url is empty")
+	}
+
+	switch c.grantType() {
+	case GrantClientCredentials:
+	case GrantPassword:
+		if c.Username == "" {
+			return fmt.Errorf("oauth2 password grant needs username")
+		}
+	case GrantRefreshToken:
+		if c.RefreshToken == "" {
+			return fmt.Errorf("oauth2 refresh token grant needs refresh token")
+		}
+	case GrantJWTBearer:
+		if c.PrivateKey == "" {
+			return fmt.Errorf("oauth2 jwt bearer grant needs private key")
+		}
+	default:
+		return fmt.Errorf("oauth2 grant type %q not supported", c.GrantType)
+	}
+
+	switch c.AuthStyle {
+	case "", "params", "header":
+	default:
+		return fmt.Errorf("oauth2 auth style %q not supported", c.AuthStyle)
+	}
+
+	return nil
+}
+
+// tokenSource caches token of one config and refreshes when expired.
+type tokenSource struct {
+	cfg AuthConfig
+	// transport is last used transport of the clients, token requests use same tls and proxy.
+	transport http.RoundTripper
+
+	token       *oauth2.Token
+	lastRefresh time.Time
+	lastError   error
+	lastErrorAt time.Time
+
+	// mutex guards token state, not held during token request to not block status.
+	mutex sync.Mutex
+	// refreshMutex allows one token request at a time.
+	refreshMutex sync.Mutex
+}
+
+func newTokenSource(cfg AuthConfig) *tokenSource {
+	return &tokenSource{
+		cfg: cfg,
+	}
+}
+
+// Token returns cached token or gets new one with the transport of the client.
+func (s *tokenSource) Token(ctx context.Context, transport http.RoundTripper) (*oauth2.Token, error) {
+	if token := s.validToken(); token != nil {
+		return token, nil
+	}
+
+	s.refreshMutex.Lock()
+	defer s.refreshMutex.Unlock()
+
+	// refreshed while waiting
+	if token := s.validToken(); token != nil {
+		return token, nil
+	}
+
+	return s.refresh(ctx, transport)
+}
+
+// Refresh gets new token even current one is valid.
+func (s *tokenSource) Refresh(ctx context.Context) error {
+	s.refreshMutex.Lock()
+	defer s.refreshMutex.Unlock()
+
+	_, err := s.refresh(ctx, nil)
+
+	return err
+}
+
+func (s *tokenSource) validToken() *oauth2.Token {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.token.Valid() {
+		return s.token
+	}
+
+	return nil
+}
+
+// refresh requests new token, caller holds refreshMutex.
+// Nil transport uses last transport of the clients.
+func (s *tokenSource) refresh(ctx context.Context, transport http.RoundTripper) (*oauth2.Token, error) {
+	s.mutex.Lock()
+	current := s.token
+
+	if transport != nil {
+		s.transport = transport
+	} else {
+		transport = s.transport
+	}
+
+	s.mutex.Unlock()
+
+	client := &http.Client{Transport: transport, Timeout: TokenRequestTimeout}
+
+	var (
+		token *oauth2.Token
+		err   error
+	)
+
+	// use refresh token of previous token first
+	if current != nil && current.RefreshToken != "" && s.cfg.grantType() != GrantClientCredentials {
+		token, err = s.request(ctx, client, url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {current.RefreshToken},
+		})
+	}
+
+	if token == nil {
+		token, err = s.grant(ctx, client)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err != nil {
+		s.lastError = err
+		s.lastErrorAt = time.Now()
+
+		return nil, err
+	}
+
+	// keep refresh token when server not rotate it
+	if token.RefreshToken == "" && current != nil {
+		token.RefreshToken = current.RefreshToken
+	}
+
+	s.token = token
+	s.lastRefresh = time.Now()
+	s.lastError = nil
+
+	return token, nil
+}
+
+func (s *tokenSource) grant(ctx context.Context, client *http.Client) (*oauth2.Token, error) {
+	values := url.Values{}
+
+	switch s.cfg.grantType() {
+	case GrantClientCredentials:
+		values.Set("grant_type", "client_credentials")
+	case GrantPassword:
+		values.Set("grant_type", "password")
+		values.Set("username", s.cfg.Username)
+		values.Set("password", s.cfg.Password)
+	case GrantRefreshToken:
+		values.Set("grant_type", "refresh_token")
+		values.Set("refresh_token", s.cfg.RefreshToken)
+	case GrantJWTBearer:
+		subject := s.cfg.Subject
+		if subject == "" {
+			subject = s.cfg.ClientID
+		}
+
+		audience := s.cfg.Audience
+		if audience == "" {
+			audience = s.cfg.TokenURL
+		}
+
+		assertion, err := s.assertion(subject, audience)
+		if err != nil {
+			return nil, err
+		}
+
+		values.Set("grant_type", grantTypeJWTBearer)
+		values.Set("assertion", assertion)
+	}
+
+	return s.request(ctx, client, values)
+}
+
+// request sends token request with client authentication and extra params.
+func (s *tokenSource) request(ctx context.Context, client *http.Client, values url.Values) (*oauth2.Token, error) {
+	if scopes := s.cfg.scopes(); len(scopes) > 0 {
+		values.Set("scope", strings.Join(scopes, " "))
+	}
+
+	if s.cfg.Audience != "" {
+		values.Set("audience", s.cfg.Audience)
+	}
+
+	if s.cfg.Resource != "" {
+		values.Set("resource", s.cfg.Resource)
+	}
+
+	for k, v := range s.cfg.Params {
+		values.Set(k, v)
+	}
+
+	useHeader := false
+
+	// client secret in basic auth by default like client_secret_basic
+	switch {
+	case s.cfg.PrivateKey != "" && s.cfg.grantType() != GrantJWTBearer:
+		assertion, err := s.assertion(s.cfg.ClientID, s.cfg.TokenURL)
+		if err != nil {
+			return nil, err
+		}
+
+		values.Set("client_id", s.cfg.ClientID)
+		values.Set("client_assertion_type", clientAssertionTypeJWT)
+		values.Set("client_assertion", assertion)
+	case s.cfg.AuthStyle == "params" || s.cfg.ClientSecret == "":
+		// public clients without secret only send client id
+		values.Set("client_id", s.cfg.ClientID)
+
+		if s.cfg.ClientSecret != "" {
+			values.Set("client_secret", s.cfg.ClientSecret)
+		}
+	default:
+		useHeader = true
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.TokenURL, strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("oauth2 token request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	if useHeader {
+		req.SetBasicAuth(url.QueryEscape(s.cfg.ClientID), url.QueryEscape(s.cfg.ClientSecret))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oauth2 token request: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20)) //nolint:gomnd // 1MB token response
+	if err != nil {
+		return nil, fmt.Errorf("oauth2 token response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("oauth2 token response status %d: %s", resp.StatusCode, body)
+	}
+
+	var tokenResponse struct {
+		AccessToken  string      `json:"access_token"`
+		TokenType    string      `json:"token_type"`
+		RefreshToken string      `json:"refresh_token"`
+		ExpiresIn    json.Number `json:"expires_in"`
+	}
+
+	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		return nil, fmt.Errorf("oauth2 token response cannot parse: %w", err)
+	}
+
+	if tokenResponse.AccessToken == "" {
+		return nil, errors.New("oauth2 token response has no access token")
+	}
+
+	token := &oauth2.Token{
+		AccessToken:  tokenResponse.AccessToken,
+		TokenType:    tokenResponse.TokenType,
+		RefreshToken: tokenResponse.RefreshToken,
+	}
+
+	if expiresIn, err := tokenResponse.ExpiresIn.Int64(); err == nil && expiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+
+	return token, nil
+}
+
+// assertion returns signed jwt with the private key, RSA keys use RS256 and EC keys ES256.
+func (s *tokenSource) assertion(subject, audience string) (string, error) {
+	var (
+		method jwt.SigningMethod
+		key    interface{}
+		err    error
+	)
+
+	if key, err = jwt.ParseRSAPrivateKeyFromPEM([]byte(s.cfg.PrivateKey)); err == nil {
+		method = jwt.SigningMethodRS256
+	} else if key, err = jwt.ParseECPrivateKeyFromPEM([]byte(s.cfg.PrivateKey)); err == nil {
+		method = jwt.SigningMethodES256
+	} else {
+		return "", fmt.Errorf("oauth2 private key should be RSA or EC PEM: %w", err)
+	}
+
+	now := time.Now()
+
+	token := jwt.NewWithClaims(method, jwt.RegisteredClaims{
+		Issuer:    s.cfg.ClientID,
+		Subject:   subject,
+		Audience:  jwt.ClaimStrings{audience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(assertionExpireDuration)),
+		ID:        uuid.NewString(),
+	})
+
+	if s.cfg.KeyID != "" {
+		token.Header["kid"] = s.cfg.KeyID
+	}
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		return "", fmt.Errorf("oauth2 assertion cannot sign: %w", err)
+	}
+
+	return signed, nil
+}
+
+// oauth2Transport sets token of the source to requests.
+type oauth2Transport struct {
+	source *tokenSource
+	base   http.RoundTripper
+}
+
+func (t *oauth2Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token(req.Context(), t.base)
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	token.SetAuthHeader(req)
+
+	return t.base.RoundTrip(req) //nolint:wrapcheck // transport error
+}
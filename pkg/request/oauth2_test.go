@@ -0,0 +1,288 @@
+package request
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestRegistry_OAuth2(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+
+	var tokenRequests int
+
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		token := r.Form.Get("grant_type") + ":" + r.Form.Get("scope") + ":" + r.Form.Get("audience")
+
+		switch r.Form.Get("grant_type") {
+		case "password":
+			if r.Form.Get("username") != "user" || r.Form.Get("password") != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+		case "client_credentials":
+			if assertion := r.Form.Get("client_assertion"); assertion != "" {
+				if _, err := jwt.Parse(assertion, func(*jwt.Token) (interface{}, error) { return &key.PublicKey, nil }); err != nil {
+					t.Errorf("client assertion error = %v", err)
+				}
+
+				token += ":assertion"
+			}
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  token,
+			"token_type":    "Bearer",
+			"refresh_token": "refresh",
+			"expires_in":    300,
+		})
+	}))
+	defer tokenSrv.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer srv.Close()
+
+	reg := InitGlobalRegistry(context.Background())
+
+	tests := []struct {
+		name string
+		cfg  AuthConfig
+		want string
+	}{
+		{
+			name: "client credentials",
+			cfg:  AuthConfig{Enabled: true, ClientID: "chore", TokenURL: tokenSrv.URL, Scope: "a, b", Audience: "api"},
+			want: "Bearer client_credentials:a b:api",
+		},
+		{
+			name: "same client other scope",
+			cfg:  AuthConfig{Enabled: true, ClientID: "chore", TokenURL: tokenSrv.URL, Scopes: []string{"c"}},
+			want: "Bearer client_credentials:c:",
+		},
+		{
+			name: "password",
+			cfg:  AuthConfig{Enabled: true, GrantType: GrantPassword, ClientID: "chore", TokenURL: tokenSrv.URL, Username: "user", Password: "pass"},
+			want: "Bearer password::",
+		},
+		{
+			name: "private key client assertion",
+			cfg:  AuthConfig{Enabled: true, ClientID: "chore", TokenURL: tokenSrv.URL, PrivateKey: keyPEM},
+			want: "Bearer client_credentials:::assertion",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(Config{Auth: tt.cfg})
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			for i := 0; i < 2; i++ {
+				resp, err := c.Call(context.TODO(), srv.URL, http.MethodGet, nil, nil)
+				if err != nil {
+					t.Fatalf("Client.Call() error = %v", err)
+				}
+
+				if string(resp.Body) != tt.want {
+					t.Errorf("Authorization = %s, want %s", resp.Body, tt.want)
+				}
+			}
+		})
+	}
+
+	// tokens cached per config
+	if tokenRequests != len(tests) {
+		t.Errorf("token requests = %d, want %d", tokenRequests, len(tests))
+	}
+
+	statuses := reg.Status()
+	if len(statuses) != len(tests) {
+		t.Fatalf("Status() = %d clients, want %d", len(statuses), len(tests))
+	}
+
+	// password grant refreshes with refresh token
+	for _, status := range statuses {
+		if status.GrantType != GrantPassword {
+			continue
+		}
+
+		if _, err := reg.Refresh(context.TODO(), status.Key); err != nil {
+			t.Errorf("Refresh() error = %v", err)
+		}
+	}
+
+	if tokenRequests != len(tests)+1 {
+		t.Errorf("token requests after refresh = %d, want %d", tokenRequests, len(tests)+1)
+	}
+
+	if _, err := reg.Refresh(context.TODO(), "unknown"); err == nil {
+		t.Errorf("Refresh() unknown key should fail")
+	}
+
+	_, err = NewClient(Config{Auth: AuthConfig{Enabled: true, ClientID: "chore", TokenURL: tokenSrv.URL, GrantType: "device"}})
+	if err == nil {
+		t.Errorf("NewClient() unsupported grant should fail")
+	}
+}
+
+func TestRegistry_OAuth2StatusDuringRefresh(t *testing.T) {
+	requested := make(chan struct{})
+	release := make(chan struct{})
+
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(requested)
+		<-release
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "token",
+			"expires_in":   300,
+		})
+	}))
+	defer tokenSrv.Close()
+
+	reg := InitGlobalRegistry(context.Background())
+
+	cfg := AuthConfig{Enabled: true, ClientID: "chore", ClientSecret: "secret", TokenURL: tokenSrv.URL}
+	if _, err := reg.AddService(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses := reg.Status()
+	if len(statuses) != 1 {
+		t.Fatalf("Status() = %d clients, want 1", len(statuses))
+	}
+
+	// key of the status not derived from the config with secrets
+	if statuses[0].Key == "" || statuses[0].Key == cfg.key() {
+		t.Errorf("Status() key = %q, want random id", statuses[0].Key)
+	}
+
+	refreshed := make(chan error, 1)
+
+	go func() {
+		_, err := reg.Refresh(context.Background(), statuses[0].Key)
+		refreshed <- err
+	}()
+
+	<-requested
+
+	done := make(chan []ServiceStatus, 1)
+
+	go func() { done <- reg.Status() }()
+
+	select {
+	case got := <-done:
+		if got[0].HasToken {
+			t.Errorf("Status() has token before refresh finished")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Status() blocked by token request")
+	}
+
+	close(release)
+
+	if err := <-refreshed; err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	if got := reg.Status(); !got[0].HasToken || got[0].Key != statuses[0].Key {
+		t.Errorf("Status() after refresh = %+v", got[0])
+	}
+}
+
+func TestRegistry_OAuth2AuthStyle(t *testing.T) {
+	tokenSrv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		style := "params:" + r.PostForm.Get("client_id") + ":" + r.PostForm.Get("client_secret")
+		if user, pass, ok := r.BasicAuth(); ok {
+			style = "header:" + user + ":" + pass
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": style,
+			"expires_in":   300,
+		})
+	}))
+	defer tokenSrv.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer srv.Close()
+
+	InitGlobalRegistry(context.Background())
+
+	tests := []struct {
+		name string
+		cfg  AuthConfig
+		want string
+	}{
+		{
+			name: "default header",
+			cfg:  AuthConfig{Enabled: true, ClientID: "chore", ClientSecret: "secret", TokenURL: tokenSrv.URL},
+			want: "Bearer header:chore:secret",
+		},
+		{
+			name: "params",
+			cfg:  AuthConfig{Enabled: true, ClientID: "chore", ClientSecret: "secret", TokenURL: tokenSrv.URL, AuthStyle: "params"},
+			want: "Bearer params:chore:secret",
+		},
+		{
+			name: "public client",
+			cfg:  AuthConfig{Enabled: true, ClientID: "chore", TokenURL: tokenSrv.URL},
+			want: "Bearer params:chore:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// token server certificate is trusted only with the client transport
+			c, err := NewClient(Config{Auth: tt.cfg, SkipVerify: true})
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			resp, err := c.Call(context.TODO(), srv.URL, http.MethodGet, nil, nil)
+			if err != nil {
+				t.Fatalf("Client.Call() error = %v", err)
+			}
+
+			if string(resp.Body) != tt.want {
+				t.Errorf("Authorization = %s, want %s", resp.Body, tt.want)
+			}
+		})
+	}
+}
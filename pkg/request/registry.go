@@ -4,15 +4,18 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
-	"github.com/worldline-go/auth"
-	"github.com/worldline-go/auth/providers"
 )
 
-var ErrClientIDNotFound = errors.New("client id not found")
+var (
+	ErrClientIDNotFound = errors.New("client id not found")
+	ErrServiceNotFound  = errors.New("oauth2 client not found")
+)
 
 var (
 	DefaultTimeDuration   = 10 * time.Minute
@@ -20,29 +23,48 @@ var (
 )
 
 type Registry struct {
-	clientIDs map[string]Auth
-	timers    map[string]Timer
-	ctx       context.Context //nolint:containedctx // application context
+	services map[string]*service
+	timers   map[string]Timer
+	ctx      context.Context //nolint:containedctx // application context
 
 	mutex sync.RWMutex
 }
 
 var GlobalRegistry *Registry
 
-type Auth struct {
+type service struct {
+	// id is random key of the service in status, config key has secrets.
+	id           string
+	source       *tokenSource
+	names        map[string]struct{}
 	RoundTripper func(_ context.Context, transport http.RoundTripper) (http.RoundTripper, error)
-	Cancel       context.CancelFunc
 }
 
 type Timer struct {
-	Timer    *time.Timer
-	ClientID string
+	Timer *time.Timer
+	Key   string
+}
+
+// ServiceStatus is state of a cached oauth2 client.
+type ServiceStatus struct {
+	// Key is random id of the client in this instance, used to refresh.
+	Key         string     `json:"key"`
+	Names       []string   `json:"names"`
+	ClientID    string     `json:"client_id"`
+	TokenURL    string     `json:"token_url"`
+	GrantType   string     `json:"grant_type"`
+	Scopes      []string   `json:"scopes"`
+	HasToken    bool       `json:"has_token"`
+	Expiry      *time.Time `json:"expiry,omitempty"`
+	LastRefresh *time.Time `json:"last_refresh,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
 }
 
 func InitGlobalRegistry(ctx context.Context) *Registry {
 	reg := &Registry{}
 
-	reg.clientIDs = map[string]Auth{}
+	reg.services = map[string]*service{}
 	reg.timers = map[string]Timer{}
 	reg.ctx = ctx
 
@@ -51,52 +73,140 @@ func InitGlobalRegistry(ctx context.Context) *Registry {
 	return GlobalRegistry
 }
 
+// AddService returns shared round tripper of the config, same configs share the token.
 func (r *Registry) AddService(cfg AuthConfig) (func(_ context.Context, transport http.RoundTripper) (http.RoundTripper, error), error) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	if cfg.ClientID == "" {
-		return nil, ErrClientIDNotFound
+	if err := cfg.validate(); err != nil {
+		return nil, err
 	}
 
-	if _, ok := r.clientIDs[cfg.ClientID]; ok {
-		r.resetTimer(cfg.ClientID)
-		// log.Debug().Msgf("using client id: %s", cfg.ClientID)
+	key := cfg.key()
+
+	if svc, ok := r.services[key]; ok {
+		r.resetTimer(key)
+
+		if cfg.Name != "" {
+			svc.names[cfg.Name] = struct{}{}
+		}
 
-		return r.clientIDs[cfg.ClientID].RoundTripper, nil
+		return svc.RoundTripper, nil
 	}
 
-	log.Debug().Msgf("adding client id: %s", cfg.ClientID)
+	log.Debug().Msgf("adding oauth2 client id: %s", cfg.ClientID)
+
+	source := newTokenSource(cfg)
+
+	svc := &service{
+		id:     uuid.NewString(),
+		source: source,
+		names:  map[string]struct{}{},
+		RoundTripper: func(_ context.Context, transport http.RoundTripper) (http.RoundTripper, error) {
+			return &oauth2Transport{source: source, base: transport}, nil
+		},
+	}
 
-	provider := auth.Provider{Generic: &providers.Generic{
-		ClientID:     cfg.ClientID,
-		ClientSecret: cfg.ClientSecret,
-		TokenURL:     cfg.TokenURL,
-		Scopes:       cfg.Scopes,
-	}}
-	providerActive := provider.ActiveProvider()
+	if cfg.Name != "" {
+		svc.names[cfg.Name] = struct{}{}
+	}
 
-	ctx, cancel := context.WithCancel(r.ctx)
-	shared, err := providerActive.NewOauth2Shared(ctx)
-	if err != nil {
-		cancel()
+	r.services[key] = svc
 
-		return nil, err //nolint:wrapcheck // no need
+	r.timers[key] = Timer{
+		Timer: time.NewTimer(DefaultTimeDuration),
+		Key:   key,
 	}
 
-	timer := time.NewTimer(DefaultTimeDuration)
+	return svc.RoundTripper, nil
+}
+
+// Status returns cached clients sorted by client id.
+func (r *Registry) Status() []ServiceStatus {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	statuses := make([]ServiceStatus, 0, len(r.services))
 
-	r.clientIDs[cfg.ClientID] = Auth{
-		RoundTripper: shared.RoundTripper,
-		Cancel:       cancel,
+	for _, svc := range r.services {
+		statuses = append(statuses, svc.status())
 	}
 
-	r.timers[cfg.ClientID] = Timer{
-		Timer:    timer,
-		ClientID: cfg.ClientID,
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].ClientID == statuses[j].ClientID {
+			return statuses[i].Key < statuses[j].Key
+		}
+
+		return statuses[i].ClientID < statuses[j].ClientID
+	})
+
+	return statuses
+}
+
+// Refresh gets new token of the cached client, key is the id in the status.
+func (r *Registry) Refresh(ctx context.Context, key string) (ServiceStatus, error) {
+	svc := r.findService(key)
+	if svc == nil {
+		return ServiceStatus{}, ErrServiceNotFound
 	}
 
-	return r.clientIDs[cfg.ClientID].RoundTripper, nil
+	err := svc.source.Refresh(ctx)
+
+	return svc.status(), err
+}
+
+func (r *Registry) findService(id string) *service {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, svc := range r.services {
+		if svc.id == id {
+			return svc
+		}
+	}
+
+	return nil
+}
+
+func (s *service) status() ServiceStatus {
+	s.source.mutex.Lock()
+	defer s.source.mutex.Unlock()
+
+	cfg := s.source.cfg
+
+	status := ServiceStatus{
+		Key:       s.id,
+		Names:     make([]string, 0, len(s.names)),
+		ClientID:  cfg.ClientID,
+		TokenURL:  cfg.TokenURL,
+		GrantType: cfg.grantType(),
+		Scopes:    cfg.scopes(),
+		HasToken:  s.source.token != nil,
+	}
+
+	for name := range s.names {
+		status.Names = append(status.Names, name)
+	}
+
+	sort.Strings(status.Names)
+
+	if s.source.token != nil && !s.source.token.Expiry.IsZero() {
+		expiry := s.source.token.Expiry
+		status.Expiry = &expiry
+	}
+
+	if !s.source.lastRefresh.IsZero() {
+		lastRefresh := s.source.lastRefresh
+		status.LastRefresh = &lastRefresh
+	}
+
+	if s.source.lastError != nil {
+		lastErrorAt := s.source.lastErrorAt
+		status.LastError = s.source.lastError.Error()
+		status.LastErrorAt = &lastErrorAt
+	}
+
+	return status
 }
 
 func (r *Registry) Start(wg *sync.WaitGroup) {
@@ -116,13 +226,8 @@ func (r *Registry) Start(wg *sync.WaitGroup) {
 	}()
 }
 
-func (r *Registry) removeClientID(clientID string) {
-	if _, ok := r.clientIDs[clientID]; !ok {
-		return
-	}
-
-	r.clientIDs[clientID].Cancel()
-	delete(r.clientIDs, clientID)
+func (r *Registry) removeService(key string) {
+	delete(r.services, key)
 }
 
 func (r *Registry) clearTimer() {
@@ -130,11 +235,9 @@ func (r *Registry) clearTimer() {
 	defer r.mutex.Unlock()
 
 	for _, timer := range r.timers {
-		// log.Debug().Msgf("checking timer for client id: %s", timer.ClientID)
 		select {
 		case <-timer.Timer.C:
-			// log.Debug().Msgf("clear timer for client id: %s", timer.ClientID)
-			r.removeClientID(timer.ClientID)
+			r.removeService(timer.Key)
 			if !timer.Timer.Stop() {
 				select {
 				case <-timer.Timer.C:
@@ -144,20 +247,23 @@ func (r *Registry) clearTimer() {
 
 			timer.Timer.C = nil
 
-			delete(r.timers, timer.ClientID)
+			delete(r.timers, timer.Key)
 		default:
 		}
 	}
 }
 
-func (r *Registry) resetTimer(clientID string) {
-	if _, ok := r.timers[clientID]; !ok {
+func (r *Registry) resetTimer(key string) {
+	if _, ok := r.timers[key]; !ok {
 		return
 	}
 
-	if !r.timers[clientID].Timer.Stop() {
-		<-r.timers[clientID].Timer.C
+	if !r.timers[key].Timer.Stop() {
+		select {
+		case <-r.timers[key].Timer.C:
+		default:
+		}
 	}
 
-	r.timers[clientID].Timer.Reset(DefaultTimeDuration)
+	r.timers[key].Timer.Reset(DefaultTimeDuration)
 }
@@ -3,12 +3,18 @@ package request
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"runtime"
 	"strconv"
+	"time"
 
 	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 	"github.com/worldline-go/klient"
 	"github.com/worldline-go/logz"
 )
@@ -19,6 +25,25 @@ type Retry struct {
 	EnabledStatusCodes  []int
 }
 
+var ErrResponseTooLarge = errors.New("response too large")
+
+// DefaultConnectTimeout used when call has no connect timeout.
+var DefaultConnectTimeout = 30 * time.Second
+
+type ctxRequest string
+
+const ctxConnectTimeout ctxRequest = "connect_timeout"
+
+// CallOptions limits one call, zero values mean no limit.
+type CallOptions struct {
+	// Timeout is total time of the call including retries and reading body.
+	Timeout time.Duration
+	// ConnectTimeout is time to establish connection.
+	ConnectTimeout time.Duration
+	// MaxResponseBytes is maximum size of the response body.
+	MaxResponseBytes int64
+}
+
 type ClientResponse struct {
 	Header     http.Header
 	Body       []byte
@@ -26,27 +51,74 @@ type ClientResponse struct {
 }
 
 type Client struct {
-	klient *klient.Client
+	klient   *klient.Client
+	breaker  BreakerConfig
+	cache    CacheConfig
+	identity string
 }
 
 type Config struct {
 	SkipVerify bool
+	TLS        *TLSConfig
 	Proxy      string
 	Log        *zerolog.Logger
 	Retry      Retry
 	Auth       AuthConfig
+	Profile    AuthProfile
+	Breaker    BreakerConfig
+	Cache      CacheConfig
 }
 
+// BreakerConfig enables shared circuit breaker of the upstream.
+type BreakerConfig struct {
+	Enabled bool
+	// Name of the breaker, default is host of the url.
+	Name string
+}
+
+// AuthConfig is oauth2 config, registry shares tokens of the same config.
 type AuthConfig struct {
-	Enabled      bool     `json:"enabled"`
+	Enabled bool `json:"enabled"`
+	// Name of the settings, not part of the config.
+	Name string `json:"-"`
+	// GrantType is client_credentials (default), password, refresh_token or jwt_bearer.
+	GrantType    string   `json:"grant_type"`
 	ClientID     string   `json:"client_id"`
 	ClientSecret string   `json:"client_secret"`
 	TokenURL     string   `json:"token_url"`
 	Scopes       []string `json:"scopes"`
+	// Scope is space or comma separated scopes.
+	Scope        string `json:"scope"`
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	RefreshToken string `json:"refresh_token"`
+	// PrivateKey is PEM key to sign client assertion or jwt bearer grant.
+	PrivateKey string `json:"private_key"`
+	KeyID      string `json:"key_id"`
+	// Subject of the jwt bearer grant, default is client id.
+	Subject  string `json:"subject"`
+	Audience string `json:"audience"`
+	Resource string `json:"resource"`
+	// AuthStyle of the client secret is header (default) or params.
+	AuthStyle string `json:"auth_style"`
+	// Params are extra token request parameters.
+	Params map[string]string `json:"params"`
 }
 
 func NewClient(cfg Config) (*Client, error) {
-	options := []klient.OptionClientFn{klient.WithDisableBaseURLCheck(true)}
+	var tlsConfig *tls.Config
+
+	if cfg.TLS != nil {
+		var err error
+		if tlsConfig, err = cfg.TLS.Build(); err != nil {
+			return nil, err
+		}
+	}
+
+	options := []klient.OptionClientFn{
+		klient.WithDisableBaseURLCheck(true),
+		klient.WithHTTPClient(newHTTPClient(tlsConfig)),
+	}
 	if cfg.Log != nil {
 		options = append(options, klient.WithLogger(logz.AdapterKV{Log: *cfg.Log}))
 	}
@@ -81,6 +153,15 @@ func NewClient(cfg Config) (*Client, error) {
 		options = append(options, klient.WithRoundTripper(roundTripper))
 	}
 
+	profileRoundTripper, err := cfg.Profile.RoundTripper()
+	if err != nil {
+		return nil, err
+	}
+
+	if profileRoundTripper != nil {
+		options = append(options, klient.WithRoundTripper(profileRoundTripper))
+	}
+
 	if cfg.Proxy != "" {
 		options = append(options, klient.WithProxy(cfg.Proxy))
 	}
@@ -91,7 +172,10 @@ func NewClient(cfg Config) (*Client, error) {
 	}
 
 	return &Client{
-		klient: client,
+		klient:   client,
+		breaker:  cfg.Breaker,
+		cache:    cfg.Cache,
+		identity: clientIdentity(cfg),
 	}, nil
 }
 
@@ -101,37 +185,209 @@ func (c *Client) Call(
 	headers map[string]interface{},
 	payload []byte,
 ) (*ClientResponse, error) {
-	req, err := c.newRequest(ctx, url, method, headers, payload)
+	return c.CallBody(ctx, url, method, headers, Body{Raw: payload}, CallOptions{})
+}
+
+// CallBody sends request with encoded body based on the body mode.
+func (c *Client) CallBody(
+	ctx context.Context,
+	url, method string,
+	headers map[string]interface{},
+	body Body,
+	opts CallOptions,
+) (*ClientResponse, error) {
+	callerCtx := ctx
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+
+		defer cancel()
+	}
+
+	if opts.ConnectTimeout > 0 {
+		ctx = context.WithValue(ctx, ctxConnectTimeout, opts.ConnectTimeout)
+	}
+
+	req, err := c.newRequest(ctx, url, method, headers, body)
 	if err != nil {
 		return nil, err
 	}
 
+	var (
+		key    string
+		cached *CacheEntry
+	)
+
+	if c.cache.Enabled {
+		key = cacheKey(req, c.identity, c.cache.Headers, body)
+
+		if cached, err = GlobalCache.Get(ctx, key); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("request cache cannot get")
+		}
+
+		if cached != nil {
+			if cached.Fresh(time.Now()) {
+				cacheResult(ctx, CacheHit, url)
+
+				return cached.response(), nil
+			}
+
+			if !c.cache.Control || !cached.hasValidator() {
+				cached = nil
+			} else {
+				if cached.ETag != "" {
+					req.Header.Set("If-None-Match", cached.ETag)
+				}
+
+				if cached.LastModified != "" {
+					req.Header.Set("If-Modified-Since", cached.LastModified)
+				}
+			}
+		}
+	}
+
+	var breaker *Breaker
+
+	if c.breaker.Enabled {
+		name := c.breaker.Name
+		if name == "" {
+			name = req.URL.Host
+		}
+
+		breaker = GlobalBreakers.Get(name)
+		if err := breaker.Allow(); err != nil {
+			GlobalBreakers.Rejected(ctx, name)
+
+			return nil, err
+		}
+	}
+
 	resp, err := c.klient.HTTP.Do(req)
+
+	if breaker != nil {
+		if err != nil && callerCtx.Err() != nil {
+			// canceled or deadline of the caller, not a failure of the upstream
+			breaker.Cancel()
+		} else {
+			breaker.Done(err == nil && resp.StatusCode < http.StatusInternalServerError)
+		}
+	}
+
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 
 	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
 
-	return &ClientResponse{
-		Body:       body,
+	var bodyReader io.Reader = resp.Body
+	if opts.MaxResponseBytes > 0 {
+		bodyReader = io.LimitReader(resp.Body, opts.MaxResponseBytes+1)
+	}
+
+	respBody, err := io.ReadAll(bodyReader)
+	if err != nil {
+		err = fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if opts.MaxResponseBytes > 0 && int64(len(respBody)) > opts.MaxResponseBytes {
+		return nil, fmt.Errorf("%w; limit %d bytes", ErrResponseTooLarge, opts.MaxResponseBytes)
+	}
+
+	response := &ClientResponse{
+		Body:       respBody,
 		StatusCode: resp.StatusCode,
 		Header:     resp.Header,
-	}, err
+	}
+
+	if c.cache.Enabled && err == nil {
+		response = c.storeCache(ctx, key, url, cached, response)
+	}
+
+	return response, err
+}
+
+// storeCache saves the response and returns cached response when not modified.
+func (c *Client) storeCache(ctx context.Context, key, url string, cached *CacheEntry, response *ClientResponse) *ClientResponse {
+	result := CacheMiss
+
+	if cached != nil && response.StatusCode == http.StatusNotModified {
+		header := cached.Header.Clone()
+		for k, v := range response.Header {
+			if k != "Content-Length" {
+				header[k] = v
+			}
+		}
+
+		response = &ClientResponse{
+			Body:       cached.Body,
+			StatusCode: cached.StatusCode,
+			Header:     header,
+		}
+
+		result = CacheRevalidated
+	}
+
+	cacheResult(ctx, result, url)
+
+	if entry := newCacheEntry(c.cache, response); entry != nil {
+		if err := GlobalCache.Set(ctx, key, entry); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("request cache cannot set")
+		}
+	}
+
+	return response
+}
+
+// IsTimeout returns true if error is deadline or network timeout.
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// newHTTPClient returns pooled client which dial timeout can set per call.
+func newHTTPClient(tlsConfig *tls.Config) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert // standard transport
+	transport.MaxIdleConnsPerHost = runtime.GOMAXPROCS(0) + 1
+	transport.TLSClientConfig = tlsConfig
+
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialer := net.Dialer{
+			Timeout:   DefaultConnectTimeout,
+			KeepAlive: 30 * time.Second, //nolint:gomnd // same as default transport
+		}
+
+		if v, ok := ctx.Value(ctxConnectTimeout).(time.Duration); ok {
+			dialer.Timeout = v
+		}
+
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	return &http.Client{Transport: transport}
 }
 
 // NewRequest creates a new HTTP request with the given method, URL, and optional body.
 //
 //nolint:lll // clear
-func (c *Client) newRequest(ctx context.Context, url, method string, headers map[string]interface{}, payload []byte) (*http.Request, error) {
-	var body io.Reader
+func (c *Client) newRequest(ctx context.Context, url, method string, headers map[string]interface{}, body Body) (*http.Request, error) {
+	payload, contentType, err := body.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode body for [%s]: %w", url, err)
+	}
+
+	var bodyReader io.Reader
 
 	if payload != nil {
-		body = bytes.NewBuffer(payload)
+		bodyReader = bytes.NewBuffer(payload)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request for [%s]: %w", url, err)
 	}
@@ -140,6 +396,11 @@ func (c *Client) newRequest(ctx context.Context, url, method string, headers map
 		req.Header.Add(k, fmt.Sprint(v))
 	}
 
+	// multipart boundary always should match
+	if contentType != "" && (body.Mode == BodyMultipart || req.Header.Get("Content-Type") == "") {
+		req.Header.Set("Content-Type", contentType)
+	}
+
 	req.Header.Add("Content-Length", strconv.Itoa(len(payload)))
 
 	return req, nil
@@ -3,10 +3,12 @@ package request
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestClient_Send(t *testing.T) {
@@ -75,3 +77,72 @@ func TestClient_Send(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_CallBodyLimits(t *testing.T) {
+	c, err := NewClient(Config{})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+
+		_, _ = w.Write([]byte("0123456789"))
+	}))
+	defer srv.Close()
+
+	if _, err := c.CallBody(context.TODO(), srv.URL, http.MethodGet, nil, Body{}, CallOptions{MaxResponseBytes: 10}); err != nil {
+		t.Errorf("Client.CallBody() in limit error = %v", err)
+	}
+
+	if _, err := c.CallBody(context.TODO(), srv.URL, http.MethodGet, nil, Body{}, CallOptions{MaxResponseBytes: 5}); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("Client.CallBody() error = %v, want %v", err, ErrResponseTooLarge)
+	}
+
+	_, err = c.CallBody(context.TODO(), srv.URL+"/slow", http.MethodGet, nil, Body{}, CallOptions{Timeout: 50 * time.Millisecond})
+	if !IsTimeout(err) {
+		t.Errorf("Client.CallBody() error = %v, want timeout", err)
+	}
+}
+
+func TestClient_CallBodyBreakerCaller(t *testing.T) {
+	c, err := NewClient(Config{Breaker: BreakerConfig{Enabled: true, Name: "caller-test"}})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := c.CallBody(canceled, srv.URL, http.MethodGet, nil, Body{}, CallOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Client.CallBody() error = %v, want %v", err, context.Canceled)
+	}
+
+	deadline, cancelDeadline := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelDeadline()
+
+	if _, err := c.CallBody(deadline, srv.URL, http.MethodGet, nil, Body{}, CallOptions{}); !IsTimeout(err) {
+		t.Errorf("Client.CallBody() error = %v, want timeout", err)
+	}
+
+	b, _ := GlobalBreakers.Find("caller-test")
+	if got := b.State(); got.Requests != 0 || got.Failures != 0 {
+		t.Errorf("caller context counted on breaker, state = %+v", got)
+	}
+
+	// timeout of the call is slow upstream
+	if _, err := c.CallBody(context.Background(), srv.URL, http.MethodGet, nil, Body{}, CallOptions{Timeout: 20 * time.Millisecond}); !IsTimeout(err) {
+		t.Errorf("Client.CallBody() error = %v, want timeout", err)
+	}
+
+	if got := b.State(); got.Requests != 1 || got.Failures != 1 {
+		t.Errorf("call timeout not counted on breaker, state = %+v", got)
+	}
+}
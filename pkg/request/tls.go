@@ -0,0 +1,67 @@
+package request
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strings"
+)
+
+// TLSConfig is a tls settings profile, certificates are PEM encoded.
+type TLSConfig struct {
+	// CA bundle to verify servers, empty uses system pool.
+	CA string `json:"ca"`
+	// Cert and Key are client certificate pair for mutual TLS.
+	Cert string `json:"cert"`
+	Key  string `json:"key"`
+	// MinVersion is one of 1.0, 1.1, 1.2, 1.3.
+	MinVersion string `json:"min_version"`
+	ServerName string `json:"server_name"`
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Build returns tls config of the profile.
+func (t TLSConfig) Build() (*tls.Config, error) {
+	cfg := &tls.Config{ //nolint:gosec // min version set with profile
+		ServerName: t.ServerName,
+	}
+
+	if t.MinVersion != "" {
+		version, ok := tlsVersions[strings.TrimPrefix(strings.TrimSpace(t.MinVersion), "TLS")]
+		if !ok {
+			return nil, fmt.Errorf("tls min version %q not supported", t.MinVersion)
+		}
+
+		cfg.MinVersion = version
+	}
+
+	if strings.TrimSpace(t.CA) != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM([]byte(t.CA)) {
+			return nil, fmt.Errorf("tls ca bundle has no valid certificate")
+		}
+
+		cfg.RootCAs = pool
+	}
+
+	if t.Cert != "" || t.Key != "" {
+		cert, err := tls.X509KeyPair([]byte(t.Cert), []byte(t.Key))
+		if err != nil {
+			return nil, fmt.Errorf("tls client certificate: %w", err)
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
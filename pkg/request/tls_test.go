@@ -0,0 +1,62 @@
+package request
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_TLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.ServerName))
+	}))
+	defer srv.Close()
+
+	ca := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+
+	tests := []struct {
+		name          string
+		tls           *TLSConfig
+		wantClientErr bool
+		wantErr       bool
+	}{
+		{
+			name:    "unknown ca",
+			wantErr: true,
+		},
+		{
+			name: "custom ca",
+			tls:  &TLSConfig{CA: ca, MinVersion: "1.2", ServerName: "example.com"},
+		},
+		{
+			name:          "wrong min version",
+			tls:           &TLSConfig{MinVersion: "2.0"},
+			wantClientErr: true,
+		},
+		{
+			name:          "invalid client certificate",
+			tls:           &TLSConfig{CA: ca, Cert: "cert", Key: "key"},
+			wantClientErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(Config{TLS: tt.tls})
+			if (err != nil) != tt.wantClientErr {
+				t.Fatalf("NewClient() error = %v, wantErr %v", err, tt.wantClientErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			_, err = c.CallBody(context.TODO(), srv.URL, http.MethodGet, nil, Body{}, CallOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.CallBody() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/dop251/goja"
+	"github.com/worldline-go/chore/pkg/transfer"
 	"gopkg.in/yaml.v3"
 )
 
@@ -39,6 +40,26 @@ func sleep(ctx context.Context) interface{} {
 	}
 }
 
+// xmlToObject converts XML to object, attributes are "@name" and text is "#text".
+func xmlToObject(v []byte) interface{} {
+	m, err := transfer.XMLToData(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// toXML converts object with one root key to XML string.
+func toXML(v interface{}) string {
+	b, err := transfer.DataToXML(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(b)
+}
+
 func setValue(_ interface{}) {}
 
 type commands struct {
@@ -56,6 +77,14 @@ var commandList = []commands{
 		fn:   toString,
 		name: "toString",
 	},
+	{
+		fn:   xmlToObject,
+		name: "xmlToObject",
+	},
+	{
+		fn:   toXML,
+		name: "toXML",
+	},
 	{
 		fn:   setValue,
 		name: "setValue",
@@ -9,6 +9,7 @@ import (
 	"github.com/dop251/goja"
 	"github.com/rs/zerolog/log"
 	"github.com/worldline-go/chore/pkg/transfer"
+	"gorm.io/gorm"
 )
 
 var ErrThrow = errors.New("throw")
@@ -17,6 +18,7 @@ type Goja struct {
 	runtime   *goja.Runtime
 	DataName  string
 	functions map[string]interface{}
+	db        *gorm.DB
 }
 
 func NewGoja() Goja {
@@ -35,6 +37,11 @@ func (g *Goja) SetFunction(name string, fn interface{}) {
 	g.functions[name] = fn
 }
 
+// SetDB enables key-value store functions in the script.
+func (g *Goja) SetDB(db *gorm.DB) {
+	g.db = db
+}
+
 func (g *Goja) RunString(value string) (goja.Value, error) {
 	return g.runtime.RunString(value)
 }
@@ -54,6 +61,12 @@ func (g *Goja) RunScript(ctx context.Context, script string, inputs []interface{
 		return nil, err
 	}
 
+	if g.db != nil {
+		if err := setScriptFuncs(ctx, g.runtime, kvFuncs(ctx, g.db)); err != nil {
+			return nil, err
+		}
+	}
+
 	passValues := []goja.Value{}
 	for i := range inputs {
 		passValues = append(passValues, g.runtime.ToValue(inputs[i]))
@@ -52,6 +52,22 @@ func TestGoja_RunScript(t *testing.T) {
 			want:    []byte("hello"),
 			wantErr: false,
 		},
+		{
+			name: "xml functions",
+			args: args{
+				script: `
+				function main(v1) {
+					const o = xmlToObject(v1);
+					return toXML({order: {"@id": o.order["@id"], item: o.order.item.concat(["c"])}});
+				}
+				`,
+				inputs: []interface{}{
+					[]byte(`<order id="1"><item>a</item><item>b</item></order>`),
+				},
+			},
+			want:    []byte(`<order id="1"><item>a</item><item>b</item><item>c</item></order>`),
+			wantErr: false,
+		},
 		{
 			name: "reference error",
 			args: args{
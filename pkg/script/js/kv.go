@@ -0,0 +1,93 @@
+package js
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/worldline-go/chore/pkg/kv"
+	"github.com/worldline-go/chore/pkg/transfer"
+	"gorm.io/gorm"
+)
+
+// kvValue converts script value to stored text, objects stored as JSON.
+func kvValue(v interface{}) string {
+	return string(transfer.DataToBytes(v))
+}
+
+// kvMissing is true for not passed, undefined or null arguments.
+func kvMissing(v goja.Value) bool {
+	return v == nil || goja.IsUndefined(v) || goja.IsNull(v)
+}
+
+func kvTTL(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+
+	ttl, err := time.ParseDuration(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return ttl
+}
+
+// kvFuncs returns key-value store functions, values are parsed like toObject when reading.
+func kvFuncs(ctx context.Context, db *gorm.DB) map[string]interface{} {
+	return map[string]interface{}{
+		"kvGet": func(namespace, key string) interface{} {
+			record, err := kv.Get(ctx, db, namespace, key)
+			if errors.Is(err, kv.ErrNotFound) {
+				return nil
+			}
+
+			if err != nil {
+				panic(err)
+			}
+
+			return transfer.BytesToData([]byte(record.Value))
+		},
+		"kvSet": func(namespace, key string, value interface{}, ttl string) {
+			if err := kv.Set(ctx, db, namespace, key, kvValue(value), kvTTL(ttl)); err != nil {
+				panic(err)
+			}
+		},
+		"kvDelete": func(namespace, key string) bool {
+			deleted, err := kv.Delete(ctx, db, namespace, key)
+			if err != nil {
+				panic(err)
+			}
+
+			return deleted
+		},
+		"kvIncrement": func(namespace, key string, delta goja.Value, ttl string) int64 {
+			d := int64(1)
+			if !kvMissing(delta) {
+				d = delta.ToInteger()
+			}
+
+			value, err := kv.Increment(ctx, db, namespace, key, d, kvTTL(ttl))
+			if err != nil {
+				panic(err)
+			}
+
+			return value
+		},
+		"kvCompareAndSwap": func(namespace, key string, old goja.Value, value interface{}, ttl string) bool {
+			var oldValue *string
+			if !kvMissing(old) {
+				v := kvValue(old.Export())
+				oldValue = &v
+			}
+
+			swapped, err := kv.CompareAndSwap(ctx, db, namespace, key, oldValue, kvValue(value), kvTTL(ttl))
+			if err != nil {
+				panic(err)
+			}
+
+			return swapped
+		},
+	}
+}
@@ -0,0 +1,62 @@
+package js
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestGoja_KV(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectQuery(`SELECT \* FROM "kvs"`).
+		WithArgs("orders", "42", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"namespace", "key", "value"}).AddRow("orders", "42", `{"status":"paid"}`))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "kvs"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT \* FROM "kvs"`).
+		WillReturnRows(sqlmock.NewRows([]string{"namespace", "key", "value"}).AddRow("counters", "paid", "0"))
+	mock.ExpectExec(`UPDATE "kvs"`).
+		WithArgs(nil, "1", sqlmock.AnyArg(), "counters", "paid").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	runner := NewGoja()
+	runner.SetDB(db)
+
+	got, err := runner.RunScript(context.Background(), `
+	function main() {
+		const order = kvGet("orders", "42");
+		const count = kvIncrement("counters", order.status);
+		return order.status + ":" + count;
+	}
+	`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != "paid:1" {
+		t.Errorf("RunScript() = %s, want paid:1", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,259 @@
+package transfer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// XML conversion keys, attributes are prefixed and text of elements with attributes or children is in XMLText.
+const (
+	XMLAttrPrefix = "@"
+	XMLText       = "#text"
+)
+
+// IsXML reports data looks like an XML document.
+func IsXML(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+
+	return len(trimmed) > 1 && trimmed[0] == '<' && trimmed[len(trimmed)-1] == '>'
+}
+
+// XMLBytesToData converts XML documents with XMLToData, others with BytesToData.
+// BytesToData not checks XML, nodes expecting XML input use this one.
+func XMLBytesToData(data []byte) interface{} {
+	if IsXML(data) {
+		if v, err := XMLToData(data); err == nil {
+			return v
+		}
+	}
+
+	return BytesToData(data)
+}
+
+// XMLToData converts XML document to map with one root key.
+//
+// Prefixes of the names are kept as written like "soap:Envelope" and namespace declarations are attributes.
+// Repeated elements become a list, element without attributes and children is its text.
+func XMLToData(data []byte) (interface{}, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = true
+
+	type element struct {
+		name     string
+		value    map[string]interface{}
+		text     strings.Builder
+		children bool
+	}
+
+	var (
+		stack []*element
+		root  map[string]interface{}
+	)
+
+	for {
+		// raw tokens keep prefixes
+		token, err := decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("xml cannot parse: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			e := &element{name: xmlName(t.Name), value: make(map[string]interface{}, len(t.Attr))}
+			for _, attr := range t.Attr {
+				e.value[XMLAttrPrefix+xmlName(attr.Name)] = attr.Value
+			}
+
+			if len(stack) > 0 {
+				stack[len(stack)-1].children = true
+			} else if root != nil {
+				return nil, errors.New("xml has multiple root elements")
+			}
+
+			stack = append(stack, e)
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return nil, errors.New("xml has unexpected end element")
+			}
+
+			e := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			var value interface{}
+
+			text := strings.TrimSpace(e.text.String())
+
+			switch {
+			case len(e.value) == 0 && !e.children:
+				value = text
+			default:
+				if text != "" {
+					e.value[XMLText] = text
+				}
+
+				value = e.value
+			}
+
+			if len(stack) == 0 {
+				root = map[string]interface{}{e.name: value}
+
+				continue
+			}
+
+			parent := stack[len(stack)-1].value
+			if existing, ok := parent[e.name]; ok {
+				if list, ok := existing.([]interface{}); ok {
+					parent[e.name] = append(list, value)
+				} else {
+					parent[e.name] = []interface{}{existing, value}
+				}
+			} else {
+				parent[e.name] = value
+			}
+		}
+	}
+
+	if root == nil || len(stack) > 0 {
+		return nil, errors.New("xml has no complete root element")
+	}
+
+	return root, nil
+}
+
+func xmlName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+
+	return name.Space + ":" + name.Local
+}
+
+// DataToXML converts map with one root key to XML, reverse of XMLToData.
+// Keys are written in sorted order, attributes first.
+func DataToXML(data interface{}) ([]byte, error) {
+	m, ok := data.(map[string]interface{})
+	if !ok || len(m) != 1 {
+		return nil, errors.New("xml data should be an object with one root key")
+	}
+
+	var buf bytes.Buffer
+
+	for name, value := range m {
+		if err := writeXML(&buf, name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeXML(buf *bytes.Buffer, name string, value interface{}) error {
+	if strings.HasPrefix(name, XMLAttrPrefix) || name == XMLText || name == "" {
+		return fmt.Errorf("xml element name %q is not valid", name)
+	}
+
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if err := writeXML(buf, name, item); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		buf.WriteString("<" + name)
+
+		for _, k := range keys {
+			if strings.HasPrefix(k, XMLAttrPrefix) {
+				buf.WriteString(" " + strings.TrimPrefix(k, XMLAttrPrefix) + `="`)
+				xmlEscape(buf, xmlValue(v[k]))
+				buf.WriteString(`"`)
+			}
+		}
+
+		buf.WriteString(">")
+
+		if text, ok := v[XMLText]; ok {
+			xmlEscape(buf, xmlValue(text))
+		}
+
+		for _, k := range keys {
+			if strings.HasPrefix(k, XMLAttrPrefix) || k == XMLText {
+				continue
+			}
+
+			if err := writeXML(buf, k, v[k]); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteString("</" + name + ">")
+
+		return nil
+	default:
+		buf.WriteString("<" + name + ">")
+		xmlEscape(buf, xmlValue(v))
+		buf.WriteString("</" + name + ">")
+
+		return nil
+	}
+}
+
+func xmlValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+
+	return fmt.Sprint(v)
+}
+
+func xmlEscape(buf *bytes.Buffer, s string) {
+	_ = xml.EscapeText(buf, []byte(s))
+}
+
+// XMLEscape returns escaped text to use in XML content and attributes.
+func XMLEscape(s string) string {
+	var buf bytes.Buffer
+
+	xmlEscape(&buf, s)
+
+	return buf.String()
+}
+
+// XMLFuncs are template functions of the XML conversion.
+func XMLFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"fromXml": func(v interface{}) (interface{}, error) {
+			return XMLToData(DataToBytes(v))
+		},
+		"toXml": func(v interface{}) (string, error) {
+			b, err := DataToXML(v)
+
+			return string(b), err
+		},
+		"xmlEscape": func(v interface{}) string {
+			return XMLEscape(xmlValue(v))
+		},
+	}
+}
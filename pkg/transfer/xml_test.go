@@ -0,0 +1,122 @@
+package transfer
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestXMLToData(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name: "soap envelope",
+			data: `<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <m:GetPriceResponse xmlns:m="https://example.com/prices">
+      <m:Price currency="EUR">1.90</m:Price>
+      <m:Tag>a</m:Tag>
+      <m:Tag>b</m:Tag>
+      <m:Empty/>
+    </m:GetPriceResponse>
+  </soap:Body>
+</soap:Envelope>`,
+			want: map[string]interface{}{
+				"soap:Envelope": map[string]interface{}{
+					"@xmlns:soap": "http://schemas.xmlsoap.org/soap/envelope/",
+					"soap:Body": map[string]interface{}{
+						"m:GetPriceResponse": map[string]interface{}{
+							"@xmlns:m": "https://example.com/prices",
+							"m:Price": map[string]interface{}{
+								"@currency": "EUR",
+								"#text":     "1.90",
+							},
+							"m:Tag":   []interface{}{"a", "b"},
+							"m:Empty": "",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "not closed",
+			data:    `<a><b></a>`,
+			wantErr: true,
+		},
+		{
+			name:    "multiple roots",
+			data:    `<a/><b/>`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := XMLToData([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("XMLToData() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("XMLToData() = %v", diff)
+			}
+
+			if !tt.wantErr {
+				if diff := deep.Equal(XMLBytesToData([]byte(tt.data)), tt.want); diff != nil {
+					t.Errorf("XMLBytesToData() = %v", diff)
+				}
+
+				// shared decoder not converts XML
+				if _, ok := BytesToData([]byte(tt.data)).(map[string]interface{}); ok {
+					t.Errorf("BytesToData() converted XML")
+				}
+			}
+		})
+	}
+}
+
+func TestDataToXML(t *testing.T) {
+	data := map[string]interface{}{
+		"soap:Envelope": map[string]interface{}{
+			"@xmlns:soap": "http://schemas.xmlsoap.org/soap/envelope/",
+			"soap:Body": map[string]interface{}{
+				"Item": []interface{}{
+					map[string]interface{}{"@id": 1, "#text": "a & b"},
+					"c",
+				},
+				"Empty": nil,
+			},
+		},
+	}
+
+	want := `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">` +
+		`<soap:Body><Empty></Empty><Item id="1">a &amp; b</Item><Item>c</Item></soap:Body></soap:Envelope>`
+
+	got, err := DataToXML(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != want {
+		t.Errorf("DataToXML() = %s, want %s", got, want)
+	}
+
+	// round trip keeps structure, values become strings
+	back, err := XMLToData(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := back.(map[string]interface{})["soap:Envelope"].(map[string]interface{})["soap:Body"].(map[string]interface{})["Item"]; len(v.([]interface{})) != 2 {
+		t.Errorf("round trip Item = %v", v)
+	}
+
+	if _, err := DataToXML([]interface{}{"a"}); err == nil {
+		t.Errorf("DataToXML() list should fail")
+	}
+}